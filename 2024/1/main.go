@@ -1,7 +1,9 @@
-package main
+package day1
 
 import (
-	"bufio"
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
+	"context"
 	"fmt"
 	"os"
 	"sort"
@@ -9,83 +11,28 @@ import (
 	"strings"
 )
 
-func main() {
-	INPUT_FILE := os.Getenv("INPUT_FILE")
-	OUTPUT_FILE := os.Getenv("OUTPUT_FILE")
-	if INPUT_FILE == "" || OUTPUT_FILE == "" {
-		fmt.Println("INPUT_FILE and OUTPUT_FILE environment variables not set")
-		fmt.Println("Defaulting to input.txt and output.txt")
-		INPUT_FILE = "input.txt"
-		OUTPUT_FILE = "output.txt"
-	}
-
-	inputFile, err := os.Open(INPUT_FILE)
-	if err != nil {
-		fmt.Printf("Error opening %s: %v", INPUT_FILE, err)
-		return
-	}
-	defer inputFile.Close()
-
-	outputFile, err := os.Create(OUTPUT_FILE)
-	if err != nil {
-		fmt.Printf("Error creating %s: %v", OUTPUT_FILE, err)
-		return
-	}
-	defer outputFile.Close()
+var solution = registry.Register(registry.Solution{
+	Year:   2024,
+	Day:    1,
+	Solver: solver.Lines(parseLists, part1, part2),
+})
 
-	scanner := bufio.NewScanner(inputFile)
-	writer := bufio.NewWriter(outputFile)
-
-	lines := make([]string, 0)
-	for scanner.Scan() {
-		line := scanner.Text()
-		lines = append(lines, line)
-	}
+// lists holds the two lists of location IDs side by side.
+type lists struct {
+	left, right []int
+}
 
-	////////////////////////////////////////////////////////////////////
-	// Start Solution Logic  ///////////////////////////////////////////
-	////////////////////////////////////////////////////////////////////
+func parseLists(lines []string) (lists, error) {
 	leftList, rightList, err := ParseInput(lines)
-	if err != nil {
-		fmt.Println("Error parsing input:", err)
-		return
-	}
-
-	results, err := Solve1(leftList, rightList)
-	if err != nil {
-		fmt.Println("Error solving 1:", err)
-		return
-	}
-	results2, err := Solve2(leftList, rightList)
-	results = append(results, results2...)
-	if err != nil {
-		fmt.Println("Error solving 2:", err)
-		return
-	}
-	////////////////////////////////////////////////////////////////////
-	// End Solution Logic  /////////////////////////////////////////////
-	////////////////////////////////////////////////////////////////////
-
-	// Write the results to output.txt, one line per result
-	for i, res := range results {
-		_, err := writer.WriteString(res)
-		if err != nil {
-			fmt.Printf("error writing value to %s: %v", OUTPUT_FILE, err)
-			return
-		}
-		if i != len(results)-1 {
-			_, err = writer.WriteString("\n")
-			if err != nil {
-				fmt.Printf("error writing newline to %s: %v", OUTPUT_FILE, err)
-				return
-			}
-		}
-	}
+	return lists{leftList, rightList}, err
+}
 
-	// Flush the writer to ensure all data is written to output.txt
-	writer.Flush()
+func part1(ctx context.Context, l lists, _ int) ([]string, error) {
+	return Solve1(l.left, l.right)
+}
 
-	fmt.Printf("Successfully processed %s and created %s", INPUT_FILE, OUTPUT_FILE)
+func part2(ctx context.Context, l lists, _ int) ([]string, error) {
+	return Solve2(l.left, l.right)
 }
 
 func ParseInput(lines []string) ([]int, []int, error) {
@@ -1,6 +1,7 @@
-package main
+package day1
 
 import (
+	"2ajoyce/adventofcode/aoc/runner"
 	"os"
 	"strings"
 	"testing"
@@ -40,8 +41,10 @@ func TestMainWritesToFile(t *testing.T) {
 	// Set up the input data
 	SetUpTestInput(t, INPUT_FILE)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	// Read the content of output.txt
 	data, err := os.ReadFile(OUTPUT_FILE)
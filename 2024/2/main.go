@@ -1,91 +1,19 @@
-package main
+package day2
 
 import (
-	"bufio"
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
 )
 
-func main() {
-	INPUT_FILE := os.Getenv("INPUT_FILE")
-	OUTPUT_FILE := os.Getenv("OUTPUT_FILE")
-	if INPUT_FILE == "" || OUTPUT_FILE == "" {
-		fmt.Println("INPUT_FILE and OUTPUT_FILE environment variables not set")
-		fmt.Println("Defaulting to input.txt and output.txt")
-		INPUT_FILE = "input.txt"
-		OUTPUT_FILE = "output.txt"
-	}
-
-	inputFile, err := os.Open(INPUT_FILE)
-	if err != nil {
-		fmt.Printf("Error opening %s: %v", INPUT_FILE, err)
-		return
-	}
-	defer inputFile.Close()
-
-	outputFile, err := os.Create(OUTPUT_FILE)
-	if err != nil {
-		fmt.Printf("Error creating %s: %v", OUTPUT_FILE, err)
-		return
-	}
-	defer outputFile.Close()
-
-	scanner := bufio.NewScanner(inputFile)
-	writer := bufio.NewWriter(outputFile)
-
-	lines := make([]string, 0)
-	for scanner.Scan() {
-		line := scanner.Text()
-		lines = append(lines, line)
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// Start Solution Logic  ///////////////////////////////////////////
-	////////////////////////////////////////////////////////////////////
-	reports, err := ParseInput(lines)
-	if err != nil {
-		fmt.Println("Error parsing input:", err)
-		return
-	}
-
-	results, err := Solve1(reports)
-	if err != nil {
-		fmt.Println("Error solving 1:", err)
-		return
-	}
-	results2, err := Solve2(reports)
-	results = append(results, results2...)
-	if err != nil {
-		fmt.Println("Error solving 2:", err)
-		return
-	}
-	////////////////////////////////////////////////////////////////////
-	// End Solution Logic  /////////////////////////////////////////////
-	////////////////////////////////////////////////////////////////////
-
-	// Write the results to output.txt, one line per result
-	for i, res := range results {
-		_, err := writer.WriteString(res)
-		if err != nil {
-			fmt.Printf("error writing value to %s: %v", OUTPUT_FILE, err)
-			return
-		}
-		if i != len(results)-1 {
-			_, err = writer.WriteString("\n")
-			if err != nil {
-				fmt.Printf("error writing newline to %s: %v", OUTPUT_FILE, err)
-				return
-			}
-		}
-	}
-
-	// Flush the writer to ensure all data is written to output.txt
-	writer.Flush()
-
-	fmt.Printf("Successfully processed %s and created %s", INPUT_FILE, OUTPUT_FILE)
-}
+var solution = registry.Register(registry.Solution{
+	Year:   2024,
+	Day:    2,
+	Solver: solver.Lines(ParseInput, solver.Serial(Solve1), solver.Serial(Solve2)),
+})
 
 func ParseInput(lines []string) ([][]int, error) {
 	DEBUG := os.Getenv("DEBUG")
@@ -1,7 +1,8 @@
-package main
+package day3
 
 import (
-	"bufio"
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
 	"fmt"
 	"os"
 	"regexp"
@@ -9,73 +10,11 @@ import (
 	"strings"
 )
 
-func main() {
-	//os.Setenv("DEBUG", "true")
-	INPUT_FILE := os.Getenv("INPUT_FILE")
-	OUTPUT_FILE := os.Getenv("OUTPUT_FILE")
-
-	if INPUT_FILE == "" || OUTPUT_FILE == "" {
-		fmt.Println("INPUT_FILE and OUTPUT_FILE environment variables not set")
-		fmt.Println("Defaulting to input.txt and output.txt")
-		INPUT_FILE = "input.txt"
-		OUTPUT_FILE = "output.txt"
-	}
-
-	lines, err := ReadInput(INPUT_FILE)
-	if err != nil {
-		fmt.Printf("Error reading from %s: %v", INPUT_FILE, err)
-		return
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// Start Solution Logic  ///////////////////////////////////////////
-	////////////////////////////////////////////////////////////////////
-	equations, err := ParseInput(lines)
-	if err != nil {
-		fmt.Println("Error parsing input:", err)
-		return
-	}
-
-	results, err := Solve1(equations)
-	if err != nil {
-		fmt.Println("Error solving 1:", err)
-		return
-	}
-	results2, err := Solve2(equations)
-	results = append(results, results2...)
-	if err != nil {
-		fmt.Println("Error solving 2:", err)
-		return
-	}
-	////////////////////////////////////////////////////////////////////
-	// End Solution Logic  /////////////////////////////////////////////
-	////////////////////////////////////////////////////////////////////
-
-	err = WriteOutput(OUTPUT_FILE, results)
-	if err != nil {
-		fmt.Printf("Error writing to %s: %v", OUTPUT_FILE, err)
-		return
-	}
-
-	fmt.Printf("Successfully processed %s and created %s", INPUT_FILE, OUTPUT_FILE)
-}
-
-func ReadInput(INPUT_FILE string) ([]string, error) {
-	inputFile, err := os.Open(INPUT_FILE)
-	if err != nil {
-		return nil, fmt.Errorf("error opening %s: %v", INPUT_FILE, err)
-	}
-	defer inputFile.Close()
-
-	scanner := bufio.NewScanner(inputFile)
-
-	lines := make([]string, 0)
-	for scanner.Scan() {
-		line := scanner.Text()
-		lines = append(lines, line)
-	}
-	return lines, nil
-}
+var solution = registry.Register(registry.Solution{
+	Year:   2024,
+	Day:    3,
+	Solver: solver.Lines(ParseInput, solver.Serial(Solve1), solver.Serial(Solve2)),
+})
 
 func ParseInput(lines []string) ([]string, error) {
 	DEBUG := os.Getenv("DEBUG")
@@ -103,33 +42,6 @@ func ParseInput(lines []string) ([]string, error) {
 	return equations, nil
 }
 
-func WriteOutput(OUTPUT_FILE string, results []string) error {
-	outputFile, err := os.Create(OUTPUT_FILE)
-	if err != nil {
-		return fmt.Errorf("error creating %s: %v", OUTPUT_FILE, err)
-	}
-	defer outputFile.Close()
-	writer := bufio.NewWriter(outputFile)
-
-	// Write the results to output.txt, one line per result
-	for i, res := range results {
-		_, err := writer.WriteString(res)
-		if err != nil {
-			return fmt.Errorf("error writing value to %s: %v", OUTPUT_FILE, err)
-		}
-		if i != len(results)-1 {
-			_, err = writer.WriteString("\n")
-			if err != nil {
-				return fmt.Errorf("error writing newline to %s: %v", OUTPUT_FILE, err)
-			}
-		}
-	}
-
-	// Flush the writer to ensure all data is written to output.txt
-	writer.Flush()
-	return nil
-}
-
 func Solve1(equations []string) ([]string, error) {
 	DEBUG := os.Getenv("DEBUG")
 	results := []string{}
@@ -1,7 +1,9 @@
-package main
+package day4
 
 import (
-	"bufio"
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
+	"context"
 	"fmt"
 	"os"
 	"regexp"
@@ -9,108 +11,34 @@ import (
 	"strings"
 )
 
-func ReadInput(INPUT_FILE string) ([]string, error) {
-	inputFile, err := os.Open(INPUT_FILE)
-	if err != nil {
-		return nil, fmt.Errorf("error opening %s: %v", INPUT_FILE, err)
-	}
-	defer inputFile.Close()
+var solution = registry.Register(registry.Solution{
+	Year:   2024,
+	Day:    4,
+	Solver: solver.Lines(parseGrid, part1, part2),
+})
 
-	scanner := bufio.NewScanner(inputFile)
-
-	lines := make([]string, 0)
-	for scanner.Scan() {
-		line := scanner.Text()
-		lines = append(lines, line)
-	}
+// parseGrid keeps the lines as they are, each part searches them for its own letter.
+func parseGrid(lines []string) ([]string, error) {
 	return lines, nil
 }
 
-func WriteOutput(OUTPUT_FILE string, results []string) error {
-	outputFile, err := os.Create(OUTPUT_FILE)
-	if err != nil {
-		return fmt.Errorf("error creating %s: %v", OUTPUT_FILE, err)
-	}
-	defer outputFile.Close()
-	writer := bufio.NewWriter(outputFile)
-
-	// Write the results to output.txt, one line per result
-	for i, res := range results {
-		_, err := writer.WriteString(res)
-		if err != nil {
-			return fmt.Errorf("error writing value to %s: %v", OUTPUT_FILE, err)
-		}
-		if i != len(results)-1 {
-			_, err = writer.WriteString("\n")
-			if err != nil {
-				return fmt.Errorf("error writing newline to %s: %v", OUTPUT_FILE, err)
-			}
-		}
-	}
-
-	// Flush the writer to ensure all data is written to output.txt
-	writer.Flush()
-	return nil
-}
-
-func main() {
-	//os.Setenv("DEBUG", "true")
-	INPUT_FILE := os.Getenv("INPUT_FILE")
-	OUTPUT_FILE := os.Getenv("OUTPUT_FILE")
-
-	if INPUT_FILE == "" || OUTPUT_FILE == "" {
-		fmt.Println("INPUT_FILE and OUTPUT_FILE environment variables not set")
-		fmt.Println("Defaulting to input.txt and output.txt")
-		INPUT_FILE = "input.txt"
-		OUTPUT_FILE = "output.txt"
-	}
-
-	lines, err := ReadInput(INPUT_FILE)
-	if err != nil {
-		fmt.Printf("Error reading from %s: %v", INPUT_FILE, err)
-		return
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// Start Solution Logic  ///////////////////////////////////////////
-	////////////////////////////////////////////////////////////////////
-
+func part1(ctx context.Context, lines []string, _ int) ([]string, error) {
 	// Create an array of all coordinates containing the letter X
 	startingCoords, err := FindLetter(lines, 'X')
 	if err != nil {
-		fmt.Println("Error parsing input:", err)
-		return
+		return nil, err
 	}
 	// Given starting coordinates, determine how many possible matching words exist
-	results, err := Solve1(lines, startingCoords)
-	if err != nil {
-		fmt.Println("Error solving 1:", err)
-		return
-	}
+	return Solve1(lines, startingCoords)
+}
 
+func part2(ctx context.Context, lines []string, _ int) ([]string, error) {
 	// Create an array of all coordinates containing the letter A
-	startingCoords, err = FindLetter(lines, 'A')
-	if err != nil {
-		fmt.Println("Error parsing input:", err)
-		return
-	}
-	results2, err := Solve2(lines, startingCoords)
-	results = append(results, results2...)
+	startingCoords, err := FindLetter(lines, 'A')
 	if err != nil {
-		fmt.Println("Error solving 2:", err)
-		return
+		return nil, err
 	}
-	////////////////////////////////////////////////////////////////////
-	// End Solution Logic  /////////////////////////////////////////////
-	////////////////////////////////////////////////////////////////////
-
-	err = WriteOutput(OUTPUT_FILE, results)
-	if err != nil {
-		fmt.Printf("Error writing to %s: %v", OUTPUT_FILE, err)
-		return
-	}
-
-	fmt.Printf("Successfully processed %s and created %s", INPUT_FILE, OUTPUT_FILE)
+	return Solve2(lines, startingCoords)
 }
 
 func FindLetter(lines []string, letter byte) ([][2]int, error) {
@@ -1,3 +1,3 @@
-module 1
+module 2ajoyce/adventofcode/2024/5
 
 go 1.23.2
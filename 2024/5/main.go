@@ -1,108 +1,38 @@
-package main
+package day5
 
 import (
-	"bufio"
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
+	"context"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
 )
 
-func ReadInput(INPUT_FILE string) ([]string, error) {
-	inputFile, err := os.Open(INPUT_FILE)
-	if err != nil {
-		return nil, fmt.Errorf("error opening %s: %v", INPUT_FILE, err)
-	}
-	defer inputFile.Close()
-
-	scanner := bufio.NewScanner(inputFile)
-
-	lines := make([]string, 0)
-	for scanner.Scan() {
-		line := scanner.Text()
-		lines = append(lines, line)
-	}
-	return lines, nil
-}
-
-func WriteOutput(OUTPUT_FILE string, results []string) error {
-	outputFile, err := os.Create(OUTPUT_FILE)
-	if err != nil {
-		return fmt.Errorf("error creating %s: %v", OUTPUT_FILE, err)
-	}
-	defer outputFile.Close()
-	writer := bufio.NewWriter(outputFile)
-
-	// Write the results to output.txt, one line per result
-	for i, res := range results {
-		_, err := writer.WriteString(res)
-		if err != nil {
-			return fmt.Errorf("error writing value to %s: %v", OUTPUT_FILE, err)
-		}
-		if i != len(results)-1 {
-			_, err = writer.WriteString("\n")
-			if err != nil {
-				return fmt.Errorf("error writing newline to %s: %v", OUTPUT_FILE, err)
-			}
-		}
-	}
+var solution = registry.Register(registry.Solution{
+	Year:   2024,
+	Day:    5,
+	Solver: solver.Lines(parseManual, part1, part2),
+})
 
-	// Flush the writer to ensure all data is written to output.txt
-	writer.Flush()
-	return nil
+// manual holds the page ordering rules and the updates to check against them.
+type manual struct {
+	rules   map[int][]int
+	updates [][]int
 }
 
-func main() {
-	//os.Setenv("DEBUG", "true")
-	INPUT_FILE := os.Getenv("INPUT_FILE")
-	OUTPUT_FILE := os.Getenv("OUTPUT_FILE")
-
-	if INPUT_FILE == "" || OUTPUT_FILE == "" {
-		fmt.Println("INPUT_FILE and OUTPUT_FILE environment variables not set")
-		fmt.Println("Defaulting to input.txt and output.txt")
-		INPUT_FILE = "input.txt"
-		OUTPUT_FILE = "output.txt"
-	}
-
-	lines, err := ReadInput(INPUT_FILE)
-	if err != nil {
-		fmt.Printf("Error reading from %s: %v", INPUT_FILE, err)
-		return
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// Start Solution Logic  ///////////////////////////////////////////
-	////////////////////////////////////////////////////////////////////
-
-	// Create an array of all coordinates containing the letter X
+func parseManual(lines []string) (manual, error) {
 	rules, updates, err := ParseLines(lines)
-	if err != nil {
-		fmt.Println("Error parsing input:", err)
-		return
-	}
-	results, err := Solve1(rules, updates)
-	if err != nil {
-		fmt.Println("Error solving 1:", err)
-		return
-	}
-
-	results2, err := Solve2(rules, updates)
-	results = append(results, results2...)
-	if err != nil {
-		fmt.Println("Error solving 2:", err)
-		return
-	}
-	////////////////////////////////////////////////////////////////////
-	// End Solution Logic  /////////////////////////////////////////////
-	////////////////////////////////////////////////////////////////////
+	return manual{rules, updates}, err
+}
 
-	err = WriteOutput(OUTPUT_FILE, results)
-	if err != nil {
-		fmt.Printf("Error writing to %s: %v", OUTPUT_FILE, err)
-		return
-	}
+func part1(ctx context.Context, m manual, _ int) ([]string, error) {
+	return Solve1(m.rules, m.updates)
+}
 
-	fmt.Printf("Successfully processed %s and created %s", INPUT_FILE, OUTPUT_FILE)
+func part2(ctx context.Context, m manual, _ int) ([]string, error) {
+	return Solve2(m.rules, m.updates)
 }
 
 func ParseLines(lines []string) (map[int][]int, [][]int, error) {
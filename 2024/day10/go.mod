@@ -1,4 +1,4 @@
-module day10
+module 2ajoyce/adventofcode/2024/day10
 
 go 1.23.2
 
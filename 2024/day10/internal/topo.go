@@ -1,7 +1,7 @@
 package internal
 
 import (
-	"day10/internal/simulation"
+	simulation "2ajoyce/adventofcode/lib/simulation/xy"
 	"fmt"
 	"os"
 	"strconv"
@@ -38,9 +38,9 @@ func StringifySimulation(sim simulation.Simulation) (string, error) {
 			if err != nil {
 				return "", err
 			}
-			entityIds, err := cell.GetEntityIds()
-			if err != nil {
-				return "", err
+			entityIds := cell.GetEntityIds()
+			if len(entityIds) == 0 {
+				return "", simulation.CellEmptyError{}
 			}
 			entity, err := sim.GetEntity(entityIds[0]) // Fragile, but we're only expecting one entity per cell for now.
 			if err != nil {
@@ -1,6 +1,7 @@
-package main
+package day10
 
 import (
+	"2ajoyce/adventofcode/aoc/runner"
 	"fmt"
 	"os"
 	"strings"
@@ -44,8 +45,6 @@ func TestMain(t *testing.T) {
 	defer os.Unsetenv("INPUT_FILE")
 	os.Setenv("OUTPUT_FILE", OUTPUT_FILE)
 	defer os.Unsetenv("OUTPUT_FILE")
-	os.Setenv("PARALLELISM", "1")
-	defer os.Unsetenv("PARALLELISM")
 	os.Setenv("DEBUG", "true")
 	defer os.Unsetenv("DEBUG")
 
@@ -53,8 +52,10 @@ func TestMain(t *testing.T) {
 	total, inputData := SetUpFullTestInput(t, INPUT_FILE)
 	writeInputToFile(INPUT_FILE, inputData, t)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	// Read the content of output.txt
 	data, err := os.ReadFile(OUTPUT_FILE)
@@ -1,4 +1,4 @@
-module day11
+module 2ajoyce/adventofcode/2024/day11
 
 go 1.23.2
 
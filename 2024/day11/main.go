@@ -1,8 +1,10 @@
-package main
+package day11
 
 import (
-	"day11/internal"
-	"day11/internal/aocIo"
+	"2ajoyce/adventofcode/2024/day11/internal"
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
+	"context"
 	"errors"
 	"fmt"
 	"math/big"
@@ -16,64 +18,25 @@ import (
 	"github.com/gosuri/uilive"
 )
 
-func main() {
+var solution = registry.Register(registry.Solution{
+	Year:   2024,
+	Day:    11,
+	Solver: solver.Lines(parseStones, part1, nil),
+})
 
-	////////////////////////////////////////////////////////////////////
-	// ENVIRONMENT SETUP
-	////////////////////////////////////////////////////////////////////
-
-	//os.Setenv("DEBUG", "true")
-	INPUT_FILE := os.Getenv("INPUT_FILE")
-	OUTPUT_FILE := os.Getenv("OUTPUT_FILE")
-	PARALLELISM, err := strconv.Atoi(os.Getenv("PARALLELISM"))
-	if PARALLELISM < 1 || err != nil {
-		PARALLELISM = 1
-	}
-	fmt.Printf("PARALLELISM: %d\n\n", PARALLELISM)
-
-	if INPUT_FILE == "" || OUTPUT_FILE == "" {
-		fmt.Println("INPUT_FILE and OUTPUT_FILE environment variables not set")
-		fmt.Println("Defaulting to input.txt and output.txt")
-		INPUT_FILE = "input.txt"
-		OUTPUT_FILE = "output.txt"
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// READ INPUT FILE
-	////////////////////////////////////////////////////////////////////
-
-	lines, err := aocIo.ReadInput(INPUT_FILE)
-	if err != nil {
-		fmt.Printf("Error reading from %s: %v", INPUT_FILE, err)
-		return
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// SOLUTION LOGIC
-	////////////////////////////////////////////////////////////////////
-
-	blink, stones, err := parseLines(lines)
-	if err != nil {
-		fmt.Println("Error parsing input:", err)
-		return
-	}
-	results, err := solve1(blink, stones, PARALLELISM)
-	if err != nil {
-		fmt.Println("Error solving 1:", err)
-		return
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// WRITE OUTPUT FILE
-	////////////////////////////////////////////////////////////////////
+// stones holds the initial stones and how many times to blink at them.
+type stones struct {
+	blink  int
+	stones []internal.Stone
+}
 
-	err = aocIo.WriteOutput(OUTPUT_FILE, results)
-	if err != nil {
-		fmt.Printf("Error writing to %s: %v", OUTPUT_FILE, err)
-		return
-	}
+func parseStones(lines []string) (stones, error) {
+	blink, s, err := parseLines(lines)
+	return stones{blink, s}, err
+}
 
-	fmt.Printf("Successfully processed %s and created %s", INPUT_FILE, OUTPUT_FILE)
+func part1(ctx context.Context, s stones, parallelism int) ([]string, error) {
+	return solve1(s.blink, s.stones, parallelism)
 }
 
 func parseLines(lines []string) (int, []internal.Stone, error) {
@@ -1,6 +1,7 @@
-package main
+package day11
 
 import (
+	"2ajoyce/adventofcode/aoc/runner"
 	"fmt"
 	"os"
 	"strings"
@@ -34,8 +35,6 @@ func TestMainSmall(t *testing.T) {
 	defer os.Unsetenv("INPUT_FILE")
 	os.Setenv("OUTPUT_FILE", OUTPUT_FILE)
 	defer os.Unsetenv("OUTPUT_FILE")
-	os.Setenv("PARALLELISM", "1")
-	defer os.Unsetenv("PARALLELISM")
 	os.Setenv("DEBUG", "true")
 	defer os.Unsetenv("DEBUG")
 
@@ -44,8 +43,10 @@ func TestMainSmall(t *testing.T) {
 	total := 7
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Stones: %d", total)
 	validateOutput(t, INPUT_FILE, OUTPUT_FILE, expectedContent)
@@ -58,8 +59,6 @@ func TestMainMedium3(t *testing.T) {
 	defer os.Unsetenv("INPUT_FILE")
 	os.Setenv("OUTPUT_FILE", OUTPUT_FILE)
 	defer os.Unsetenv("OUTPUT_FILE")
-	os.Setenv("PARALLELISM", "1")
-	defer os.Unsetenv("PARALLELISM")
 	os.Setenv("DEBUG", "true")
 	defer os.Unsetenv("DEBUG")
 
@@ -68,8 +67,10 @@ func TestMainMedium3(t *testing.T) {
 	total := 5
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Stones: %d", total)
 	validateOutput(t, INPUT_FILE, OUTPUT_FILE, expectedContent)
@@ -82,8 +83,6 @@ func TestMainMedium6(t *testing.T) {
 	defer os.Unsetenv("INPUT_FILE")
 	os.Setenv("OUTPUT_FILE", OUTPUT_FILE)
 	defer os.Unsetenv("OUTPUT_FILE")
-	os.Setenv("PARALLELISM", "1")
-	defer os.Unsetenv("PARALLELISM")
 	os.Setenv("DEBUG", "true")
 	defer os.Unsetenv("DEBUG")
 
@@ -92,8 +91,10 @@ func TestMainMedium6(t *testing.T) {
 	total := 22
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Stones: %d", total)
 	validateOutput(t, INPUT_FILE, OUTPUT_FILE, expectedContent)
@@ -106,8 +107,6 @@ func TestMainMedium25(t *testing.T) {
 	defer os.Unsetenv("INPUT_FILE")
 	os.Setenv("OUTPUT_FILE", OUTPUT_FILE)
 	defer os.Unsetenv("OUTPUT_FILE")
-	os.Setenv("PARALLELISM", "1")
-	defer os.Unsetenv("PARALLELISM")
 	os.Setenv("DEBUG", "false")
 	defer os.Unsetenv("DEBUG")
 
@@ -116,8 +115,10 @@ func TestMainMedium25(t *testing.T) {
 	total := 55312
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Stones: %d", total)
 	validateOutput(t, INPUT_FILE, OUTPUT_FILE, expectedContent)
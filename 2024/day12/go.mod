@@ -1,4 +1,4 @@
-module day12
+module 2ajoyce/adventofcode/2024/day12
 
 go 1.23.2
 
@@ -1,73 +1,22 @@
-package main
+package day12
 
 import (
-	"day12/internal/aocUtils"
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
+	"2ajoyce/adventofcode/lib/grid"
+	"2ajoyce/adventofcode/lib/simulation"
+	"context"
 	"fmt"
 	"os"
 	"sort"
-	"strconv"
 	"strings"
 )
 
-func main() {
-
-	////////////////////////////////////////////////////////////////////
-	// ENVIRONMENT SETUP
-	////////////////////////////////////////////////////////////////////
-
-	//os.Setenv("DEBUG", "true")
-	INPUT_FILE := os.Getenv("INPUT_FILE")
-	OUTPUT_FILE := os.Getenv("OUTPUT_FILE")
-	PARALLELISM, err := strconv.Atoi(os.Getenv("PARALLELISM"))
-	if PARALLELISM < 1 || err != nil {
-		PARALLELISM = 1
-	}
-	fmt.Printf("PARALLELISM: %d\n\n", PARALLELISM)
-
-	if INPUT_FILE == "" || OUTPUT_FILE == "" {
-		fmt.Println("INPUT_FILE and OUTPUT_FILE environment variables not set")
-		fmt.Println("Defaulting to input.txt and output.txt")
-		INPUT_FILE = "input.txt"
-		OUTPUT_FILE = "output.txt"
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// READ INPUT FILE
-	////////////////////////////////////////////////////////////////////
-
-	lines, err := aocUtils.ReadInput(INPUT_FILE)
-	if err != nil {
-		fmt.Printf("Error reading from %s: %v", INPUT_FILE, err)
-		return
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// SOLUTION LOGIC
-	////////////////////////////////////////////////////////////////////
-
-	input, err := parseLines(lines)
-	if err != nil {
-		fmt.Println("Error parsing input:", err)
-		return
-	}
-	results, err := solve1(input, PARALLELISM)
-	if err != nil {
-		fmt.Println("Error solving 1:", err)
-		return
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// WRITE OUTPUT FILE
-	////////////////////////////////////////////////////////////////////
-
-	err = aocUtils.WriteOutput(OUTPUT_FILE, results)
-	if err != nil {
-		fmt.Printf("Error writing to %s: %v", OUTPUT_FILE, err)
-		return
-	}
-
-	fmt.Printf("Successfully processed %s and created %s", INPUT_FILE, OUTPUT_FILE)
-}
+var solution = registry.Register(registry.Solution{
+	Year:   2024,
+	Day:    12,
+	Solver: solver.Lines(parseLines, nil, solve1),
+})
 
 func parseLines(lines []string) (RegionMap, error) {
 	//DEBUG := os.Getenv("DEBUG") == "true"
@@ -77,42 +26,41 @@ func parseLines(lines []string) (RegionMap, error) {
 		return nil, fmt.Errorf("input is empty")
 	}
 
-	rm := make(RegionMap, len(lines))
+	trimmed := make([]string, len(lines))
 	for i, line := range lines {
-		rm[i] = strings.TrimSpace(line)
+		trimmed[i] = strings.TrimSpace(line)
+	}
+	rm, err := grid.ParseRunes(trimmed)
+	if err != nil {
+		return nil, err
 	}
 
-	fmt.Printf("Parsed map with height %d and width %d\n", len(rm), len(rm[0]))
+	fmt.Printf("Parsed map with height %d and width %d\n", rm.Height(), rm.Width())
 
 	return rm, nil
 }
 
-// Coordinate represents a point in the grid
-type Coordinate struct {
-	X, Y int
-}
-
-// RegionMap represents the grid
-type RegionMap []string
+// RegionMap represents the grid, each cell holds the plant growing there
+type RegionMap = *grid.Grid[rune]
 
 // UnionFind structure for region merging
 type UnionFind struct {
-	parent map[Coordinate]Coordinate
-	rank   map[Coordinate]int
-	size   map[Coordinate]int // To track the size (area) of each region
+	parent map[simulation.Coord]simulation.Coord
+	rank   map[simulation.Coord]int
+	size   map[simulation.Coord]int // To track the size (area) of each region
 }
 
 // Create a new UnionFind
 func NewUnionFind() *UnionFind {
 	return &UnionFind{
-		parent: make(map[Coordinate]Coordinate),
-		rank:   make(map[Coordinate]int),
-		size:   make(map[Coordinate]int),
+		parent: make(map[simulation.Coord]simulation.Coord),
+		rank:   make(map[simulation.Coord]int),
+		size:   make(map[simulation.Coord]int),
 	}
 }
 
 // Find the root of a coordinate
-func (uf *UnionFind) Find(coord Coordinate) Coordinate {
+func (uf *UnionFind) Find(coord simulation.Coord) simulation.Coord {
 	if uf.parent[coord] == coord {
 		return coord
 	}
@@ -121,7 +69,7 @@ func (uf *UnionFind) Find(coord Coordinate) Coordinate {
 }
 
 // Union two coordinates
-func (uf *UnionFind) Union(coord1, coord2 Coordinate) {
+func (uf *UnionFind) Union(coord1, coord2 simulation.Coord) {
 	root1 := uf.Find(coord1)
 	root2 := uf.Find(coord2)
 
@@ -143,60 +91,42 @@ func (uf *UnionFind) Union(coord1, coord2 Coordinate) {
 
 // Initialize the UnionFind structure for the grid
 func (uf *UnionFind) Initialize(grid RegionMap) {
-	for y := 0; y < len(grid); y++ {
-		for x := 0; x < len(grid[y]); x++ {
-			coord := Coordinate{X: x, Y: y}
-			uf.parent[coord] = coord
-			uf.rank[coord] = 0
-			uf.size[coord] = 1 // Initially, every cell is its own region
-		}
-	}
-}
-
-// Get neighbors of a coordinate
-func getNeighbors(coord Coordinate, grid RegionMap) []Coordinate {
-	dirs := []Coordinate{{0, 1}, {1, 0}, {0, -1}, {-1, 0}}
-	neighbors := []Coordinate{}
-
-	for _, d := range dirs {
-		nx, ny := coord.X+d.X, coord.Y+d.Y
-		if nx >= 0 && ny >= 0 && ny < len(grid) && nx < len(grid[ny]) {
-			neighbors = append(neighbors, Coordinate{X: nx, Y: ny})
-		}
+	for coord := range grid.All() {
+		uf.parent[coord] = coord
+		uf.rank[coord] = 0
+		uf.size[coord] = 1 // Initially, every cell is its own region
 	}
-
-	return neighbors
 }
 
 // Calculate the number of straight sides a region has
-func calculateNumberOfSides(regionRoot Coordinate, grid RegionMap, uf *UnionFind) int {
+func calculateNumberOfSides(regionRoot simulation.Coord, grid RegionMap, uf *UnionFind) int {
 	// Maps to store horizontal and vertical boundaries
 	horizontalLines := make(map[int][]int)      // key: y, value: list of x where horizontal boundary starts
 	verticalLines := make(map[int]map[int]bool) // key: x, value: set of y where vertical boundary starts
 
 	// Identify all boundary edges
-	for y := 0; y < len(grid); y++ {
-		for x := 0; x < len(grid[y]); x++ {
-			coord := Coordinate{X: x, Y: y}
+	for y := 0; y < grid.Height(); y++ {
+		for x := 0; x < grid.Width(); x++ {
+			coord := simulation.Coord{X: x, Y: y}
 			if uf.Find(coord) != regionRoot {
 				continue
 			}
 
 			// Check four directions for boundary edges
 			// Up
-			if y == 0 || uf.Find(Coordinate{X: x, Y: y - 1}) != regionRoot {
+			if y == 0 || uf.Find(simulation.Coord{X: x, Y: y - 1}) != regionRoot {
 				// Horizontal boundary at the top of the cell (y, x)
 				horizontalLines[y] = append(horizontalLines[y], x)
 			}
 
 			// Down
-			if y == len(grid)-1 || uf.Find(Coordinate{X: x, Y: y + 1}) != regionRoot {
+			if y == grid.Height()-1 || uf.Find(simulation.Coord{X: x, Y: y + 1}) != regionRoot {
 				// Horizontal boundary at the bottom of the cell (y+1, x)
 				horizontalLines[y+1] = append(horizontalLines[y+1], x)
 			}
 
 			// Left
-			if x == 0 || uf.Find(Coordinate{X: x - 1, Y: y}) != regionRoot {
+			if x == 0 || uf.Find(simulation.Coord{X: x - 1, Y: y}) != regionRoot {
 				// Vertical boundary on the left of the cell (x, y)
 				if verticalLines[x] == nil {
 					verticalLines[x] = make(map[int]bool)
@@ -205,7 +135,7 @@ func calculateNumberOfSides(regionRoot Coordinate, grid RegionMap, uf *UnionFind
 			}
 
 			// Right
-			if x == len(grid[y])-1 || uf.Find(Coordinate{X: x + 1, Y: y}) != regionRoot {
+			if x == grid.Width()-1 || uf.Find(simulation.Coord{X: x + 1, Y: y}) != regionRoot {
 				// Vertical boundary on the right of the cell (x+1, y)
 				if verticalLines[x+1] == nil {
 					verticalLines[x+1] = make(map[int]bool)
@@ -302,7 +232,7 @@ func calculateNumberOfSides(regionRoot Coordinate, grid RegionMap, uf *UnionFind
 }
 
 // Solve function with expense calculation
-func solve1(rm RegionMap, parallelism int) ([]string, error) {
+func solve1(ctx context.Context, rm RegionMap, parallelism int) ([]string, error) {
 	DEBUG := os.Getenv("DEBUG") == "true"
 	var output = []string{}
 	var totalExpense = 0 // The sum of the expense of every region
@@ -311,22 +241,19 @@ func solve1(rm RegionMap, parallelism int) ([]string, error) {
 	uf.Initialize(rm)
 
 	// Merge regions based on adjacency and same value
-	for y := 0; y < len(rm); y++ {
-		for x := 0; x < len(rm[y]); x++ {
-			coord := Coordinate{X: x, Y: y}
-			for _, neighbor := range getNeighbors(coord, rm) {
-				if rm[coord.Y][coord.X] == rm[neighbor.Y][neighbor.X] {
-					uf.Union(coord, neighbor)
-				}
+	for coord, plant := range rm.All() {
+		for neighbor := range rm.Neighbors4(coord) {
+			if plant == rm.Get(neighbor) {
+				uf.Union(coord, neighbor)
 			}
 		}
 	}
 
 	// Calculate expenses for each region
-	visited := make(map[Coordinate]bool)
-	for y := 0; y < len(rm); y++ {
-		for x := 0; x < len(rm[y]); x++ {
-			coord := Coordinate{X: x, Y: y}
+	visited := make(map[simulation.Coord]bool)
+	for y := 0; y < rm.Height(); y++ {
+		for x := 0; x < rm.Width(); x++ {
+			coord := simulation.Coord{X: x, Y: y}
 			root := uf.Find(coord)
 
 			// Skip if already calculated
@@ -1,6 +1,7 @@
-package main
+package day12
 
 import (
+	"2ajoyce/adventofcode/aoc/runner"
 	"fmt"
 	"os"
 	"strings"
@@ -31,7 +32,6 @@ func TestMain(m *testing.M) {
 	// Set up environment variables here
 	os.Setenv("INPUT_FILE", INPUT_FILE)
 	os.Setenv("OUTPUT_FILE", OUTPUT_FILE)
-	os.Setenv("PARALLELISM", "1")
 	os.Setenv("DEBUG", "true")
 
 	// Run all tests
@@ -40,7 +40,6 @@ func TestMain(m *testing.M) {
 	// Clean up any resources if necessary
 	os.Unsetenv("INPUT_FILE")
 	os.Unsetenv("OUTPUT_FILE")
-	os.Unsetenv("PARALLELISM")
 	os.Unsetenv("DEBUG")
 
 	// Exit with the same status as `go test`
@@ -65,8 +64,10 @@ func TestMainSmall(t *testing.T) {
 	total := totalA + totalB + totalC + totalD + totalE
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Expense: %d", total)
 	validateOutput(t, expectedContent)
@@ -86,8 +87,10 @@ func TestMainMedium(t *testing.T) {
 	total := 436
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Expense: %d", total)
 	validateOutput(t, expectedContent)
@@ -107,8 +110,10 @@ func TestMainEShaped(t *testing.T) {
 	total := 236
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Expense: %d", total)
 	validateOutput(t, expectedContent)
@@ -129,8 +134,10 @@ func TestMainTouchingDiagonally(t *testing.T) {
 	total := 368
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Expense: %d", total)
 	validateOutput(t, expectedContent)
@@ -155,8 +162,10 @@ func TestMainLarge(t *testing.T) {
 	total := 1206
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Expense: %d", total)
 	validateOutput(t, expectedContent)
@@ -1,4 +1,4 @@
-module day13
+module 2ajoyce/adventofcode/2024/day13
 
 go 1.23.2
 
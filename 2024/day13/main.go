@@ -1,72 +1,20 @@
-package main
+package day13
 
 import (
-	"day13/internal/aocUtils"
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
+	"context"
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
+	"sync"
 )
 
-func main() {
-
-	////////////////////////////////////////////////////////////////////
-	// ENVIRONMENT SETUP
-	////////////////////////////////////////////////////////////////////
-
-	//os.Setenv("DEBUG", "true")
-	INPUT_FILE := os.Getenv("INPUT_FILE")
-	OUTPUT_FILE := os.Getenv("OUTPUT_FILE")
-	PARALLELISM, err := strconv.Atoi(os.Getenv("PARALLELISM"))
-	if PARALLELISM < 1 || err != nil {
-		PARALLELISM = 1
-	}
-	fmt.Printf("PARALLELISM: %d\n\n", PARALLELISM)
-
-	if INPUT_FILE == "" || OUTPUT_FILE == "" {
-		fmt.Println("INPUT_FILE and OUTPUT_FILE environment variables not set")
-		fmt.Println("Defaulting to input.txt and output.txt")
-		INPUT_FILE = "input.txt"
-		OUTPUT_FILE = "output.txt"
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// READ INPUT FILE
-	////////////////////////////////////////////////////////////////////
-
-	lines, err := aocUtils.ReadInput(INPUT_FILE)
-	if err != nil {
-		fmt.Printf("Error reading from %s: %v", INPUT_FILE, err)
-		return
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// SOLUTION LOGIC
-	////////////////////////////////////////////////////////////////////
-
-	input, err := parseLines(lines)
-	if err != nil {
-		fmt.Println("Error parsing input:", err)
-		return
-	}
-	results, err := solve1(input, PARALLELISM)
-	if err != nil {
-		fmt.Println("Error solving 1:", err)
-		return
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// WRITE OUTPUT FILE
-	////////////////////////////////////////////////////////////////////
-
-	err = aocUtils.WriteOutput(OUTPUT_FILE, results)
-	if err != nil {
-		fmt.Printf("Error writing to %s: %v", OUTPUT_FILE, err)
-		return
-	}
-
-	fmt.Printf("Successfully processed %s and created %s", INPUT_FILE, OUTPUT_FILE)
-}
+var solution = registry.Register(registry.Solution{
+	Year:   2024,
+	Day:    13,
+	Solver: solver.Lines(parseLines, nil, solve1),
+})
 
 type ButtonA = [2]int64
 type ButtonB = [2]int64
@@ -236,15 +184,22 @@ func solveDiophantine(ax, ay, bx, by, targetX, targetY int64) (nA, nB int64, win
 	return nA, nB, true
 }
 
-func solve1(machines []*Machine, parallelism int) ([]string, error) {
+func solve1(ctx context.Context, machines []*Machine, parallelism int) ([]string, error) {
 	output := []string{}
 	results := make(chan int64, len(machines))
 	semaphore := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
 
 	for i, m := range machines {
+		wg.Add(1)
 		go func(idx int64, machine *Machine) {
+			defer wg.Done()
 			var tokens = int64(0)
-			semaphore <- struct{}{} // Limit the number of goroutines
+			select {
+			case semaphore <- struct{}{}: // Limit the number of goroutines
+			case <-ctx.Done():
+				return
+			}
 
 			// Prize location coordinates
 			targetX, targetY := machine.PrizeLocation[0], machine.PrizeLocation[1]
@@ -271,9 +226,16 @@ func solve1(machines []*Machine, parallelism int) ([]string, error) {
 	// Collect results
 	var totalTokens = int64(0)
 	for i := 0; i < len(machines); i++ {
-		t := <-results
-		fmt.Printf("Result %d: Tokens = %d\n", i+1, t)
-		totalTokens += t
+		select {
+		case t := <-results:
+			fmt.Printf("Result %d: Tokens = %d\n", i+1, t)
+			totalTokens += t
+		case <-ctx.Done():
+			// Machines already being solved finish, the rest never start. Results
+			// is buffered for every machine, so none of them block sending theirs.
+			wg.Wait()
+			return nil, fmt.Errorf("solved %d of %d machines: %w", i, len(machines), ctx.Err())
+		}
 	}
 	output = append(output, fmt.Sprintf("Tokens: %d", totalTokens))
 	return output, nil
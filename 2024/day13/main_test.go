@@ -1,6 +1,7 @@
-package main
+package day13
 
 import (
+	"2ajoyce/adventofcode/aoc/runner"
 	"fmt"
 	"os"
 	"strings"
@@ -31,7 +32,6 @@ func TestMain(m *testing.M) {
 	// Set up environment variables here
 	os.Setenv("INPUT_FILE", INPUT_FILE)
 	os.Setenv("OUTPUT_FILE", OUTPUT_FILE)
-	os.Setenv("PARALLELISM", "1")
 	os.Setenv("DEBUG", "true")
 
 	// Run all tests
@@ -40,7 +40,6 @@ func TestMain(m *testing.M) {
 	// Clean up any resources if necessary
 	os.Unsetenv("INPUT_FILE")
 	os.Unsetenv("OUTPUT_FILE")
-	os.Unsetenv("PARALLELISM")
 	os.Unsetenv("DEBUG")
 
 	// Exit with the same status as `go test`
@@ -57,8 +56,10 @@ func TestMainFailure1(t *testing.T) {
 	total := 0
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Tokens: %d", total)
 	validateOutput(t, expectedContent)
@@ -74,8 +75,10 @@ func TestMainSuccess1(t *testing.T) {
 	total := 459236326669
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Tokens: %d", total)
 	validateOutput(t, expectedContent)
@@ -91,8 +94,10 @@ func TestMainFailure2(t *testing.T) {
 	total := 0
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Tokens: %d", total)
 	validateOutput(t, expectedContent)
@@ -108,8 +113,10 @@ func TestMainSuccess2(t *testing.T) {
 	total := 416082282239
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Tokens: %d", total)
 	validateOutput(t, expectedContent)
@@ -137,8 +144,10 @@ func TestMainMedium(t *testing.T) {
 	total := 875318608908
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Tokens: %d", total)
 	validateOutput(t, expectedContent)
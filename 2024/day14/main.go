@@ -1,9 +1,16 @@
-package main
+package day14
 
 import (
-	"day14/internal/aocUtils"
-	"day14/internal/simulation"
+	"2ajoyce/adventofcode/2024/day14/internal/aocUtils"
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
+	"2ajoyce/adventofcode/lib/playback"
+	"2ajoyce/adventofcode/lib/record"
+	core "2ajoyce/adventofcode/lib/simulation"
+	simulation "2ajoyce/adventofcode/lib/simulation/xy"
+	"context"
 	"fmt"
+	"image/color"
 	"os"
 	"strconv"
 	"strings"
@@ -11,85 +18,34 @@ import (
 	"github.com/schollz/progressbar/v3"
 )
 
-func main() {
+var solution = registry.Register(registry.Solution{
+	Year:   2024,
+	Day:    14,
+	Solver: solver.Lines(parseLines, solve1, nil),
+})
 
-	////////////////////////////////////////////////////////////////////
-	// ENVIRONMENT SETUP
-	////////////////////////////////////////////////////////////////////
-
-	//os.Setenv("DEBUG", "true")
-	INPUT_FILE := os.Getenv("INPUT_FILE")
-	OUTPUT_FILE := os.Getenv("OUTPUT_FILE")
-	PARALLELISM, err := strconv.Atoi(os.Getenv("PARALLELISM"))
-	if PARALLELISM < 1 || err != nil {
-		PARALLELISM = 1
-	}
-	fmt.Printf("PARALLELISM: %d\n\n", PARALLELISM)
-
-	if INPUT_FILE == "" || OUTPUT_FILE == "" {
-		fmt.Println("INPUT_FILE and OUTPUT_FILE environment variables not set")
-		fmt.Println("Defaulting to input.txt and output.txt")
-		INPUT_FILE = "input.txt"
-		OUTPUT_FILE = "output.txt"
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// READ INPUT FILE
-	////////////////////////////////////////////////////////////////////
-
-	lines, err := aocUtils.ReadInput(INPUT_FILE)
-	if err != nil {
-		fmt.Printf("Error reading from %s: %v", INPUT_FILE, err)
-		return
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// SOLUTION LOGIC
-	////////////////////////////////////////////////////////////////////
-
-	input, err := parseLines(lines)
-	if err != nil {
-		fmt.Println("Error parsing input:", err)
-		return
-	}
-	results, err := solve1(input, PARALLELISM)
-	if err != nil {
-		fmt.Println("Error solving 1:", err)
-		return
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// WRITE OUTPUT FILE
-	////////////////////////////////////////////////////////////////////
-
-	err = aocUtils.WriteOutput(OUTPUT_FILE, results)
-	if err != nil {
-		fmt.Printf("Error writing to %s: %v", OUTPUT_FILE, err)
-		return
-	}
-
-	fmt.Printf("Successfully processed %s and created %s", INPUT_FILE, OUTPUT_FILE)
+// Colors of the frames recorded with --record, robots are drawn as '#'
+var robotPalette = record.Palette{
+	Runes: map[rune]color.Color{'#': color.RGBA{G: 0xc0, A: 0xff}, ' ': color.Black},
 }
 
-func PrintSim(sim simulation.Simulation) string {
-	var output string
-	for y := 0; y < sim.GetMap().GetHeight(); y++ {
-		for x := 0; x < sim.GetMap().GetWidth(); x++ {
-			cell, err := sim.GetMap().GetCell(x, y)
-			if err != nil {
-				output += "?"
-				continue
-			}
-			ids := cell.GetEntityIds()
-			if len(ids) == 0 {
-				output += " "
-			} else {
-				output += "#"
-			}
+// recordTick adds a frame of the robots to the recording
+func recordTick(recorder *record.Recorder, sim simulation.Simulation) {
+	m := sim.GetMap()
+	recorder.Cells(m.GetWidth(), m.GetHeight(), &robotPalette, func(x, y int) rune {
+		cell, err := m.GetCell(x, y)
+		if err != nil || len(cell.GetEntityIds()) == 0 {
+			return ' '
 		}
-		output += "\n"
-	}
-	return output
+		return '#'
+	})
+}
+
+// Draws the robots as '#' for the safest tick visuals, and colors them for
+// the playback with --play
+var robotStyle = playback.Style{
+	Legend: core.MustNewLegend(' ', map[rune]string{'#': simulation.EntityType}),
+	Colors: map[string]string{simulation.EntityType: "32"},
 }
 
 func parseLines(lines []string) (simulation.Simulation, error) {
@@ -168,25 +124,16 @@ func calculateSafetyFactor(sim simulation.Simulation) int {
 	// To determine the safest area, count the number of robots in each quadrant
 	// Robots that are exactly in the middle (horizontally or vertically) don't count as being in any quadrant,
 
-	//1. Divide the simulation space into four quadrants.
+	//1. Divide the simulation space into four quadrants and count the robots in each.
 	height := sim.GetMap().GetHeight()
 	width := sim.GetMap().GetWidth()
-	quadrantCount := make(map[string]int)
-	for _, e := range sim.GetEntities() {
-		x, y := e.GetPosition()
-		if x < width/2 && y < height/2 {
-			quadrantCount["NW"]++
-		} else if x < width/2 && y > height/2 {
-			quadrantCount["NE"]++
-		} else if x > width/2 && y < height/2 {
-			quadrantCount["SW"]++
-		} else if x > width/2 && y > height/2 {
-			quadrantCount["SE"]++
-		}
-	}
+	quadrantCount := sim.CountPerRegion(core.Quadrants(width, height))
 
 	//2. Multiply the number of robots in each quadrant together.
-	safetyFactor := quadrantCount["NW"] * quadrantCount["NE"] * quadrantCount["SW"] * quadrantCount["SE"]
+	safetyFactor := 1
+	for _, count := range quadrantCount {
+		safetyFactor *= count
+	}
 	return safetyFactor
 }
 
@@ -196,27 +143,51 @@ type OutputRecord struct {
 	visual       string
 }
 
-func solve1(sim simulation.Simulation, parallelism int) ([]string, error) {
+func solve1(ctx context.Context, sim simulation.Simulation, parallelism int) ([]string, error) {
 	var output = []string{}
 	const maxTick = 10000
 	bar := progressbar.Default(maxTick)
 	var safestTick = 0
 	safetyMap := map[int]OutputRecord{}
 	var safetyFactor = 0
-
-	for tickNumber := 1; tickNumber <= maxTick; tickNumber++ {
-		// fmt.Printf("Tick %d\n%s", tickNumber, PrintSim(sim))
-		_, err := tick(sim, tickNumber)
-		if err != nil {
-			return nil, fmt.Errorf("error during tick %d: %s", tickNumber, err)
-		}
-		bar.Add(1)
-
-		sf := calculateSafetyFactor(sim)
-		if safetyFactor == 0 || sf <= safetyFactor {
-			safetyFactor = sf
-			visual := PrintSim(sim)
-			safetyMap[tickNumber] = OutputRecord{tick: tickNumber, safetyFactor: sf, visual: visual}
+	recorder := record.FromContext(ctx)
+	player := playback.FromContext(ctx)
+
+	// Each tick moves the robots, then looks at where they ended up
+	scheduler := core.NewScheduler(sim,
+		core.System[simulation.Simulation]{Name: "robots", Run: func(sim simulation.Simulation, tickNumber int) error {
+			_, err := tick(sim, tickNumber)
+			return err
+		}},
+		core.System[simulation.Simulation]{Name: "progress", Run: func(sim simulation.Simulation, tickNumber int) error {
+			bar.Add(1) // A progress bar that fails to draw should not fail the solve
+			return nil
+		}},
+		core.System[simulation.Simulation]{Name: "record", Run: func(sim simulation.Simulation, tickNumber int) error {
+			recordTick(recorder, sim)
+			return nil
+		}},
+		core.System[simulation.Simulation]{Name: "safety", Run: func(sim simulation.Simulation, tickNumber int) error {
+			sf := calculateSafetyFactor(sim)
+			if safetyFactor == 0 || sf <= safetyFactor {
+				safetyFactor = sf
+				visual := robotStyle.Legend.Render(sim.Unwrap())
+				safetyMap[tickNumber] = OutputRecord{tick: tickNumber, safetyFactor: sf, visual: visual}
+			}
+			return nil
+		}},
+	)
+	if player != nil {
+		scheduler.AddSystem(core.System[simulation.Simulation]{Name: "play", Run: func(sim simulation.Simulation, tickNumber int) error {
+			return player.Frame(sim.Unwrap(), &robotStyle, fmt.Sprintf("Tick %d/%d", tickNumber, maxTick))
+		}})
+	}
+	if err := scheduler.RunN(ctx, maxTick); err != nil {
+		return nil, err
+	}
+	if os.Getenv("DEBUG") == "true" {
+		for _, timing := range scheduler.Timings() {
+			fmt.Println(timing)
 		}
 	}
 	safetyFactor = calculateSafetyFactor(sim) // At the end, set the safety factor to the last frame
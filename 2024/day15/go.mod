@@ -1,4 +1,4 @@
-module day15
+module 2ajoyce/adventofcode/2024/day15
 
 go 1.23.2
 
@@ -1,6 +1,7 @@
-package main
+package day15
 
 import (
+	"2ajoyce/adventofcode/aoc/runner"
 	"fmt"
 	"os"
 	"strings"
@@ -31,7 +32,6 @@ func TestMain(m *testing.M) {
 	// Set up environment variables here
 	os.Setenv("INPUT_FILE", INPUT_FILE)
 	os.Setenv("OUTPUT_FILE", OUTPUT_FILE)
-	os.Setenv("PARALLELISM", "1")
 	os.Setenv("DEBUG", "true")
 
 	// Run all tests
@@ -40,7 +40,6 @@ func TestMain(m *testing.M) {
 	// Clean up any resources if necessary
 	os.Unsetenv("INPUT_FILE")
 	os.Unsetenv("OUTPUT_FILE")
-	os.Unsetenv("PARALLELISM")
 	os.Unsetenv("DEBUG")
 
 	// Exit with the same status as `go test`
@@ -73,8 +72,10 @@ func TestMainSmall(t *testing.T) {
 	const total = 1751
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Total: %d", total)
 	validateOutput(t, expectedContent)
@@ -99,8 +100,10 @@ func TestMainDoubleBox(t *testing.T) {
 	const total = 618
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Total: %d", total)
 	validateOutput(t, expectedContent)
@@ -145,8 +148,10 @@ func TestMainLarge(t *testing.T) {
 	const total = 9021
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Total: %d", total)
 	validateOutput(t, expectedContent)
@@ -166,8 +171,10 @@ func TestMainReddit1(t *testing.T) {
 	const total = 406
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Total: %d", total)
 	validateOutput(t, expectedContent)
@@ -188,8 +195,10 @@ func TestMainReddit2(t *testing.T) {
 	const total = 509
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Total: %d", total)
 	validateOutput(t, expectedContent)
@@ -210,8 +219,10 @@ func TestMainReddit3(t *testing.T) {
 	const total = 511
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Total: %d", total)
 	validateOutput(t, expectedContent)
@@ -233,8 +244,10 @@ func TestMainReddit4(t *testing.T) {
 	const total = 816
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Total: %d", total)
 	validateOutput(t, expectedContent)
@@ -261,8 +274,10 @@ func TestMainReddit5(t *testing.T) {
 	const total = 2339
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Total: %d", total)
 	validateOutput(t, expectedContent)
@@ -284,8 +299,10 @@ func TestMainReddit6(t *testing.T) {
 	const total = 1226
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Total: %d", total)
 	validateOutput(t, expectedContent)
@@ -308,8 +325,10 @@ func TestMainReddit7(t *testing.T) {
 	const total = 1420
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Total: %d", total)
 	validateOutput(t, expectedContent)
@@ -332,8 +351,10 @@ func TestMainReddit8(t *testing.T) {
 	const total = 1020
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Total: %d", total)
 	validateOutput(t, expectedContent)
@@ -355,8 +376,10 @@ func TestMainReddit9(t *testing.T) {
 	const total = 822
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Total: %d", total)
 	validateOutput(t, expectedContent)
@@ -380,8 +403,10 @@ func TestMainReddit10(t *testing.T) {
 	const total = 1216
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Total: %d", total)
 	validateOutput(t, expectedContent)
@@ -405,8 +430,10 @@ func TestMainReddit11(t *testing.T) {
 	const total = 2827
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Total: %d", total)
 	validateOutput(t, expectedContent)
@@ -429,8 +456,10 @@ func TestMainReddit12(t *testing.T) {
 	const total = 1751
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Total: %d", total)
 	validateOutput(t, expectedContent)
@@ -452,8 +481,10 @@ func TestMainReddit13(t *testing.T) {
 	const total = 618
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Total: %d", total)
 	validateOutput(t, expectedContent)
@@ -476,8 +507,10 @@ func TestMainReddit14(t *testing.T) {
 	const total = 1833
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Total: %d", total)
 	validateOutput(t, expectedContent)
@@ -1,4 +1,4 @@
-module day16
+module 2ajoyce/adventofcode/2024/day16
 
 go 1.23.2
 
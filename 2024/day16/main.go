@@ -1,81 +1,34 @@
-package main
+package day16
 
 import (
-	"day16/internal/aocUtils"
-	"day16/internal/simulation"
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
+	"2ajoyce/adventofcode/lib/simulation"
+	"context"
 	"errors"
 	"fmt"
 	"os"
-	"strconv"
-
-	"golang.org/x/exp/slices"
 )
 
-func main() {
-
-	////////////////////////////////////////////////////////////////////
-	// ENVIRONMENT SETUP
-	////////////////////////////////////////////////////////////////////
-
-	//os.Setenv("DEBUG", "true")
-	INPUT_FILE := os.Getenv("INPUT_FILE")
-	OUTPUT_FILE := os.Getenv("OUTPUT_FILE")
-	PARALLELISM, err := strconv.Atoi(os.Getenv("PARALLELISM"))
-	if PARALLELISM < 1 || err != nil {
-		PARALLELISM = 1
-	}
-	fmt.Printf("PARALLELISM: %d\n\n", PARALLELISM)
-
-	if INPUT_FILE == "" || OUTPUT_FILE == "" {
-		fmt.Println("INPUT_FILE and OUTPUT_FILE environment variables not set")
-		fmt.Println("Defaulting to input.txt and output.txt")
-		INPUT_FILE = "input.txt"
-		OUTPUT_FILE = "output.txt"
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// READ INPUT FILE
-	////////////////////////////////////////////////////////////////////
-
-	lines, err := aocUtils.ReadInput(INPUT_FILE)
-	if err != nil {
-		fmt.Printf("Error reading from %s: %v", INPUT_FILE, err)
-		return
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// SOLUTION LOGIC
-	////////////////////////////////////////////////////////////////////
-
-	input, err := parseLines(lines)
-	if err != nil {
-		fmt.Println("Error parsing input:", err)
-		return
-	}
-	results, err := solve(input, PARALLELISM)
-	if err != nil {
-		fmt.Println("Error solving 1:", err)
-		return
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// WRITE OUTPUT FILE
-	////////////////////////////////////////////////////////////////////
-
-	err = aocUtils.WriteOutput(OUTPUT_FILE, results)
-	if err != nil {
-		fmt.Printf("Error writing to %s: %v", OUTPUT_FILE, err)
-		return
-	}
-
-	fmt.Printf("Successfully processed %s and created %s\n", INPUT_FILE, OUTPUT_FILE)
-}
+var solution = registry.Register(registry.Solution{
+	Year:   2024,
+	Day:    16,
+	Solver: solver.Lines(parseLines, nil, solve),
+})
 
 const ReindeerEntityType = "@"
-const ObstacleEntityType = "#"
 const StartTileEntityType = "S"
 const EndTileEntityType = "E"
 
+// The walls of the maze never move, so they are terrain rather than entities
+const ObstacleTerrain byte = '#'
+
+var mazeLegend = simulation.MustNewLegend('.', map[rune]string{
+	'@': ReindeerEntityType,
+	'S': StartTileEntityType,
+	'E': EndTileEntityType,
+}, simulation.WithTerrainRune('#', ObstacleTerrain, true))
+
 func parseLines(lines []string) (simulation.Simulation, error) {
 	//DEBUG := os.Getenv("DEBUG") == "true"
 	fmt.Println("Parsing Input...")
@@ -84,57 +37,15 @@ func parseLines(lines []string) (simulation.Simulation, error) {
 		return nil, fmt.Errorf("input is empty")
 	}
 
-	width := len(lines[0])
-	height := len(lines)
-	sim := simulation.NewSimulation(width, height)
-	for y := 0; y < height; y++ {
-		for x := 0; x < width; x++ {
-			entityType := string(lines[y][x])
-			if entityType == "." {
-				continue
-			}
-			entity, err := simulation.NewEntity(entityType)
-			if err != nil {
-				return nil, fmt.Errorf("error creating entity of type %s: %v", entityType, err)
-			}
-			coords := []simulation.Coord{{X: x, Y: y}}
-			sim.AddEntity(entity, coords, simulation.North)
-		}
+	sim, err := mazeLegend.Parse(lines)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing maze: %v", err)
 	}
 
 	return sim, nil
 }
 
-func PrintSim(sim simulation.Simulation, path []simulation.Coord) string {
-	var output string
-	for y := 0; y < sim.GetMap().GetHeight(); y++ {
-		for x := 0; x < sim.GetMap().GetWidth(); x++ {
-			cell, err := sim.GetMap().GetCell(simulation.Coord{X: x, Y: y})
-			if err != nil {
-				output += "?"
-				continue
-			}
-			if slices.Contains(path, simulation.Coord{X: x, Y: y}) {
-				output += "O"
-				continue
-			}
-			ids := cell.GetEntityIds()
-			if len(ids) == 0 {
-				output += " "
-			} else {
-				entity, err := sim.GetEntity(ids[0])
-				if err != nil {
-					output += "?"
-				}
-				output += string(entity.GetEntityType())
-			}
-		}
-		output += "\n"
-	}
-	return output
-}
-
-func solve(sim simulation.Simulation, WORKER_COUNT int) ([]string, error) {
+func solve(ctx context.Context, sim simulation.Simulation, WORKER_COUNT int) ([]string, error) {
 	DEBUG := os.Getenv("DEBUG") == "true"
 	var output []string
 
@@ -162,28 +73,13 @@ func solve(sim simulation.Simulation, WORKER_COUNT int) ([]string, error) {
 	}
 
 	graph := make(map[simulation.Coord]map[simulation.Coord]float64)
-	// Create the graph to solve
+	// Create the graph to solve. GetNeighbors leaves out the walls, as they are
+	// blocked terrain.
 	for y := 0; y < height; y++ {
 		for x := 0; x < width; x++ {
 			neighborsMap := make(map[simulation.Coord]float64)
 			coord := simulation.Coord{X: x, Y: y}
 			for _, neighbor := range sim.GetMap().GetNeighbors(coord) {
-				if !sim.GetMap().ValidateCoord(neighbor) {
-					continue // Skip invalid coordinates
-				}
-				neighborCell, err := sim.GetMap().GetCell(neighbor)
-				if err != nil {
-					return nil, fmt.Errorf("error getting neighbor cell: %v", err)
-				}
-				if len(neighborCell.GetEntityIds()) > 0 {
-					neighborEntity, err := sim.GetEntity(neighborCell.GetEntityIds()[0])
-					if err != nil {
-						return nil, fmt.Errorf("error getting neighbor entity: %v", err)
-					}
-					if neighborEntity.GetEntityType() == ObstacleEntityType {
-						continue // Skip obstacles
-					}
-				}
 				neighborsMap[neighbor] = 0
 			}
 			graph[coord] = neighborsMap
@@ -201,7 +97,7 @@ func solve(sim simulation.Simulation, WORKER_COUNT int) ([]string, error) {
 				coords[i] = step.Node
 				totalNodes[step.Node] = true
 			}
-			fmt.Println(PrintSim(sim, coords))
+			fmt.Println(mazeLegend.Render(sim, simulation.PathMask(coords, 'O')))
 		}
 	}
 	fmt.Printf("Total Nodes:%d, Cost: %0.0f\n", len(totalNodes), cost)
@@ -219,26 +115,24 @@ func cost(prior, current, next simulation.Coord) float64 {
 
 	// If the direction is different from the previous direction, add 1000 to the cost
 	if priorDirection != nextDirection {
-		return 1000 + simulation.CostManhattan(current, next)
+		return 1000 + simulation.CostManhattan(prior, current, next)
 	}
 
-	return simulation.CostManhattan(current, next)
+	return simulation.CostManhattan(prior, current, next)
 }
 
 func findStart(sim simulation.Simulation) simulation.Entity {
-	for _, entity := range sim.GetEntities() {
-		if entity.GetEntityType() == StartTileEntityType {
-			return entity
-		}
+	tiles := sim.GetEntitiesOfType(StartTileEntityType)
+	if len(tiles) == 0 {
+		return nil
 	}
-	return nil
+	return tiles[0]
 }
 
 func findEnd(sim simulation.Simulation) simulation.Entity {
-	for _, entity := range sim.GetEntities() {
-		if entity.GetEntityType() == EndTileEntityType {
-			return entity
-		}
+	tiles := sim.GetEntitiesOfType(EndTileEntityType)
+	if len(tiles) == 0 {
+		return nil
 	}
-	return nil
+	return tiles[0]
 }
@@ -1,6 +1,7 @@
-package main
+package day16
 
 import (
+	"2ajoyce/adventofcode/aoc/runner"
 	"fmt"
 	"os"
 	"strings"
@@ -31,7 +32,6 @@ func TestMain(m *testing.M) {
 	// Set up environment variables here
 	os.Setenv("INPUT_FILE", INPUT_FILE)
 	os.Setenv("OUTPUT_FILE", OUTPUT_FILE)
-	os.Setenv("PARALLELISM", "1")
 	os.Setenv("DEBUG", "true")
 
 	// Run all tests
@@ -40,7 +40,6 @@ func TestMain(m *testing.M) {
 	// Clean up any resources if necessary
 	os.Unsetenv("INPUT_FILE")
 	os.Unsetenv("OUTPUT_FILE")
-	os.Unsetenv("PARALLELISM")
 	os.Unsetenv("DEBUG")
 
 	// Exit with the same status as `go test`
@@ -58,8 +57,10 @@ func TestMainTiny(t *testing.T) {
 	const total = 5
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Total: %d", total)
 	validateOutput(t, expectedContent)
@@ -86,8 +87,10 @@ func TestMainSmall1(t *testing.T) {
 	const total = 45
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Total: %d", total)
 	validateOutput(t, expectedContent)
@@ -116,8 +119,10 @@ func TestMainSmall2(t *testing.T) {
 	const total = 64
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Total: %d", total)
 	validateOutput(t, expectedContent)
@@ -156,8 +161,10 @@ func TestMainReddit1(t *testing.T) {
 	const total = 149
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Total: %d", total)
 	validateOutput(t, expectedContent)
@@ -184,8 +191,10 @@ func TestMainReddit2(t *testing.T) {
 	const total = 413
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Total: %d", total)
 	validateOutput(t, expectedContent)
@@ -208,8 +217,10 @@ func TestMainReddit3(t *testing.T) {
 	const total = 264
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Total: %d", total)
 	validateOutput(t, expectedContent)
@@ -232,8 +243,10 @@ func TestMainReddit4(t *testing.T) {
 	const total = 514
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Total: %d", total)
 	validateOutput(t, expectedContent)
@@ -252,8 +265,10 @@ func TestMainReddit5(t *testing.T) {
 	const total = 14
 	os.WriteFile(INPUT_FILE, []byte(strings.Join(inputData, "")), 0644)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	expectedContent := fmt.Sprintf("Total: %d", total)
 	validateOutput(t, expectedContent)
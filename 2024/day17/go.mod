@@ -1,4 +1,4 @@
-module day17
+module 2ajoyce/adventofcode/2024/day17
 
 go 1.23.4
 
@@ -1,8 +1,10 @@
-package main
+package day17
 
 import (
-	"day17/internal/aocUtils"
-	"day17/internal/day17"
+	"2ajoyce/adventofcode/2024/day17/internal/day17"
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
+	"context"
 	"fmt"
 	"math/big"
 	"os"
@@ -11,65 +13,11 @@ import (
 	"sync"
 )
 
-func main() {
-
-	////////////////////////////////////////////////////////////////////
-	// ENVIRONMENT SETUP
-	////////////////////////////////////////////////////////////////////
-
-	//os.Setenv("DEBUG", "true")
-	INPUT_FILE := os.Getenv("INPUT_FILE")
-	OUTPUT_FILE := os.Getenv("OUTPUT_FILE")
-	PARALLELISM, err := strconv.Atoi(os.Getenv("PARALLELISM"))
-	if PARALLELISM < 1 || err != nil {
-		PARALLELISM = 1
-	}
-	fmt.Printf("PARALLELISM: %d\n\n", PARALLELISM)
-
-	if INPUT_FILE == "" || OUTPUT_FILE == "" {
-		fmt.Println("INPUT_FILE and OUTPUT_FILE environment variables not set")
-		fmt.Println("Defaulting to input.txt and output.txt")
-		INPUT_FILE = "input.txt"
-		OUTPUT_FILE = "output.txt"
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// READ INPUT FILE
-	////////////////////////////////////////////////////////////////////
-
-	lines, err := aocUtils.ReadInput(INPUT_FILE)
-	if err != nil {
-		fmt.Printf("Error reading from %s: %v", INPUT_FILE, err)
-		return
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// SOLUTION LOGIC
-	////////////////////////////////////////////////////////////////////
-
-	input, err := parseLines(lines)
-	if err != nil {
-		fmt.Println("Error parsing input:", err)
-		return
-	}
-	results, err := solve(input)
-	if err != nil {
-		fmt.Println("Error solving 1:", err)
-		return
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// WRITE OUTPUT FILE
-	////////////////////////////////////////////////////////////////////
-
-	err = aocUtils.WriteToFile(OUTPUT_FILE, results)
-	if err != nil {
-		fmt.Printf("Error writing to %s: %v", OUTPUT_FILE, err)
-		return
-	}
-
-	fmt.Printf("Successfully processed %s and created %s\n", INPUT_FILE, OUTPUT_FILE)
-}
+var solution = registry.Register(registry.Solution{
+	Year:   2024,
+	Day:    17,
+	Solver: solver.Lines(parseLines, nil, solve),
+})
 
 func parseLines(lines []string) (*day17.Computer, error) {
 	DEBUG := os.Getenv("DEBUG") == "true"
@@ -140,9 +88,12 @@ func parseLines(lines []string) (*day17.Computer, error) {
 	return computer, nil
 }
 
-func solve(comp *day17.Computer) ([]string, error) {
+func solve(ctx context.Context, comp *day17.Computer, _ int) ([]string, error) {
 	fmt.Println("Beginning single-threaded solve")
-	results := findRangesBFS(comp.GetOpcodes())
+	results, err := findRangesBFS(ctx, comp.GetOpcodes())
+	if err != nil {
+		return nil, err
+	}
 	for i, r := range results {
 		fmt.Printf("Result %d: %s\n", i, r.String())
 	}
@@ -167,7 +118,8 @@ type Increment struct {
 	Output string
 }
 
-func findRanges(c *day17.Computer, r Range) []Range {
+// findRanges stops early, returning the ranges found so far, once ctx is done.
+func findRanges(ctx context.Context, c *day17.Computer, r Range) []Range {
 	DEBUG := os.Getenv("DEBUG") == "true"
 	eight := big.NewInt(8)
 	increment := big.NewInt(0).Exp(eight, big.NewInt(int64(r.Index-1)), nil)
@@ -180,7 +132,10 @@ func findRanges(c *day17.Computer, r Range) []Range {
 	for i := r.End; i.Cmp(r.Start) >= 0; i = big.NewInt(0).Sub(i, increment) {
 		cloneComp := c.Clone()
 		cloneComp.SetRegisterA(i)
-		output, err := SolveComputer(0, cloneComp)
+		output, err := SolveComputer(ctx, 0, cloneComp)
+		if ctx.Err() != nil {
+			break
+		}
 		if err != nil {
 			fmt.Printf("Error solving with value %s: %v\n", i.String(), err)
 			continue
@@ -238,7 +193,7 @@ func findRanges(c *day17.Computer, r Range) []Range {
 	return ranges
 }
 
-func findRangesBFS(opCodes []day17.Opcode) []*big.Int {
+func findRangesBFS(ctx context.Context, opCodes []day17.Opcode) ([]*big.Int, error) {
 	// Initialize the result array for complete matches
 	var completeMatches []*big.Int
 
@@ -265,7 +220,12 @@ func findRangesBFS(opCodes []day17.Opcode) []*big.Int {
 	fmt.Println("Initial range added to the queue")
 
 	// Perform BFS
+	processed := 0
 	for len(queue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return completeMatches, fmt.Errorf("processed %d ranges with %d still queued: %w", processed, len(queue), err)
+		}
+		processed++
 		fmt.Printf("Queue size: %d\n", len(queue))
 		// Dequeue the next range to process
 		currentRange := queue[0]
@@ -279,7 +239,7 @@ func findRangesBFS(opCodes []day17.Opcode) []*big.Int {
 		fmt.Printf("Processing range: %s\n", currentRange)
 
 		// Call findRanges to get new ranges from the current range
-		newRanges := findRanges(comp, currentRange)
+		newRanges := findRanges(ctx, comp, currentRange)
 		fmt.Printf("Found %d new ranges\n", len(newRanges))
 
 		// Process each range found
@@ -299,10 +259,12 @@ func findRangesBFS(opCodes []day17.Opcode) []*big.Int {
 	}
 
 	fmt.Printf("Total complete matches found: %d\n", len(completeMatches))
-	return completeMatches
+	return completeMatches, nil
 }
 
-func SolveComputer(workerId int, comp *day17.Computer) (string, error) {
+// SolveComputer runs the program on comp until it halts, returning its output.
+// It returns ctx.Err() if ctx is done first.
+func SolveComputer(ctx context.Context, workerId int, comp *day17.Computer) (string, error) {
 	// DEBUG := os.Getenv("DEBUG") == "true"
 	// if DEBUG {
 	// 	fmt.Printf("Worker %d: Beginning solve\n", workerId)
@@ -329,7 +291,17 @@ func SolveComputer(workerId int, comp *day17.Computer) (string, error) {
 		}
 	}()
 
+	done := ctx.Done()
 	for ip < len(opcodes) {
+		select {
+		case <-done:
+			// Stop the output collector before giving up
+			close(comp.Output)
+			workerWg.Wait()
+			return "", ctx.Err()
+		default:
+		}
+
 		// Get the opcode at the instruction pointer
 		opcode := opcodes[ip]
 
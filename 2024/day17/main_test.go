@@ -1,7 +1,8 @@
-package main
+package day17
 
 import (
-	"day17/internal/day17"
+	"2ajoyce/adventofcode/2024/day17/internal/day17"
+	"context"
 	"fmt"
 	"math/big"
 	"os"
@@ -32,7 +33,6 @@ func TestMain(m *testing.M) {
 	// Set up environment variables here
 	os.Setenv("INPUT_FILE", INPUT_FILE)
 	os.Setenv("OUTPUT_FILE", OUTPUT_FILE)
-	os.Setenv("PARALLELISM", "1")
 	os.Setenv("DEBUG", "true")
 
 	// Run all tests
@@ -41,7 +41,6 @@ func TestMain(m *testing.M) {
 	// Clean up any resources if necessary
 	os.Unsetenv("INPUT_FILE")
 	os.Unsetenv("OUTPUT_FILE")
-	os.Unsetenv("PARALLELISM")
 	os.Unsetenv("DEBUG")
 
 	// Exit with the same status as `go test`
@@ -54,7 +53,7 @@ func TestSolveComputer1(t *testing.T) {
 	comp.SetOpcodes([]day17.Opcode{0, 1, 5, 4, 3, 0})
 	expectedOutput := "4,6,3,5,6,3,5,2,1,0"
 
-	output, err := SolveComputer(0, comp)
+	output, err := SolveComputer(context.Background(), 0, comp)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
@@ -70,7 +69,7 @@ func TestSolveComputer2(t *testing.T) {
 	comp.SetOpcodes([]day17.Opcode{5, 0, 5, 1, 5, 4})
 	expectedOutput := "0,1,2"
 
-	output, err := SolveComputer(0, comp)
+	output, err := SolveComputer(context.Background(), 0, comp)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
@@ -86,7 +85,7 @@ func TestSolveComputer3(t *testing.T) {
 	comp.SetOpcodes([]day17.Opcode{0, 1, 5, 4, 3, 0})
 	expectedOutput := "4,2,5,6,7,7,7,7,3,1,0"
 
-	output, err := SolveComputer(0, comp)
+	output, err := SolveComputer(context.Background(), 0, comp)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
@@ -104,7 +103,7 @@ func TestSolveCanSolvePart2(t *testing.T) {
 	comp.SetOpcodes([]day17.Opcode{0, 3, 5, 4, 3, 0})
 	expectedOutput := "0,3,5,4,3,0"
 
-	output, err := SolveComputer(0, comp)
+	output, err := SolveComputer(context.Background(), 0, comp)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
@@ -146,7 +145,7 @@ func TestFindRangesIndexOne(t *testing.T) {
 	index := len(opCodes)
 	match := fmt.Sprintf("%d", opCodes[index-1])
 	searchSpace := Range{Start: big.NewInt(0), End: big.NewInt(262144 - 8), Index: index, Match: match, OutputLength: len(opCodes)}
-	ranges := findRanges(comp, searchSpace)
+	ranges := findRanges(context.Background(), comp, searchSpace)
 
 	expectedRanges := []Range{
 		{Start: searchSpace.Start, End: searchSpace.End, Index: searchSpace.Index - 1, Match: searchSpace.Match, OutputLength: len(opCodes)},
@@ -174,7 +173,7 @@ func TestFindRangesIndexTwo(t *testing.T) {
 		match += fmt.Sprintf("%d", opcode)
 	}
 	searchSpace := Range{Start: big.NewInt(0), End: big.NewInt(262136), Index: index, Match: match, OutputLength: len(opCodes)}
-	ranges := findRanges(comp, searchSpace)
+	ranges := findRanges(context.Background(), comp, searchSpace)
 
 	expectedRanges := []Range{
 		{Start: big.NewInt(98296), End: big.NewInt(135160), Index: searchSpace.Index - 1, Match: searchSpace.Match, OutputLength: len(opCodes)},
@@ -202,7 +201,7 @@ func TestFindRangesIndexThree(t *testing.T) {
 		match += fmt.Sprintf("%d", opcode)
 	}
 	searchSpace := Range{Start: big.NewInt(98296), End: big.NewInt(135160), Index: index, Match: match, OutputLength: len(opCodes)}
-	ranges := findRanges(comp, searchSpace)
+	ranges := findRanges(context.Background(), comp, searchSpace)
 
 	expectedRanges := []Range{
 		{Start: big.NewInt(114680), End: big.NewInt(119288), Index: searchSpace.Index - 1, Match: searchSpace.Match, OutputLength: len(opCodes)},
@@ -230,7 +229,7 @@ func TestFindRangesIndexFour(t *testing.T) {
 		match += fmt.Sprintf("%d", opcode)
 	}
 	searchSpace := Range{Start: big.NewInt(114680), End: big.NewInt(119288), Index: index, Match: match, OutputLength: len(opCodes)}
-	ranges := findRanges(comp, searchSpace)
+	ranges := findRanges(context.Background(), comp, searchSpace)
 
 	expectedRanges := []Range{
 		{Start: big.NewInt(117240), End: big.NewInt(117816), Index: searchSpace.Index - 1, Match: searchSpace.Match, OutputLength: len(opCodes)},
@@ -258,7 +257,7 @@ func TestFindRangesIndexFive(t *testing.T) {
 		match += fmt.Sprintf("%d", opcode)
 	}
 	searchSpace := Range{Start: big.NewInt(117240), End: big.NewInt(117816), Index: index, Match: match, OutputLength: len(opCodes)}
-	ranges := findRanges(comp, searchSpace)
+	ranges := findRanges(context.Background(), comp, searchSpace)
 
 	expectedRanges := []Range{
 		{Start: big.NewInt(117432), End: big.NewInt(117504), Index: searchSpace.Index - 1, Match: searchSpace.Match, OutputLength: len(opCodes)},
@@ -286,7 +285,7 @@ func TestFindRangesIndexSix(t *testing.T) {
 		match += fmt.Sprintf("%d", opcode)
 	}
 	searchSpace := Range{Start: big.NewInt(117432), End: big.NewInt(117504), Index: index, Match: match, OutputLength: len(opCodes)}
-	ranges := findRanges(comp, searchSpace)
+	ranges := findRanges(context.Background(), comp, searchSpace)
 
 	expectedRanges := []Range{
 		{Start: big.NewInt(117447), End: big.NewInt(117447), Index: searchSpace.Index - 1, Match: searchSpace.Match, OutputLength: len(opCodes)},
@@ -349,7 +348,7 @@ func TestFindRangesWithBFSTest(t *testing.T) {
 		t.Logf("Index: %d: Processing range %s", r.Index, r)
 
 		// Find the ranges for the current range
-		ranges := findRanges(comp, r)
+		ranges := findRanges(context.Background(), comp, r)
 		if len(ranges) != len(expected) {
 			t.Errorf("Expected %d ranges, but got %d", len(expected), len(ranges))
 			break
@@ -376,7 +375,10 @@ func TestFindRangesWithBFSTest(t *testing.T) {
 func TestFindRangesBFS(t *testing.T) {
 	opCodes := []day17.Opcode{0, 3, 5, 4, 3, 0}
 
-	results := findRangesBFS(opCodes)
+	results, err := findRangesBFS(context.Background(), opCodes)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
 	expectedResults := []*big.Int{
 		big.NewInt(117447),
 		big.NewInt(117446),
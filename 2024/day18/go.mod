@@ -1,4 +1,4 @@
-module day18
+module 2ajoyce/adventofcode/2024/day18
 
 go 1.23.4
 
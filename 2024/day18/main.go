@@ -1,8 +1,10 @@
-package main
+package day18
 
 import (
-	"day18/internal/aocUtils"
-	"day18/internal/simulation"
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
+	"2ajoyce/adventofcode/lib/simulation"
+	"context"
 	"fmt"
 	"os"
 	"strconv"
@@ -11,67 +13,32 @@ import (
 	"github.com/schollz/progressbar/v3"
 )
 
-func main() {
+var solution = registry.Register(registry.Solution{
+	Year:   2024,
+	Day:    18,
+	Solver: solver.Lines(parseMemory, nil, part2),
+})
 
-	////////////////////////////////////////////////////////////////////
-	// ENVIRONMENT SETUP
-	////////////////////////////////////////////////////////////////////
-
-	//os.Setenv("DEBUG", "true")
-	INPUT_FILE := os.Getenv("INPUT_FILE")
-	OUTPUT_FILE := os.Getenv("OUTPUT_FILE")
-	PARALLELISM, err := strconv.Atoi(os.Getenv("PARALLELISM"))
-	if PARALLELISM < 1 || err != nil {
-		PARALLELISM = 1
-	}
-	fmt.Printf("PARALLELISM: %d\n\n", PARALLELISM)
-
-	if INPUT_FILE == "" || OUTPUT_FILE == "" {
-		fmt.Println("INPUT_FILE and OUTPUT_FILE environment variables not set")
-		fmt.Println("Defaulting to input.txt and output.txt")
-		INPUT_FILE = "input.txt"
-		OUTPUT_FILE = "output.txt"
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// READ INPUT FILE
-	////////////////////////////////////////////////////////////////////
-
-	lines, err := aocUtils.ReadFile(INPUT_FILE)
-	if err != nil {
-		fmt.Printf("Error reading from %s: %v", INPUT_FILE, err)
-		return
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// SOLUTION LOGIC
-	////////////////////////////////////////////////////////////////////
+// memory holds the memory space and the bytes that fall into it, in order.
+type memory struct {
+	sim       simulation.Simulation
+	obstacles []simulation.Coord
+}
 
+func parseMemory(lines []string) (memory, error) {
 	sim, obstacles, err := parseLines(lines)
-	if err != nil {
-		fmt.Println("Error parsing input:", err)
-		return
-	}
-	results, err := solve(sim, obstacles)
-	if err != nil {
-		fmt.Println("Error solving 1:", err)
-		return
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// WRITE OUTPUT FILE
-	////////////////////////////////////////////////////////////////////
-
-	err = aocUtils.WriteToFile(OUTPUT_FILE, results)
-	if err != nil {
-		fmt.Printf("Error writing to %s: %v", OUTPUT_FILE, err)
-		return
-	}
+	return memory{sim, obstacles}, err
+}
 
-	fmt.Printf("Successfully processed %s and created %s\n", INPUT_FILE, OUTPUT_FILE)
+func part2(ctx context.Context, m memory, _ int) ([]string, error) {
+	return solve(m.sim, m.obstacles)
 }
 
-const ObstacleEntityType = "#"
+// ObstacleTerrain marks the cells bytes have fallen into. Bytes never move once
+// they land, so they are terrain rather than entities.
+const ObstacleTerrain byte = '#'
+
+var memoryLegend = simulation.MustNewLegend('.', nil, simulation.WithTerrainRune('#', ObstacleTerrain, true))
 
 func parseLines(lines []string) (simulation.Simulation, []simulation.Coord, error) {
 	DEBUG := os.Getenv("DEBUG") == "true"
@@ -134,50 +101,13 @@ func parseLines(lines []string) (simulation.Simulation, []simulation.Coord, erro
 	return sim, obstacles, nil
 }
 
-type printMask map[simulation.Coord]string
-
-func stringifySimulation(sim simulation.Simulation, masks []printMask) string {
-	output := ""
-	m := sim.GetMap()
-	for y := 0; y < m.GetHeight(); y++ {
-		for x := 0; x < m.GetWidth(); x++ {
-			sym := "%"
-			cell, err := m.GetCell(simulation.Coord{X: x, Y: y})
-			if err != nil {
-				return fmt.Sprintf("error getting cell at %v: %v", simulation.Coord{X: x, Y: y}, err)
-			}
-			entityIds := cell.GetEntityIds()
-			if len(entityIds) == 0 { // Empty cell
-				sym = "."
-			} else { // Entity in cell
-				entity, err := sim.GetEntity(entityIds[0])
-				if err != nil {
-					return fmt.Sprintf("error getting entity %s: %v", entityIds[0], err)
-				}
-				sym = entity.GetEntityType()
-			}
-			// Apply masks
-			for _, mask := range masks {
-				if mask != nil {
-					if val, ok := mask[simulation.Coord{X: x, Y: y}]; ok {
-						sym = val
-					}
-				}
-			}
-			output += sym
-		}
-		output += "\n"
-	}
-	return output
-}
-
 func solve(sim simulation.Simulation, obstacles []simulation.Coord) ([]string, error) {
 	DEBUG := os.Getenv("DEBUG") == "true"
 	fmt.Println("Beginning single-threaded solve")
 
 	if DEBUG {
 		// Print the initial state
-		fmt.Printf("Initial State:\n%s\n", stringifySimulation(sim, nil))
+		fmt.Printf("Initial State:\n%s\n", memoryLegend.Render(sim))
 	}
 
 	//////////////////////////////////////////////////////////////////////////////////////
@@ -185,22 +115,16 @@ func solve(sim simulation.Simulation, obstacles []simulation.Coord) ([]string, e
 	//////////////////////////////////////////////////////////////////////////////////////
 	var alwaysValid bool = true
 	var finalObstacle simulation.Coord // The obstacle that when added, prevents any path from being found
+	width, height := sim.GetMap().GetWidth(), sim.GetMap().GetHeight()
+	terrain := simulation.NewTerrain(width, height, ObstacleTerrain)
 	bar := progressbar.Default(int64(len(obstacles)))
 	for i, obstacle := range obstacles {
-		// Clone the simulation
-		cloneSim := sim.Clone()
-
-		// Add all obstacles [0, i] to the simulation
-		for j := 0; j <= i; j++ {
-			entity, err := simulation.NewEntity(ObstacleEntityType)
-			if err != nil {
-				return nil, fmt.Errorf("error creating obstacle entity: %v", err)
-			}
-			_, err = cloneSim.AddEntity(entity, []simulation.Coord{obstacles[j]}, simulation.North)
-			if err != nil {
-				return nil, fmt.Errorf("error adding obstacle %d: %v", j, err)
-			}
+		// Add obstacle i to the obstacles [0, i) already fallen, and give a copy
+		// of the terrain to a fresh simulation, as terrain on a map can not change
+		if err := terrain.Set(obstacle, ObstacleTerrain); err != nil {
+			return nil, fmt.Errorf("error adding obstacle %d: %v", i, err)
 		}
+		cloneSim := simulation.NewSimulation(width, height, simulation.WithTerrain(terrain.Clone()))
 
 		// Make the graph
 		g, err := makeGraph(cloneSim)
@@ -217,7 +141,7 @@ func solve(sim simulation.Simulation, obstacles []simulation.Coord) ([]string, e
 			finalObstacle = obstacle
 
 			if DEBUG {
-				fmt.Printf("Path:\n%s\n", stringifySimulation(cloneSim, nil))
+				fmt.Printf("Path:\n%s\n", memoryLegend.Render(cloneSim))
 			}
 
 			break
@@ -236,23 +160,21 @@ func solve(sim simulation.Simulation, obstacles []simulation.Coord) ([]string, e
 type graph = map[simulation.Coord]map[simulation.Coord]float64
 
 func makeGraph(sim simulation.Simulation) (graph, error) {
-	// Turn the simulation into a generic graph for pathfinding
+	// Turn the simulation into a generic graph for pathfinding. Obstacles are
+	// terrain, so checking a cell is a byte lookup, and GetNeighbors already
+	// leaves them out.
 	var g graph = make(map[simulation.Coord]map[simulation.Coord]float64)
 	m := sim.GetMap()
+	terrain := m.GetTerrain()
 	for y := 0; y < m.GetHeight(); y++ {
 		for x := 0; x < m.GetWidth(); x++ {
 			coord := simulation.Coord{X: x, Y: y}
-			cell, err := m.GetCell(coord)
-			if err != nil {
-				return g, fmt.Errorf("error getting cell at %v: %v", coord, err)
+			if terrain.Blocked(coord) {
+				continue
 			}
-			entityIds := cell.GetEntityIds()
-			if len(entityIds) == 0 { // Empty cell
-				g[coord] = make(map[simulation.Coord]float64)
-				sim.GetMap().GetNeighbors(coord)
-				for _, neighbor := range sim.GetMap().GetNeighbors(coord) {
-					g[coord][neighbor] = 0
-				}
+			g[coord] = make(map[simulation.Coord]float64)
+			for _, neighbor := range m.GetNeighbors(coord) {
+				g[coord][neighbor] = 0
 			}
 		}
 	}
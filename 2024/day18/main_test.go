@@ -1,8 +1,9 @@
-package main
+package day18
 
 import (
-	"day18/internal/aocUtils"
-	"day18/internal/simulation"
+	"2ajoyce/adventofcode/2024/day18/internal/aocUtils"
+	"2ajoyce/adventofcode/aoc/runner"
+	"2ajoyce/adventofcode/lib/simulation"
 	"os"
 	"testing"
 )
@@ -40,7 +41,6 @@ func TestMain(m *testing.M) {
 	// Set up environment variables here
 	os.Setenv("INPUT_FILE", INPUT_FILE)
 	os.Setenv("OUTPUT_FILE", OUTPUT_FILE)
-	os.Setenv("PARALLELISM", "1")
 	os.Setenv("DEBUG", "true")
 
 	// Run all tests
@@ -50,7 +50,6 @@ func TestMain(m *testing.M) {
 	// If the validation fails, the input and output are retained for troubleshooting
 	os.Unsetenv("INPUT_FILE")
 	os.Unsetenv("OUTPUT_FILE")
-	os.Unsetenv("PARALLELISM")
 	os.Unsetenv("DEBUG")
 
 	// Exit with the same status as `go test`
@@ -68,7 +67,9 @@ func TestMainParseEmptyGrid(t *testing.T) {
 	aocUtils.WriteToFile(INPUT_FILE, input)
 	expectedOutput := "No obstacle fully blocks the path"
 
-	main()
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	validateOutput(t, expectedOutput)
 }
@@ -93,7 +94,9 @@ func TestMainParseFullGrid(t *testing.T) {
 	aocUtils.WriteToFile(INPUT_FILE, input)
 	expectedOutput := "(2, 2)" // No path
 
-	main()
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	validateOutput(t, expectedOutput)
 }
@@ -110,7 +113,9 @@ func TestMainParseBaseCaseCenteredGrid(t *testing.T) {
 	aocUtils.WriteToFile(INPUT_FILE, input)
 	expectedOutput := "No obstacle fully blocks the path"
 
-	main()
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	validateOutput(t, expectedOutput)
 }
@@ -127,7 +132,9 @@ func TestMainParseBaseCaseRightGrid(t *testing.T) {
 	aocUtils.WriteToFile(INPUT_FILE, input)
 	expectedOutput := "No obstacle fully blocks the path"
 
-	main()
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	validateOutput(t, expectedOutput)
 }
@@ -144,7 +151,9 @@ func TestMainParseBaseCaseLeftGrid(t *testing.T) {
 	aocUtils.WriteToFile(INPUT_FILE, input)
 	expectedOutput := "No obstacle fully blocks the path"
 
-	main()
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	validateOutput(t, expectedOutput)
 }
@@ -162,7 +171,9 @@ func TestMainParseBaseCaseOneWayGrid(t *testing.T) {
 	aocUtils.WriteToFile(INPUT_FILE, input)
 	expectedOutput := "No obstacle fully blocks the path"
 
-	main()
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	validateOutput(t, expectedOutput)
 }
@@ -186,7 +197,9 @@ func TestMainPart1Example(t *testing.T) {
 	aocUtils.WriteToFile(INPUT_FILE, input)
 	expectedOutput := "No obstacle fully blocks the path"
 
-	main()
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	validateOutput(t, expectedOutput)
 }
@@ -223,7 +236,9 @@ func TestMainPart2Example(t *testing.T) {
 	aocUtils.WriteToFile(INPUT_FILE, input)
 	expectedOutput := "(6, 1)"
 
-	main()
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	validateOutput(t, expectedOutput)
 }
@@ -279,17 +294,16 @@ func Test_makeGraphBaseCase(t *testing.T) {
 	}
 }
 
-func Test_makeGraphWithEntity(t *testing.T) {
+func Test_makeGraphWithObstacle(t *testing.T) {
 	// Simple 2x2 grid with one obstacle
 	// *01
 	// 0.#
 	// 1..
-	sim := simulation.NewSimulation(2, 2)
-	entity, err := simulation.NewEntity("#")
-	if err != nil {
-		t.Errorf("Failed to create entity: %v", err)
+	terrain := simulation.NewTerrain(2, 2, ObstacleTerrain)
+	if err := terrain.Set(simulation.Coord{X: 1, Y: 0}, ObstacleTerrain); err != nil {
+		t.Errorf("Failed to set terrain: %v", err)
 	}
-	sim.AddEntity(entity, []simulation.Coord{{X: 1, Y: 0}}, simulation.North)
+	sim := simulation.NewSimulation(2, 2, simulation.WithTerrain(terrain))
 	graph, err := makeGraph(sim)
 	if err != nil {
 		t.Errorf("Failed to make graph: %v", err)
@@ -1,4 +1,4 @@
-module day19
+module 2ajoyce/adventofcode/2024/day19
 
 go 1.23.4
 
@@ -1,7 +1,8 @@
-package main
+package day19
 
 import (
-	"day19/internal/aocUtils"
+	"2ajoyce/adventofcode/2024/day19/internal/aocUtils"
+	"2ajoyce/adventofcode/aoc/runner"
 	"math/rand"
 	"os"
 	"strings"
@@ -42,7 +43,6 @@ func TestMain(m *testing.M) {
 	// Set up environment variables here
 	os.Setenv("INPUT_FILE", INPUT_FILE)
 	os.Setenv("OUTPUT_FILE", OUTPUT_FILE)
-	os.Setenv("PARALLELISM", "1")
 	os.Setenv("DEBUG", "true")
 
 	// Run all tests
@@ -52,7 +52,6 @@ func TestMain(m *testing.M) {
 	// If the validation fails, the input and output are retained for troubleshooting
 	os.Unsetenv("INPUT_FILE")
 	os.Unsetenv("OUTPUT_FILE")
-	os.Unsetenv("PARALLELISM")
 	os.Unsetenv("DEBUG")
 
 	// Exit with the same status as `go test`
@@ -206,7 +205,9 @@ func TestMainExample(t *testing.T) {
 	aocUtils.WriteToFile(INPUT_FILE, input)
 	expectedOutput := "6"
 
-	main()
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	validateOutput(t, expectedOutput)
 }
@@ -232,7 +233,9 @@ func TestMainBig(t *testing.T) {
 	aocUtils.WriteToFile(INPUT_FILE, input)
 	expectedOutput := "1"
 
-	main()
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	validateOutput(t, expectedOutput)
 }
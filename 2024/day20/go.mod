@@ -1,4 +1,4 @@
-module day20
+module 2ajoyce/adventofcode/2024/day20
 
 go 1.23.4
 
@@ -1,76 +1,23 @@
-package main
+package day20
 
 import (
-	"day20/internal/aocUtils"
-	"day20/internal/simulation"
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
+	"2ajoyce/adventofcode/lib/grid"
+	"2ajoyce/adventofcode/lib/simulation"
 	"fmt"
 	"math"
 	"os"
 	"slices"
 	"sort"
-	"strconv"
 	"strings"
 )
 
-func main() {
-
-	////////////////////////////////////////////////////////////////////
-	// ENVIRONMENT SETUP
-	////////////////////////////////////////////////////////////////////
-
-	//os.Setenv("DEBUG", "true")
-	INPUT_FILE := os.Getenv("INPUT_FILE")
-	OUTPUT_FILE := os.Getenv("OUTPUT_FILE")
-	PARALLELISM, err := strconv.Atoi(os.Getenv("PARALLELISM"))
-	if PARALLELISM < 1 || err != nil {
-		PARALLELISM = 1
-	}
-	fmt.Printf("PARALLELISM: %d\n\n", PARALLELISM)
-
-	if INPUT_FILE == "" || OUTPUT_FILE == "" {
-		fmt.Println("INPUT_FILE and OUTPUT_FILE environment variables not set")
-		fmt.Println("Defaulting to input.txt and output.txt")
-		INPUT_FILE = "input.txt"
-		OUTPUT_FILE = "output.txt"
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// READ INPUT FILE
-	////////////////////////////////////////////////////////////////////
-
-	lines, err := aocUtils.ReadFile(INPUT_FILE)
-	if err != nil {
-		fmt.Printf("Error reading from %s: %v", INPUT_FILE, err)
-		return
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// SOLUTION LOGIC
-	////////////////////////////////////////////////////////////////////
-
-	input, err := parseLines(lines)
-	if err != nil {
-		fmt.Println("Error parsing input:", err)
-		return
-	}
-	results, err := solve(input)
-	if err != nil {
-		fmt.Println("Error solving 1:", err)
-		return
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// WRITE OUTPUT FILE
-	////////////////////////////////////////////////////////////////////
-
-	err = aocUtils.WriteToFile(OUTPUT_FILE, results)
-	if err != nil {
-		fmt.Printf("Error writing to %s: %v", OUTPUT_FILE, err)
-		return
-	}
-
-	fmt.Printf("Successfully processed %s and created %s\n", INPUT_FILE, OUTPUT_FILE)
-}
+var solution = registry.Register(registry.Solution{
+	Year:   2024,
+	Day:    20,
+	Solver: solver.Lines(parseLines, solver.Serial(solve), nil),
+})
 
 func parseLines(lines []string) ([]simulation.Coord, error) {
 	// DEBUG := os.Getenv("DEBUG") == "true"
@@ -80,25 +27,24 @@ func parseLines(lines []string) ([]simulation.Coord, error) {
 		return nil, fmt.Errorf("input is empty")
 	}
 
+	g, err := grid.ParseRunes(lines)
+	if err != nil {
+		return nil, err
+	}
 	startCoord := simulation.Coord{X: -1, Y: -1}
 	endCoord := simulation.Coord{X: -1, Y: -1}
-	grid := make([][]rune, len(lines))
-	for i, line := range lines {
-		grid[i] = []rune(line)
-		for j, r := range line {
-			if r == 'S' {
-				startCoord = simulation.Coord{X: j, Y: i}
-			} else if r == 'E' {
-				endCoord = simulation.Coord{X: j, Y: i}
-			}
-		}
+	if c, ok := g.Find(func(r rune) bool { return r == 'S' }); ok {
+		startCoord = c
+	}
+	if c, ok := g.Find(func(r rune) bool { return r == 'E' }); ok {
+		endCoord = c
 	}
 
-	fmt.Printf("Parsed Grid\n    Start:%s, End:%s\n%s\n", startCoord.String(), endCoord.String(), stringifyGrid(grid, nil, 4))
+	fmt.Printf("Parsed Grid\n    Start:%s, End:%s\n%s\n", startCoord.String(), endCoord.String(), stringifyGrid(g, nil, 4))
 	fmt.Println()
 
 	// Find the path from start to end
-	path := findPath(grid, startCoord, endCoord)
+	path := findPath(g, startCoord, endCoord)
 
 	return path, nil
 }
@@ -108,28 +54,23 @@ type GridMask struct {
 	mask   rune
 }
 
-func stringifyGrid(grid [][]rune, mask []GridMask, indent int) string {
-	result := ""
-	newChar := ' '
-	for y, row := range grid {
-		result += strings.Repeat(" ", indent)
-		for x, r := range row {
-			newChar = r
-			for _, m := range mask {
-				for _, coord := range m.coords {
-					if coord.X == x && coord.Y == y {
-						newChar = m.mask
-					}
-				}
-			}
-			result += string(newChar)
+func stringifyGrid(g *grid.Grid[rune], mask []GridMask, indent int) string {
+	// Draw the masks on a copy, later masks cover earlier ones
+	masked := g.Clone()
+	for _, m := range mask {
+		for _, coord := range m.coords {
+			masked.Set(coord, m.mask)
 		}
-		result += "\n"
+	}
+
+	result := ""
+	for y := 0; y < masked.Height(); y++ {
+		result += strings.Repeat(" ", indent) + string(masked.Row(y)) + "\n"
 	}
 	return result
 }
 
-func findPath(grid [][]rune, startCoord, endCoord simulation.Coord) []simulation.Coord {
+func findPath(g *grid.Grid[rune], startCoord, endCoord simulation.Coord) []simulation.Coord {
 	DEBUG := os.Getenv("DEBUG") == "true"
 	fmt.Println("Finding Path...")
 
@@ -139,16 +80,12 @@ func findPath(grid [][]rune, startCoord, endCoord simulation.Coord) []simulation
 
 	for currentCoord != endCoord {
 		priorCoords = append(priorCoords, currentCoord)
-		neighbors := currentCoord.GetNeighbors()
-		for _, neighbor := range neighbors {
-			if neighbor.X < 0 || neighbor.Y < 0 || neighbor.Y >= len(grid) || neighbor.X >= len(grid[0]) {
-				continue
-			}
+		for neighbor := range g.Neighbors4(currentCoord) {
 			if slices.Contains(priorCoords, neighbor) {
 				continue
 			}
 			// Only one neighbor is valid
-			if grid[neighbor.Y][neighbor.X] == '.' || grid[neighbor.Y][neighbor.X] == 'E' {
+			if r := g.Get(neighbor); r == '.' || r == 'E' {
 				// Move to the neighbor
 				currentCoord = neighbor
 				path = append(path, currentCoord)
@@ -175,7 +112,7 @@ func findPath(grid [][]rune, startCoord, endCoord simulation.Coord) []simulation
 			fmt.Printf("    First 5 Elements: %v\n", path[:5])
 			fmt.Printf("    Last 5 Elements: %v\n", path[len(path)-5:])
 		}
-		gridString := stringifyGrid(grid, []GridMask{{coords: path, mask: '@'}}, 4)
+		gridString := stringifyGrid(g, []GridMask{{coords: path, mask: '@'}}, 4)
 		fmt.Println(gridString)
 	}
 	return path
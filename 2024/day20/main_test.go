@@ -1,8 +1,9 @@
-package main
+package day20
 
 import (
-	"day20/internal/aocUtils"
-	"day20/internal/simulation"
+	"2ajoyce/adventofcode/2024/day20/internal/aocUtils"
+	"2ajoyce/adventofcode/aoc/runner"
+	"2ajoyce/adventofcode/lib/simulation"
 	"os"
 	"testing"
 )
@@ -41,7 +42,6 @@ func TestMain(m *testing.M) {
 	// Set up environment variables here
 	os.Setenv("INPUT_FILE", INPUT_FILE)
 	os.Setenv("OUTPUT_FILE", OUTPUT_FILE)
-	os.Setenv("PARALLELISM", "1")
 	os.Setenv("DEBUG", "true")
 
 	// Run all tests
@@ -51,7 +51,6 @@ func TestMain(m *testing.M) {
 	// If the validation fails, the input and output are retained for troubleshooting
 	os.Unsetenv("INPUT_FILE")
 	os.Unsetenv("OUTPUT_FILE")
-	os.Unsetenv("PARALLELISM")
 	os.Unsetenv("DEBUG")
 
 	// Exit with the same status as `go test`
@@ -174,7 +173,9 @@ func TestMainSimple(t *testing.T) {
 		"E.",
 	}
 	aocUtils.WriteToFile(INPUT_FILE, input)
-	main()
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 	expectedOutput := []string{
 		"Steps Saved, Count of Cheats",
 		"2,1",
@@ -201,7 +202,9 @@ func TestMainExample(t *testing.T) {
 		"###############",
 	}
 	aocUtils.WriteToFile(INPUT_FILE, input)
-	main()
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 	expectedOutput := []string{
 		"Steps Saved, Count of Cheats",
 		"50,32",
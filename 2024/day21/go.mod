@@ -1,4 +1,4 @@
-module day21
+module 2ajoyce/adventofcode/2024/day21
 
 go 1.23.4
 
@@ -3,6 +3,8 @@ package day21
 import (
 	"fmt"
 	"sync"
+
+	"2ajoyce/adventofcode/lib/vec"
 )
 
 type Coord struct {
@@ -10,6 +12,15 @@ type Coord struct {
 	Y int
 }
 
+// Vec returns the coordinate as a vector
+func (c Coord) Vec() vec.Vec2 {
+	return vec.Vec2{X: c.X, Y: c.Y}
+}
+
+func CoordFromVec(v vec.Vec2) Coord {
+	return Coord{X: v.X, Y: v.Y}
+}
+
 // The keypad is a 3x4 grid of numbers 0-9, with an A in the bottom right corner
 // 7 8 9
 // 4 5 6
@@ -1,8 +1,9 @@
-package main
+package day21
 
 import (
-	"day21/internal/aocUtils"
-	"day21/internal/day21"
+	"2ajoyce/adventofcode/2024/day21/internal/aocUtils"
+	"2ajoyce/adventofcode/2024/day21/internal/day21"
+	"2ajoyce/adventofcode/aoc/runner"
 	"fmt"
 	"os"
 	"slices"
@@ -44,7 +45,6 @@ func TestMain(m *testing.M) {
 	// Set up environment variables here
 	os.Setenv("INPUT_FILE", INPUT_FILE)
 	os.Setenv("OUTPUT_FILE", OUTPUT_FILE)
-	os.Setenv("PARALLELISM", "1")
 	os.Setenv("DEBUG", "true")
 
 	// Run all tests
@@ -54,7 +54,6 @@ func TestMain(m *testing.M) {
 	// If the validation fails, the input and output are retained for troubleshooting
 	os.Unsetenv("INPUT_FILE")
 	os.Unsetenv("OUTPUT_FILE")
-	os.Unsetenv("PARALLELISM")
 	os.Unsetenv("DEBUG")
 
 	// Exit with the same status as `go test`
@@ -71,7 +70,9 @@ func TestMainExample(t *testing.T) {
 		"379A",
 	}
 	aocUtils.WriteToFile(INPUT_FILE, input)
-	main()
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 	expectedOutput := []string{
 		"126384",
 	}
@@ -85,7 +86,9 @@ func TestMainExampleSmall(t *testing.T) {
 		"379A",
 	}
 	aocUtils.WriteToFile(INPUT_FILE, input)
-	main()
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 	expectedOutput := []string{
 		"24256",
 	}
@@ -114,7 +117,9 @@ func TestMainBaseCases(t *testing.T) {
 		tc := tc // capture range variable
 		t.Run(string(tc.input), func(t *testing.T) {
 			aocUtils.WriteToFile(INPUT_FILE, []string{tc.input})
-			main()
+			if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+				t.Errorf("Failed to run solution: %v", err)
+			}
 			validateOutput(t, []string{tc.expectedOutput})
 		})
 	}
@@ -1,7 +1,7 @@
 package internal
 
 import (
-	"day6/internal/directions"
+	"2ajoyce/adventofcode/2024/day6/internal/directions"
 	"fmt"
 	"os"
 )
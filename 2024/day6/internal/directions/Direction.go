@@ -1,6 +1,10 @@
 package directions
 
-import "fmt"
+import (
+	"fmt"
+
+	"2ajoyce/adventofcode/lib/vec"
+)
 
 type Direction string
 
@@ -18,34 +22,52 @@ const (
 	SW Direction = "SW"
 )
 
-func (d Direction) TurnRight() Direction {
-	switch d {
-	case N:
-		return E
-	case S:
-		return W
-	case E:
-		return S
-	case W:
-		return N
-	default:
-		fmt.Println("Invalid direction")
+// Each direction as a step on the grid
+var vectors = map[Direction]vec.Vec2{
+	N:  vec.North,
+	S:  vec.South,
+	E:  vec.East,
+	W:  vec.West,
+	NE: vec.NorthEast,
+	NW: vec.NorthWest,
+	SE: vec.SouthEast,
+	SW: vec.SouthWest,
+}
+
+// Vec returns the step on the grid the direction stands for.
+// It returns false for an invalid direction.
+func (d Direction) Vec() (vec.Vec2, bool) {
+	v, ok := vectors[d]
+	return v, ok
+}
+
+// FromVec returns the direction of a single step on the grid.
+// It returns false if v is not one of the eight steps.
+func FromVec(v vec.Vec2) (Direction, bool) {
+	for d, dv := range vectors {
+		if dv == v {
+			return d, true
+		}
 	}
-	return d
+	return "", false
 }
 
-func (d Direction) TurnLeft() Direction {
-	switch d {
-	case N:
-		return W
-	case S:
-		return E
-	case E:
-		return N
-	case W:
-		return S
-	default:
+// Rotate turns the direction by a number of quarter turns, clockwise when
+// positive and counter-clockwise when negative.
+func (d Direction) Rotate(quarterTurns int) Direction {
+	v, ok := d.Vec()
+	if !ok {
 		fmt.Println("Invalid direction")
+		return d
 	}
-	return d
+	rotated, _ := FromVec(v.Rotate(quarterTurns))
+	return rotated
+}
+
+func (d Direction) TurnRight() Direction {
+	return d.Rotate(1)
+}
+
+func (d Direction) TurnLeft() Direction {
+	return d.Rotate(-1)
 }
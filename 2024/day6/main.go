@@ -1,9 +1,11 @@
-package main
+package day6
 
 import (
-	"bufio"
-	"day6/internal"
-	"day6/internal/directions"
+	"2ajoyce/adventofcode/2024/day6/internal"
+	"2ajoyce/adventofcode/2024/day6/internal/directions"
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
+	"context"
 	"fmt"
 	"os"
 	"strconv"
@@ -13,50 +15,6 @@ import (
 	"github.com/schollz/progressbar/v3"
 )
 
-func ReadInput(INPUT_FILE string) ([]string, error) {
-	inputFile, err := os.Open(INPUT_FILE)
-	if err != nil {
-		return nil, fmt.Errorf("error opening %s: %v", INPUT_FILE, err)
-	}
-	defer inputFile.Close()
-
-	scanner := bufio.NewScanner(inputFile)
-
-	lines := make([]string, 0)
-	for scanner.Scan() {
-		line := scanner.Text()
-		lines = append(lines, line)
-	}
-	return lines, nil
-}
-
-func WriteOutput(OUTPUT_FILE string, results []string) error {
-	outputFile, err := os.Create(OUTPUT_FILE)
-	if err != nil {
-		return fmt.Errorf("error creating %s: %v", OUTPUT_FILE, err)
-	}
-	defer outputFile.Close()
-	writer := bufio.NewWriter(outputFile)
-
-	// Write the results to output.txt, one line per result
-	for i, res := range results {
-		_, err := writer.WriteString(res)
-		if err != nil {
-			return fmt.Errorf("error writing value to %s: %v", OUTPUT_FILE, err)
-		}
-		if i != len(results)-1 {
-			_, err = writer.WriteString("\n")
-			if err != nil {
-				return fmt.Errorf("error writing newline to %s: %v", OUTPUT_FILE, err)
-			}
-		}
-	}
-
-	// Flush the writer to ensure all data is written to output.txt
-	writer.Flush()
-	return nil
-}
-
 type LoopError struct {
 	Message string
 }
@@ -65,67 +23,20 @@ func (e LoopError) Error() string {
 	return e.Message
 }
 
-func main() {
-	//os.Setenv("DEBUG", "true")
-	OVERFLOW_LIMIT := 10000
-	INPUT_FILE := os.Getenv("INPUT_FILE")
-	OUTPUT_FILE := os.Getenv("OUTPUT_FILE")
-	PARALLELISM, err := strconv.Atoi(os.Getenv("PARALLELISM"))
-	if PARALLELISM < 1 || err != nil {
-		PARALLELISM = 1
-	}
-	PARALLELISM = 10
+const OVERFLOW_LIMIT = 10000
 
-	if INPUT_FILE == "" || OUTPUT_FILE == "" {
-		fmt.Println("INPUT_FILE and OUTPUT_FILE environment variables not set")
-		fmt.Println("Defaulting to input.txt and output.txt")
-		INPUT_FILE = "input.txt"
-		OUTPUT_FILE = "output.txt"
-	}
-
-	lines, err := ReadInput(INPUT_FILE)
-	if err != nil {
-		fmt.Printf("Error reading from %s: %v", INPUT_FILE, err)
-		return
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// Start Solution Logic  ///////////////////////////////////////////
-	////////////////////////////////////////////////////////////////////
+var solution = registry.Register(registry.Solution{
+	Year:   2024,
+	Day:    6,
+	Solver: solver.Lines(parseLines, part1, part2),
+})
 
-	// Create an array of all coordinates containing the letter X
-	gridMap, err := parseLines(lines)
-	if err != nil {
-		fmt.Println("Error parsing input:", err)
-		return
-	}
-	results, err := solve1(gridMap, OVERFLOW_LIMIT, false)
-	if err != nil {
-		fmt.Println("Error solving 1:", err)
-		return
-	}
-	gridMap, err = parseLines(lines)
-	if err != nil {
-		fmt.Println("Error parsing input:", err)
-		return
-	}
-	results2, err := solve2(gridMap, OVERFLOW_LIMIT, false, PARALLELISM)
-	results = append(results, results2...)
-	if err != nil {
-		fmt.Println("Error solving 2:", err)
-		return
-	}
-	////////////////////////////////////////////////////////////////////
-	// End Solution Logic  /////////////////////////////////////////////
-	////////////////////////////////////////////////////////////////////
-
-	err = WriteOutput(OUTPUT_FILE, results)
-	if err != nil {
-		fmt.Printf("Error writing to %s: %v", OUTPUT_FILE, err)
-		return
-	}
+func part1(ctx context.Context, gridMap internal.Gridmap, _ int) ([]string, error) {
+	return solve1(gridMap, OVERFLOW_LIMIT, false)
+}
 
-	fmt.Printf("Successfully processed %s and created %s", INPUT_FILE, OUTPUT_FILE)
+func part2(ctx context.Context, gridMap internal.Gridmap, parallelism int) ([]string, error) {
+	return solve2(gridMap, OVERFLOW_LIMIT, false, parallelism)
 }
 
 func parseLines(lines []string) (internal.Gridmap, error) {
@@ -1,6 +1,7 @@
-package main
+package day6
 
 import (
+	"2ajoyce/adventofcode/aoc/runner"
 	"os"
 	"strings"
 	"testing"
@@ -33,7 +34,6 @@ func TestMain(t *testing.T) {
 	os.Setenv("INPUT_FILE", INPUT_FILE)
 	os.Setenv("OUTPUT_FILE", OUTPUT_FILE)
 	os.Setenv("DEBUG", "true")
-	os.Setenv("PARALLELISM", "1")
 
 	// Don't forget to clean up! :D
 	defer os.Remove(INPUT_FILE)
@@ -45,8 +45,10 @@ func TestMain(t *testing.T) {
 	// Set up the input data
 	SetUpTestInput(t, INPUT_FILE)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	// Read the content of output.txt
 	data, err := os.ReadFile(OUTPUT_FILE)
@@ -1,4 +1,4 @@
-module day6
+module 2ajoyce/adventofcode/2024/day6_2
 
 go 1.23.2
 
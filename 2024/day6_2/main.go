@@ -1,75 +1,21 @@
-package main
+package day6_2
 
 import (
-	"day6/internal/aocUtils"
-	"day6/internal/simulation"
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
+	"2ajoyce/adventofcode/lib/simulation"
+	"context"
 	"fmt"
 	"os"
-	"strconv"
 	"sync"
-
-	"github.com/google/uuid"
 )
 
-func main() {
-
-	////////////////////////////////////////////////////////////////////
-	// ENVIRONMENT SETUP
-	////////////////////////////////////////////////////////////////////
-
-	//os.Setenv("DEBUG", "true")
-	INPUT_FILE := os.Getenv("INPUT_FILE")
-	OUTPUT_FILE := os.Getenv("OUTPUT_FILE")
-	PARALLELISM, err := strconv.Atoi(os.Getenv("PARALLELISM"))
-	if PARALLELISM < 1 || err != nil {
-		PARALLELISM = 1
-	}
-	fmt.Printf("PARALLELISM: %d\n\n", PARALLELISM)
-
-	if INPUT_FILE == "" || OUTPUT_FILE == "" {
-		fmt.Println("INPUT_FILE and OUTPUT_FILE environment variables not set")
-		fmt.Println("Defaulting to input.txt and output.txt")
-		INPUT_FILE = "input.txt"
-		OUTPUT_FILE = "output.txt"
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// READ INPUT FILE
-	////////////////////////////////////////////////////////////////////
-
-	lines, err := aocUtils.ReadInput(INPUT_FILE)
-	if err != nil {
-		fmt.Printf("Error reading from %s: %v", INPUT_FILE, err)
-		return
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// SOLUTION LOGIC
-	////////////////////////////////////////////////////////////////////
-
-	input, err := parseLines(lines)
-	if err != nil {
-		fmt.Println("Error parsing input:", err)
-		return
-	}
-	results, err := solve1(input, PARALLELISM)
-	if err != nil {
-		fmt.Println("Error solving 1:", err)
-		return
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// WRITE OUTPUT FILE
-	////////////////////////////////////////////////////////////////////
-
-	err = aocUtils.WriteOutput(OUTPUT_FILE, results)
-	if err != nil {
-		fmt.Printf("Error writing to %s: %v", OUTPUT_FILE, err)
-		return
-	}
-
-	fmt.Printf("Successfully processed %s and created %s", INPUT_FILE, OUTPUT_FILE)
-}
+var solution = registry.Register(registry.Solution{
+	Year:   2024,
+	Day:    6,
+	Name:   "simulation",
+	Solver: solver.Lines(parseLines, nil, solve1),
+})
 
 func parseLines(lines []string) (simulation.Simulation, error) {
 	//DEBUG := os.Getenv("DEBUG") == "true"
@@ -79,55 +25,13 @@ func parseLines(lines []string) (simulation.Simulation, error) {
 		return nil, fmt.Errorf("input is empty")
 	}
 
-	width := len(lines[0])
-	height := len(lines)
-	sim := simulation.NewSimulation(width, height)
-	for y := 0; y < height; y++ {
-		for x := 0; x < width; x++ {
-			if lines[y][x] == '.' { // Skip empty cells
-				continue
-			}
-			unitLetter := string(lines[y][x])
-			entity, err := simulation.NewEntity(unitLetter)
-			if err != nil {
-				return nil, fmt.Errorf("error creating entity for (%d,%d): %v", x, y, err)
-			}
-			_, err = sim.AddEntity(entity, x, y, North.vx, North.vy)
-			if err != nil {
-				return nil, fmt.Errorf("error adding entity to sim at (%d,%d): %v", x, y, err)
-			}
-
-		}
-
+	sim, err := labLegend.Parse(lines)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing map: %v", err)
 	}
 	return sim, nil
 }
 
-func PrintSim(sim simulation.Simulation) string {
-	var output string
-	for y := 0; y < sim.GetMap().GetHeight(); y++ {
-		for x := 0; x < sim.GetMap().GetWidth(); x++ {
-			cell, err := sim.GetMap().GetCell(x, y)
-			if err != nil {
-				output += "?"
-				continue
-			}
-			ids := cell.GetEntityIds()
-			if len(ids) == 0 {
-				output += "."
-			} else {
-				entity, err := sim.GetEntity(ids[0])
-				if err != nil {
-					output += "?"
-				}
-				output += string(entity.GetEntityType())
-			}
-		}
-		output += "\n"
-	}
-	return output
-}
-
 type Coord struct {
 	x, y int
 }
@@ -135,6 +39,11 @@ type Coord struct {
 const GuardEntityType = "^"
 const ObstacleEntityType = "#"
 
+var labLegend = simulation.MustNewLegend('.', map[rune]string{
+	'^': GuardEntityType,
+	'#': ObstacleEntityType,
+})
+
 type Direction struct {
 	vx, vy int
 }
@@ -162,7 +71,7 @@ func TurnRight(dir Direction) Direction {
 		return dir // Return unchanged if not one of the four cardinal directions
 	}
 }
-func solve1(sim simulation.Simulation, workerCount int) ([]string, error) {
+func solve1(ctx context.Context, sim simulation.Simulation, workerCount int) ([]string, error) {
 	DEBUG := os.Getenv("DEBUG") == "true"
 	var output []string
 	var obstructionPositions []Coord
@@ -174,7 +83,7 @@ func solve1(sim simulation.Simulation, workerCount int) ([]string, error) {
 	var emptyCells []Coord
 	for y := 0; y < height; y++ {
 		for x := 0; x < width; x++ {
-			cell, err := sim.GetMap().GetCell(x, y)
+			cell, err := sim.GetMap().GetCell(simulation.Coord{X: x, Y: y})
 			if err != nil {
 				if DEBUG {
 					fmt.Printf("Skipping cell (%d, %d) due to error: %v\n", x, y, err)
@@ -227,7 +136,7 @@ func solve1(sim simulation.Simulation, workerCount int) ([]string, error) {
 			}
 
 			// Place the obstacle at the specified coordinate
-			_, err = clonedSim.AddEntity(obstacle, coord.x, coord.y, 0, 0) // Obstacles don't need movement vectors
+			_, err = clonedSim.AddEntity(obstacle, []simulation.Coord{{X: coord.x, Y: coord.y}}, simulation.Direction{}) // Obstacles don't need movement vectors
 			if err != nil {
 				errorsChan <- fmt.Errorf("worker %d: error adding obstacle at (%d,%d): %v", workerId, coord.x, coord.y, err)
 				continue
@@ -295,27 +204,24 @@ func detectLoop(workerId int, sim simulation.Simulation) (bool, error) {
 	DEBUG := os.Getenv("DEBUG") == "true"
 	if DEBUG {
 		fmt.Printf("Worker %d: Running detectLoop\n", workerId)
-		fmt.Printf("%s\n", PrintSim(sim))
+		fmt.Printf("%s\n", labLegend.Render(sim))
 	}
 
 	// Identify the guard entity
-	var guardID uuid.UUID
+	var guardID simulation.EntityId
 	var guardLocation Coord
 	var guardDirection Direction
-	entities := sim.GetEntities()
-	for _, entity := range entities {
-		if entity.GetEntityType() == GuardEntityType {
-			guardID = entity.GetId()
-			x, y := entity.GetPosition()
-			guardLocation = Coord{x: x, y: y}
-			vx, vy := entity.GetVector()
-			guardDirection = Direction{vx: vx, vy: vy}
-			break
-		}
+	if guards := sim.GetEntitiesOfType(GuardEntityType); len(guards) > 0 {
+		guard := guards[0]
+		guardID = guard.GetId()
+		position := guard.GetPosition()[0]
+		guardLocation = Coord{x: position.X, y: position.Y}
+		direction := guard.GetDirection()
+		guardDirection = Direction{vx: direction.VX, vy: direction.VY}
 	}
 
 	// If no guard found, cannot detect loop
-	if guardID == uuid.Nil {
+	if guardID == simulation.NilEntityId {
 		return false, fmt.Errorf("no guard found on map")
 	}
 
@@ -323,16 +229,33 @@ func detectLoop(workerId int, sim simulation.Simulation) (bool, error) {
 		fmt.Printf("Worker %d: Guard Location: (%d, %d), Direction: (%d, %d)\n", workerId, guardLocation.x, guardLocation.y, guardDirection.vx, guardDirection.vy)
 	}
 
-	// State tracking: position + direction
+	// State tracking: position + direction. An observer records the state after
+	// every move and turn of the guard, and spots the loop when a state repeats.
 	type State struct {
 		x, y   int
 		vx, vy int
 	}
 	visitedStates := make(map[State]bool)
 	visitedStates[State{guardLocation.x, guardLocation.y, guardDirection.vx, guardDirection.vy}] = true
+	loopDetected := false
+	unsubscribe := sim.Subscribe(func(e simulation.Event) {
+		if e.EntityId != guardID {
+			return
+		}
+		position := e.From[0] // Turning leaves the guard where it is
+		if e.Kind == simulation.Moved {
+			position = e.To[0]
+		}
+		currentState := State{position.X, position.Y, e.Direction.VX, e.Direction.VY}
+		if visitedStates[currentState] {
+			loopDetected = true
+		}
+		visitedStates[currentState] = true
+	}, simulation.Moved, simulation.Turned)
+	defer unsubscribe()
 
-	// Simulation loop with loop detection
-	for {
+	// Simulation loop, until the guard leaves the map or the observer spots a loop
+	for !loopDetected {
 		// Calculate the position in front of the guard based on current direction
 		newX := guardLocation.x + guardDirection.vx
 		newY := guardLocation.y + guardDirection.vy
@@ -342,7 +265,7 @@ func detectLoop(workerId int, sim simulation.Simulation) (bool, error) {
 		}
 
 		// Check if the new position is outside the map boundaries
-		if !sim.GetMap().ValidateCoord(newX, newY) {
+		if !sim.GetMap().ValidateCoord(simulation.Coord{X: newX, Y: newY}) {
 			// Guard has left the map; no loop
 			if DEBUG {
 				fmt.Printf("worker %d: Guard moved (%d, %d) outside the map boundaries (width: %d, height: %d)\n",
@@ -352,7 +275,7 @@ func detectLoop(workerId int, sim simulation.Simulation) (bool, error) {
 		}
 
 		// Check if the space in front is empty
-		cell, err := sim.GetMap().GetCell(newX, newY)
+		cell, err := sim.GetMap().GetCell(simulation.Coord{X: newX, Y: newY})
 		if err != nil {
 			// Error accessing cell; assume no loop
 			return false, fmt.Errorf("worker %d: Error accessing cell at (%d, %d): %v", workerId, newX, newY, err)
@@ -360,7 +283,7 @@ func detectLoop(workerId int, sim simulation.Simulation) (bool, error) {
 
 		if cell.IsEmpty() {
 			// Move the guard forward
-			err := sim.MoveEntity(guardID, newX, newY, false)
+			err := sim.MoveEntity(guardID, false)
 			if err != nil {
 				// Failed to move; assume no loop
 				return false, fmt.Errorf("worker %d: Failed to move guard to (%d, %d): %v", workerId, newX, newY, err)
@@ -372,32 +295,17 @@ func detectLoop(workerId int, sim simulation.Simulation) (bool, error) {
 			if DEBUG {
 				fmt.Printf("Guard moved to (%d, %d)\n", newX, newY)
 			}
-
-			// Check for loop
-			currentState := State{guardLocation.x, guardLocation.y, guardDirection.vx, guardDirection.vy}
-			if visitedStates[currentState] {
-				// Loop detected
-				return true, nil
-			}
-			visitedStates[currentState] = true
 		} else {
 			// Turn the guard to the right
 			guardDirection = TurnRight(guardDirection)
 
 			// Update the guard's direction vector in the simulation
-			err := sim.SetEntityVector(guardID, guardDirection.vx, guardDirection.vy)
+			err := sim.SetEntityDirection(guardID, simulation.Direction{VX: guardDirection.vx, VY: guardDirection.vy})
 			if err != nil {
 				// Error updating direction; assume no loop
 				return false, fmt.Errorf("worker %d: Error setting entity vector for guard at (%d, %d): %v", workerId, newX, newY, err)
 			}
-
-			// Check for loop after turning
-			currentState := State{guardLocation.x, guardLocation.y, guardDirection.vx, guardDirection.vy}
-			if visitedStates[currentState] {
-				// Loop detected
-				return true, nil
-			}
-			visitedStates[currentState] = true
 		}
 	}
+	return true, nil
 }
@@ -1,4 +1,4 @@
-module day7
+module 2ajoyce/adventofcode/2024/day7
 
 go 1.23.2
 
@@ -209,7 +209,7 @@ func (e *equation) String() string {
 
 	result := fmt.Sprintf("%s = ", &e.total)
 	for i, number := range e.numbers {
-		result += fmt.Sprintf("%s", &number)
+		result += fmt.Sprintf("%d", number)
 		if i < len(symbols) {
 			result += fmt.Sprintf(" %s ", symbols[i])
 		}
@@ -1,34 +1,22 @@
-package main
+package day7
 
 import (
+	"2ajoyce/adventofcode/2024/day7/internal"
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
 	"bufio"
-	"day7/internal"
+	"context"
 	"fmt"
 	"math/big"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/schollz/progressbar/v3"
 )
 
-func ReadInput(INPUT_FILE string) ([]string, error) {
-	inputFile, err := os.Open(INPUT_FILE)
-	if err != nil {
-		return nil, fmt.Errorf("error opening %s: %v", INPUT_FILE, err)
-	}
-	defer inputFile.Close()
-
-	scanner := bufio.NewScanner(inputFile)
-
-	lines := make([]string, 0)
-	for scanner.Scan() {
-		line := scanner.Text()
-		lines = append(lines, line)
-	}
-	return lines, nil
-}
-
 func WriteOutput(OUTPUT_FILE string, results []string) error {
 	outputFile, err := os.Create(OUTPUT_FILE)
 	if err != nil {
@@ -64,68 +52,11 @@ func (e LoopError) Error() string {
 	return e.Message
 }
 
-func main() {
-	//os.Setenv("DEBUG", "true")
-	INPUT_FILE := os.Getenv("INPUT_FILE")
-	OUTPUT_FILE := os.Getenv("OUTPUT_FILE")
-	PARALLELISM, err := strconv.Atoi(os.Getenv("PARALLELISM"))
-	if PARALLELISM < 1 || err != nil {
-		PARALLELISM = 1
-	}
-	fmt.Printf("PARALLELISM: %d\n", PARALLELISM)
-
-	if INPUT_FILE == "" || OUTPUT_FILE == "" {
-		fmt.Println("INPUT_FILE and OUTPUT_FILE environment variables not set")
-		fmt.Println("Defaulting to input.txt and output.txt")
-		INPUT_FILE = "input.txt"
-		OUTPUT_FILE = "output.txt"
-	}
-
-	lines, err := ReadInput(INPUT_FILE)
-	if err != nil {
-		fmt.Printf("Error reading from %s: %v", INPUT_FILE, err)
-		return
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// Start Solution Logic  ///////////////////////////////////////////
-	////////////////////////////////////////////////////////////////////
-
-	// Create an array of all coordinates containing the letter X
-	equations, err := parseLines(lines)
-	if err != nil {
-		fmt.Println("Error parsing input:", err)
-		return
-	}
-	results, err := solve1(equations, PARALLELISM)
-	if err != nil {
-		fmt.Println("Error solving 1:", err)
-		return
-	}
-
-	equations, err = parseLines(lines)
-	if err != nil {
-		fmt.Println("Error parsing input:", err)
-		return
-	}
-	results2, err := solve2(equations, PARALLELISM)
-	results = append(results, results2...)
-	if err != nil {
-		fmt.Println("Error solving 2:", err)
-		return
-	}
-	////////////////////////////////////////////////////////////////////
-	// End Solution Logic  /////////////////////////////////////////////
-	////////////////////////////////////////////////////////////////////
-
-	err = WriteOutput(OUTPUT_FILE, results)
-	if err != nil {
-		fmt.Printf("Error writing to %s: %v", OUTPUT_FILE, err)
-		return
-	}
-
-	fmt.Printf("Successfully processed %s and created %s", INPUT_FILE, OUTPUT_FILE)
-}
+var solution = registry.Register(registry.Solution{
+	Year:   2024,
+	Day:    7,
+	Solver: solver.Lines(parseLines, solve1, nil),
+})
 
 func parseLines(lines []string) ([]internal.Equation, error) {
 	//DEBUG := os.Getenv("DEBUG")
@@ -161,43 +92,56 @@ func parseLines(lines []string) ([]internal.Equation, error) {
 	return equations, nil
 }
 
-func solve1(equations []internal.Equation, parallelism int) ([]string, error) {
+func solve1(ctx context.Context, equations []internal.Equation, parallelism int) ([]string, error) {
 	//DEBUG := os.Getenv("DEBUG") == "true"
 
 	results := []string{}
 
-	// Open a channel to pass equations to the worker goroutines, a channel to signal completion, and a channel for errors
-	// The goroutines will read equations from the channel, call their Solve() method, and end the routine when they receive a signal on doneChan.
+	// Open a channel to pass equations to the worker goroutines and a channel for errors
+	// The goroutines will read equations from the channel, call their Solve() method, and end the routine when the channel is closed.
+	// Once ctx is done no more equations are handed out, and the workers stop after the equation they are on.
 
+	workerCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
 	equationChan := make(chan internal.Equation)
-	doneChan := make(chan bool)
-	errorChan := make(chan error)
+	errorChan := make(chan error, parallelism)
+	var wg sync.WaitGroup
+	var solved atomic.Int64
 	bar := progressbar.Default(int64(len(equations)))
 
 	for i := 0; i < parallelism; i++ {
+		wg.Add(1)
 		go func() {
+			defer wg.Done()
 			for equation := range equationChan {
 				_, err := equation.Solve()
 				if err != nil {
 					errorChan <- err
+					cancel() // Stop handing out equations
 					return
 				}
+				solved.Add(1)
 				bar.Add(1)
 			}
-			doneChan <- true
 		}()
 	}
+feed:
 	for _, equation := range equations {
-		equationChan <- equation
+		select {
+		case equationChan <- equation:
+		case <-workerCtx.Done():
+			break feed
+		}
 	}
 	close(equationChan)
-	for i := 0; i < parallelism; i++ {
-		<-doneChan
-	}
+	wg.Wait()
 	close(errorChan)
 	for err := range errorChan {
 		return nil, err
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("solved %d of %d equations: %w", solved.Load(), len(equations), err)
+	}
 
 	solvedEquations := 0
 	validEquations := 0
@@ -1,7 +1,8 @@
-package main
+package day7
 
 import (
-	"day7/internal"
+	"2ajoyce/adventofcode/2024/day7/internal"
+	"2ajoyce/adventofcode/aoc/runner"
 	"fmt"
 	"math/big"
 	"math/rand"
@@ -135,8 +136,6 @@ func TestMain(t *testing.T) {
 	defer os.Unsetenv("INPUT_FILE")
 	os.Setenv("OUTPUT_FILE", OUTPUT_FILE)
 	defer os.Unsetenv("OUTPUT_FILE")
-	os.Setenv("PARALLELISM", "1")
-	defer os.Unsetenv("PARALLELISM")
 	//os.Setenv("DEBUG", "true")
 	//defer os.Unsetenv("DEBUG")
 
@@ -144,8 +143,10 @@ func TestMain(t *testing.T) {
 	total, inputData := SetUpTestInput(t, INPUT_FILE)
 	writeInputToFile(INPUT_FILE, inputData, t)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	// Read the content of output.txt
 	data, err := os.ReadFile(OUTPUT_FILE)
@@ -169,8 +170,6 @@ func TestMainParallel(t *testing.T) {
 	defer os.Unsetenv("INPUT_FILE")
 	os.Setenv("OUTPUT_FILE", OUTPUT_FILE)
 	defer os.Unsetenv("OUTPUT_FILE")
-	os.Setenv("PARALLELISM", "1")
-	defer os.Unsetenv("PARALLELISM")
 	//os.Setenv("DEBUG", "true")
 	//defer os.Unsetenv("DEBUG")
 
@@ -178,8 +177,10 @@ func TestMainParallel(t *testing.T) {
 	total, inputData := SetUpTestInput(t, INPUT_FILE)
 	writeInputToFile(INPUT_FILE, inputData, t)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 2); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	// Read the content of output.txt
 	data, err := os.ReadFile(OUTPUT_FILE)
@@ -208,8 +209,6 @@ func TestMainFuzzy(t *testing.T) {
 	defer os.Unsetenv("INPUT_FILE")
 	os.Setenv("OUTPUT_FILE", OUTPUT_FILE)
 	defer os.Unsetenv("OUTPUT_FILE")
-	os.Setenv("PARALLELISM", "1")
-	defer os.Unsetenv("PARALLELISM")
 	// os.Setenv("DEBUG", "true")
 	// defer os.Unsetenv("DEBUG")
 
@@ -218,8 +217,10 @@ func TestMainFuzzy(t *testing.T) {
 	fmt.Println("Writing input to file")
 	writeInputToFile(INPUT_FILE, inputData, t)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	// Read the content of output.txt
 	data, err := os.ReadFile(OUTPUT_FILE)
@@ -1,4 +1,4 @@
-module day8
+module 2ajoyce/adventofcode/2024/day8
 
 go 1.23.2
 
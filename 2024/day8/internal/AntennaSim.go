@@ -1,7 +1,7 @@
 package internal
 
 import (
-	"day8/internal/simulation"
+	simulation "2ajoyce/adventofcode/lib/simulation/xy"
 	"fmt"
 	"os"
 )
@@ -28,13 +28,9 @@ func (as *AntennaSimulation) String() (result string) {
 				continue
 			}
 
-			entityIds, err := cell.GetEntityIds()
-			if err != nil {
-				if _, ok := err.(simulation.CellEmptyError); ok {
-					result += "." // If the cell is not occupied, use "." as a placeholder
-				} else {
-					result += "?" // Use "?" as a placeholder if there is an error fetching the entity ID
-				}
+			entityIds := cell.GetEntityIds()
+			if len(entityIds) == 0 {
+				result += "." // If the cell is not occupied, use "." as a placeholder
 				continue
 			}
 			var entitySymbol = "?"
@@ -64,7 +60,7 @@ func (as *AntennaSimulation) String() (result string) {
 }
 
 func (as *AntennaSimulation) AddAntenna(newAnt *Antenna, x int, y int) (*Antenna, error) {
-	ant, err := as.Simulation.AddEntity(newAnt, x, y)
+	ant, err := as.Simulation.AddEntity(newAnt, x, y, 0, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -243,10 +239,7 @@ func (as *AntennaSimulation) placeAntinode(x, y int) error {
 	}
 
 	// Retrieve all entity IDs in the cell
-	entityIds, err := cell.GetEntityIds()
-	if err != nil && err.Error() != "cell is empty" {
-		return fmt.Errorf("failed to get entities in cell (%d, %d): %v", x, y, err)
-	}
+	entityIds := cell.GetEntityIds()
 
 	// Iterate over all entities and remove existing antinodes
 	for _, eid := range entityIds {
@@ -267,7 +260,7 @@ func (as *AntennaSimulation) placeAntinode(x, y int) error {
 	}
 
 	// Add the new antinode to the simulation
-	_, err = as.AddEntity(a, x, y)
+	_, err = as.AddEntity(a, x, y, 0, 0)
 	if err != nil {
 		return fmt.Errorf("failed to add new antinode at (%d, %d): %v", x, y, err)
 	}
@@ -1,31 +1,16 @@
-package main
+package day8
 
 import (
+	"2ajoyce/adventofcode/2024/day8/internal"
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
 	"bufio"
-	"day8/internal"
+	"context"
 	"fmt"
 	"os"
-	"strconv"
 	"strings"
 )
 
-func ReadInput(INPUT_FILE string) ([]string, error) {
-	inputFile, err := os.Open(INPUT_FILE)
-	if err != nil {
-		return nil, fmt.Errorf("error opening %s: %v", INPUT_FILE, err)
-	}
-	defer inputFile.Close()
-
-	scanner := bufio.NewScanner(inputFile)
-
-	lines := make([]string, 0)
-	for scanner.Scan() {
-		line := scanner.Text()
-		lines = append(lines, line)
-	}
-	return lines, nil
-}
-
 func WriteOutput(OUTPUT_FILE string, results []string) error {
 	outputFile, err := os.Create(OUTPUT_FILE)
 	if err != nil {
@@ -53,68 +38,11 @@ func WriteOutput(OUTPUT_FILE string, results []string) error {
 	return nil
 }
 
-func main() {
-	//os.Setenv("DEBUG", "true")
-	INPUT_FILE := os.Getenv("INPUT_FILE")
-	OUTPUT_FILE := os.Getenv("OUTPUT_FILE")
-	PARALLELISM, err := strconv.Atoi(os.Getenv("PARALLELISM"))
-	if PARALLELISM < 1 || err != nil {
-		PARALLELISM = 1
-	}
-	fmt.Printf("PARALLELISM: %d\n\n", PARALLELISM)
-
-	if INPUT_FILE == "" || OUTPUT_FILE == "" {
-		fmt.Println("INPUT_FILE and OUTPUT_FILE environment variables not set")
-		fmt.Println("Defaulting to input.txt and output.txt")
-		INPUT_FILE = "input.txt"
-		OUTPUT_FILE = "output.txt"
-	}
-
-	lines, err := ReadInput(INPUT_FILE)
-	if err != nil {
-		fmt.Printf("Error reading from %s: %v", INPUT_FILE, err)
-		return
-	}
-
-	////////////////////////////////////////////////////////////////////
-	// Start Solution Logic  ///////////////////////////////////////////
-	////////////////////////////////////////////////////////////////////
-
-	// Create an array of all coordinates containing the letter X
-	input, err := parseLines(lines)
-	if err != nil {
-		fmt.Println("Error parsing input:", err)
-		return
-	}
-	results, err := solve1(input, PARALLELISM)
-	if err != nil {
-		fmt.Println("Error solving 1:", err)
-		return
-	}
-
-	// input, err = parseLines(lines)
-	// if err != nil {
-	// 	fmt.Println("Error parsing input:", err)
-	// 	return
-	// }
-	// results2, err := solve2(input, PARALLELISM)
-	// results = append(results, results2...)
-	// if err != nil {
-	// 	fmt.Println("Error solving 2:", err)
-	// 	return
-	//}
-	////////////////////////////////////////////////////////////////////
-	// End Solution Logic  /////////////////////////////////////////////
-	////////////////////////////////////////////////////////////////////
-
-	err = WriteOutput(OUTPUT_FILE, results)
-	if err != nil {
-		fmt.Printf("Error writing to %s: %v", OUTPUT_FILE, err)
-		return
-	}
-
-	fmt.Printf("Successfully processed %s and created %s", INPUT_FILE, OUTPUT_FILE)
-}
+var solution = registry.Register(registry.Solution{
+	Year:   2024,
+	Day:    8,
+	Solver: solver.Lines(parseLines, nil, solve1),
+})
 
 func parseLines(lines []string) (*internal.AntennaSimulation, error) {
 	DEBUG := os.Getenv("DEBUG") == "true"
@@ -152,7 +80,7 @@ func parseLines(lines []string) (*internal.AntennaSimulation, error) {
 	return sim, nil
 }
 
-func solve1(sim *internal.AntennaSimulation, parallelism int) ([]string, error) {
+func solve1(ctx context.Context, sim *internal.AntennaSimulation, parallelism int) ([]string, error) {
 	//DEBUG := os.Getenv("DEBUG") == "true"
 	fmt.Println("Beginning Solve 1")
 	fmt.Println(sim)
@@ -1,6 +1,7 @@
-package main
+package day8
 
 import (
+	"2ajoyce/adventofcode/aoc/runner"
 	"fmt"
 	"math/rand"
 	"os"
@@ -74,8 +75,6 @@ func TestMain(t *testing.T) {
 	defer os.Unsetenv("INPUT_FILE")
 	os.Setenv("OUTPUT_FILE", OUTPUT_FILE)
 	defer os.Unsetenv("OUTPUT_FILE")
-	os.Setenv("PARALLELISM", "1")
-	defer os.Unsetenv("PARALLELISM")
 	os.Setenv("DEBUG", "true")
 	defer os.Unsetenv("DEBUG")
 
@@ -83,8 +82,10 @@ func TestMain(t *testing.T) {
 	total, inputData := SetUpTestInput(t, INPUT_FILE)
 	writeInputToFile(INPUT_FILE, inputData, t)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	// Read the content of output.txt
 	data, err := os.ReadFile(OUTPUT_FILE)
@@ -108,8 +109,6 @@ func TestMainParallel(t *testing.T) {
 	defer os.Unsetenv("INPUT_FILE")
 	os.Setenv("OUTPUT_FILE", OUTPUT_FILE)
 	defer os.Unsetenv("OUTPUT_FILE")
-	os.Setenv("PARALLELISM", "2")
-	defer os.Unsetenv("PARALLELISM")
 	//os.Setenv("DEBUG", "true")
 	//defer os.Unsetenv("DEBUG")
 
@@ -117,8 +116,10 @@ func TestMainParallel(t *testing.T) {
 	total, inputData := SetUpTestInput(t, INPUT_FILE)
 	writeInputToFile(INPUT_FILE, inputData, t)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 2); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	// Read the content of output.txt
 	data, err := os.ReadFile(OUTPUT_FILE)
@@ -148,8 +149,6 @@ func TestMainFuzzy(t *testing.T) {
 	defer os.Unsetenv("INPUT_FILE")
 	os.Setenv("OUTPUT_FILE", OUTPUT_FILE)
 	defer os.Unsetenv("OUTPUT_FILE")
-	os.Setenv("PARALLELISM", "1")
-	defer os.Unsetenv("PARALLELISM")
 	// os.Setenv("DEBUG", "true")
 	// defer os.Unsetenv("DEBUG")
 
@@ -158,8 +157,10 @@ func TestMainFuzzy(t *testing.T) {
 	fmt.Println("Writing input to file")
 	writeInputToFile(INPUT_FILE, inputData, t)
 
-	// Run the main function
-	main()
+	// Run the solution
+	if err := runner.WriteOutput(solution, INPUT_FILE, OUTPUT_FILE, 1); err != nil {
+		t.Errorf("Failed to run solution: %v", err)
+	}
 
 	// Read the content of output.txt
 	data, err := os.ReadFile(OUTPUT_FILE)
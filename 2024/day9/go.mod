@@ -1,3 +1,3 @@
-module day9
+module 2ajoyce/adventofcode/2024/day9
 
 go 1.23.2
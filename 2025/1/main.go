@@ -1,52 +1,36 @@
-package main
+package day1
 
 import (
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
 	"bufio"
+	"context"
 	"fmt"
-	"os"
+	"io"
 	"strconv"
 )
 
 const DIAL_SIZE = 100
 
-func main() {
-	// First Problem
-	input := make(chan string)
-	go ReadInput("input1.txt", input)
-	result, err := Solve(input)
-	if err != nil {
-		panic(err)
-	}
-	fmt.Println(result)
-
-	// Second Problem
-	input = make(chan string)
-	go ReadInput("input2.txt", input)
-	result, err = Solve2(input)
-	if err != nil {
-		panic(err)
-	}
-	fmt.Println(result)
-}
-
-// ReadInput reads the input from the input.txt file and sends each line to the provided channel.
-func ReadInput(filepath string, c chan string) {
-	f, err := os.Open(filepath)
-	if err != nil {
-		panic(err)
-	}
-	defer f.Close()
+var solution = registry.Register(registry.Solution{
+	Year:   2025,
+	Day:    1,
+	Solver: solver.Channel(ReadInput, Solve, Solve2),
+})
 
-	r := bufio.NewReader(f)
+// ReadInput reads the input from r and sends each line to the provided channel.
+// The channel is closed once the input is read, also when reading fails.
+func ReadInput(r io.Reader, c chan string) error {
+	defer close(c)
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := scanner.Text()
 		c <- line
 	}
-	close(c)
+	return scanner.Err()
 }
 
-func Solve(input chan string) (string, error) {
+func Solve(ctx context.Context, input chan string) (string, error) {
 	number := 50 // The instructions specify that the dial starts on 50
 	total := 0   // This is our output, indicating the number of times the dial landed on zero
 	for line := range input {
@@ -74,7 +58,7 @@ func Solve(input chan string) (string, error) {
 	return fmt.Sprintf("%d", total), nil
 }
 
-func Solve2(input chan string) (string, error) {
+func Solve2(ctx context.Context, input chan string) (string, error) {
 	number := 50 // The instructions specify that the dial starts on 50
 	total := 0   // This is our output, indicating the number of times the dial passed zero
 	for line := range input {
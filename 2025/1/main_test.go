@@ -1,6 +1,7 @@
-package main
+package day1
 
 import (
+	"context"
 	"fmt"
 	"testing"
 )
@@ -43,7 +44,7 @@ func TestSolve(t *testing.T) {
 					inputChan <- line
 				}
 			}()
-			result, err := Solve(inputChan)
+			result, err := Solve(context.Background(), inputChan)
 			if err != nil {
 				t.Errorf("Unexpected error: %v", err)
 			}
@@ -98,7 +99,7 @@ func TestSolve2(t *testing.T) {
 					inputChan <- line
 				}
 			}()
-			result, err := Solve2(inputChan)
+			result, err := Solve2(context.Background(), inputChan)
 			if err != nil {
 				t.Errorf("Unexpected error: %v", err)
 			}
@@ -18,8 +18,8 @@ type VoltageState []uint16
 type Equation struct {
 	Buttons []Button
 	// For Part 1
-	Target State
-	NumBits     int // Necessary for string conversion
+	Target  State
+	NumBits int // Necessary for string conversion
 	// For Part 2
 	TargetVoltage VoltageState
 }
@@ -1,11 +1,14 @@
-package main
+package day10
 
 import (
 	"2ajoyce/adventofcode/2025/10/equation"
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
 	"bufio"
+	"context"
 	"fmt"
+	"io"
 	"math"
-	"os"
 	"sort"
 	"strings"
 	"sync"
@@ -13,41 +16,22 @@ import (
 	"github.com/schollz/progressbar/v3"
 )
 
-func main() {
-	// First Problem
-	input := make(chan *equation.Equation)
-	go ReadInput("input1.txt", input)
-	result, err := Solve1(input)
-	if err != nil {
-		panic(err)
-	}
-	fmt.Println(result)
-
-	// Second Problem
-	input = make(chan *equation.Equation)
-	go ReadInput("input2.txt", input)
-	result, err = Solve2(input)
-	if err != nil {
-		panic(err)
-	}
-	fmt.Println(result)
-}
-
-// ReadInput reads the input from the filepath and sends each line to the provided channel.
-func ReadInput(filepath string, c chan *equation.Equation) {
-	f, err := os.Open(filepath)
-	if err != nil {
-		panic(err)
-	}
-	defer f.Close()
+var solution = registry.Register(registry.Solution{
+	Year:   2025,
+	Day:    10,
+	Solver: solver.Channel(ReadInput, Solve1, Solve2),
+})
 
-	r := bufio.NewReader(f)
+// ReadInput reads the input from r and sends each line to the provided channel.
+// The channel is closed once the input is read, also when reading fails.
+func ReadInput(r io.Reader, c chan *equation.Equation) error {
+	defer close(c)
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := scanner.Text()
 		c <- ParseInput(line)
 	}
-	close(c)
+	return scanner.Err()
 }
 
 // Split the input line into
@@ -57,7 +41,7 @@ func ParseInput(input string) *equation.Equation {
 	return &eq
 }
 
-func Solve1(input chan *equation.Equation) (string, error) {
+func Solve1(ctx context.Context, input chan *equation.Equation) (string, error) {
 	total := 0
 
 	// Collect all equations
@@ -129,7 +113,7 @@ func minButtonPressesBFS(eq *equation.Equation) (int, error) {
 	return 0, fmt.Errorf("no solution for equation: %v", eq)
 }
 
-func Solve2(input chan *equation.Equation) (string, error) {
+func Solve2(ctx context.Context, input chan *equation.Equation) (string, error) {
 	total := 0
 
 	// Collect all equations
@@ -1,7 +1,8 @@
-package main
+package day10
 
 import (
 	"2ajoyce/adventofcode/2025/10/equation"
+	"context"
 	"fmt"
 	"testing"
 )
@@ -27,7 +28,7 @@ func TestSolve1(t *testing.T) {
 					inputChan <- ParseInput(line)
 				}
 			}()
-			result, err := Solve1(inputChan)
+			result, err := Solve1(context.Background(), inputChan)
 			if err != nil {
 				t.Errorf("Unexpected error: %v", err)
 			}
@@ -60,7 +61,7 @@ func TestSolve2(t *testing.T) {
 					inputChan <- ParseInput(line)
 				}
 			}()
-			result, err := Solve2(inputChan)
+			result, err := Solve2(context.Background(), inputChan)
 			if err != nil {
 				t.Errorf("Unexpected error: %v", err)
 			}
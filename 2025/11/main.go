@@ -1,48 +1,36 @@
-package main
+package day11
 
 import (
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
 	"bufio"
+	"context"
 	"fmt"
+	"io"
 	"maps"
-	"os"
 	"regexp"
 )
 
-func main() {
-	// First Problem
-	input := make(chan *Graph)
-	go ReadInput("input1.txt", input)
-	result, err := Solve1(input)
-	if err != nil {
-		panic(err)
-	}
-	fmt.Println(result)
-
-	// Second Problem
-	input = make(chan *Graph)
-	go ReadInput("input2.txt", input)
-	result, err = Solve2(input)
-	if err != nil {
-		panic(err)
-	}
-	fmt.Println(result)
-}
+var solution = registry.Register(registry.Solution{
+	Year:   2025,
+	Day:    11,
+	Solver: solver.Channel(ReadInput, Solve1, Solve2),
+})
 
-// ReadInput reads the input from the filepath and sends each line to the provided channel.
-func ReadInput(filepath string, c chan *Graph) {
-	f, err := os.Open(filepath)
-	if err != nil {
-		panic(err)
-	}
-	defer f.Close()
-
-	r := bufio.NewReader(f)
+// ReadInput reads the input from r and sends each line to the provided channel.
+// The channel is closed once the input is read, also when reading fails.
+func ReadInput(r io.Reader, c chan *Graph) error {
+	defer close(c)
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := scanner.Text()
-		c <- ParseInput(line)
+		g, err := ParseInput(line)
+		if err != nil {
+			return err
+		}
+		c <- g
 	}
-	close(c)
+	return scanner.Err()
 }
 
 // ParseInput parses the input into the necessary data structure.
@@ -52,8 +40,12 @@ var (
 	childRegex = regexp.MustCompile(`(?::\s*|\s)([a-z]{3})`)
 )
 
-func ParseInput(input string) *Graph {
-	name := nameRegex.FindStringSubmatch(input)[1]
+func ParseInput(input string) (*Graph, error) {
+	nameMatch := nameRegex.FindStringSubmatch(input)
+	if nameMatch == nil {
+		return nil, fmt.Errorf("failed to parse input line %q", input)
+	}
+	name := nameMatch[1]
 
 	childMatches := childRegex.FindAllStringSubmatch(input, -1)
 	children := make([]string, 0, len(childMatches))
@@ -63,10 +55,10 @@ func ParseInput(input string) *Graph {
 
 	g := make(Graph)
 	g[name] = children
-	return &g
+	return &g, nil
 }
 
-func Solve1(input chan *Graph) (string, error) {
+func Solve1(ctx context.Context, input chan *Graph) (string, error) {
 	total := 0
 	graph := Graph{}
 	for n := range input {
@@ -134,7 +126,7 @@ func dfsHelper(g Graph, current, target string, visited map[string]bool, req map
 	return total
 }
 
-func Solve2(input chan *Graph) (string, error) {
+func Solve2(ctx context.Context, input chan *Graph) (string, error) {
 	total := 0
 	graph := Graph{}
 	for n := range input {
@@ -1,6 +1,7 @@
-package main
+package day11
 
 import (
+	"context"
 	"fmt"
 	"testing"
 )
@@ -30,10 +31,14 @@ func TestSolve1(t *testing.T) {
 			go func() {
 				defer close(inputChan)
 				for _, line := range tc.input {
-					inputChan <- ParseInput(line)
+					parsed, err := ParseInput(line)
+					if err != nil {
+						t.Errorf("Failed to parse %q: %v", line, err)
+					}
+					inputChan <- parsed
 				}
 			}()
-			result, err := Solve1(inputChan)
+			result, err := Solve1(context.Background(), inputChan)
 			if err != nil {
 				t.Errorf("Unexpected error: %v", err)
 			}
@@ -73,10 +78,14 @@ func TestSolve2(t *testing.T) {
 			go func() {
 				defer close(inputChan)
 				for _, line := range tc.input {
-					inputChan <- ParseInput(line)
+					parsed, err := ParseInput(line)
+					if err != nil {
+						t.Errorf("Failed to parse %q: %v", line, err)
+					}
+					inputChan <- parsed
 				}
 			}()
-			result, err := Solve2(inputChan)
+			result, err := Solve2(context.Background(), inputChan)
 			if err != nil {
 				t.Errorf("Unexpected error: %v", err)
 			}
@@ -1,32 +1,32 @@
-package main
+package day12
 
 import (
 	"2ajoyce/adventofcode/2025/12/packing"
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
 	"bufio"
+	"context"
 	"fmt"
-	"os"
+	"io"
 	"strings"
 )
 
-func main() {
-	// First Problem
-	problem := ReadInput("input1.txt")
-	result, err := Solve1(problem)
-	if err != nil {
-		panic(err)
-	}
-	fmt.Println(result)
+var solution = registry.Register(registry.Solution{
+	Year:   2025,
+	Day:    12,
+	Solver: solver.New(parse, part1, nil),
+})
+
+func parse(r io.Reader) (*packing.Problem, error) {
+	return ReadInput(r)
 }
 
-// ReadInput reads the input from the filepath and returns a Problem.
-func ReadInput(filepath string) *packing.Problem {
-	f, err := os.Open(filepath)
-	if err != nil {
-		panic(err)
-	}
-	defer f.Close()
+func part1(ctx context.Context, problem *packing.Problem, _ solver.Options) (string, error) {
+	return Solve1(ctx, problem)
+}
 
-	r := bufio.NewReader(f)
+// ReadInput reads the input from r and returns a Problem.
+func ReadInput(r io.Reader) (*packing.Problem, error) {
 	scanner := bufio.NewScanner(r)
 
 	pieceId := 0               // Set at start of piece
@@ -63,38 +63,57 @@ func ReadInput(filepath string) *packing.Problem {
 		}
 		// Board definition
 		if strings.Contains(line, "x") {
-			board := ParseBoard(line, pieces)
+			board, err := ParseBoard(line, pieces)
+			if err != nil {
+				return nil, err
+			}
 			boards = append(boards, board)
 			continue
 		}
 
 	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
 	return packing.NewProblem(pieces, boards)
 }
 
-func ParseBoard(line string, pieces []*packing.Piece) *packing.Board {
+func ParseBoard(line string, pieces []*packing.Piece) (*packing.Board, error) {
 	// Example: "12x5: 1 0 1 0 2 2"
 	split := strings.Split(line, ":")
+	if len(split) != 2 {
+		return nil, fmt.Errorf("invalid board %q", line)
+	}
 
 	dimStr := strings.TrimSpace(split[0])
 	var width, height int
-	fmt.Sscanf(dimStr, "%dx%d", &width, &height)
+	if _, err := fmt.Sscanf(dimStr, "%dx%d", &width, &height); err != nil {
+		return nil, fmt.Errorf("invalid board size %q: %v", dimStr, err)
+	}
 	board := packing.NewBoard(width, height)
 
 	// Split piece counts into individual strings
 	pieceCountsStr := strings.Fields(strings.TrimSpace(split[1]))
 	for pid, countStr := range pieceCountsStr {
+		if pid >= len(pieces) {
+			return nil, fmt.Errorf("board %q references unknown piece %d", line, pid)
+		}
 		var count int
-		fmt.Sscanf(countStr, "%d", &count)
+		if _, err := fmt.Sscanf(countStr, "%d", &count); err != nil {
+			return nil, fmt.Errorf("invalid piece count %q: %v", countStr, err)
+		}
 		board.AddPiece(pieces[pid], count)
 	}
 
-	return board
+	return board, nil
 }
 
-func Solve1(problem *packing.Problem) (string, error) {
+func Solve1(ctx context.Context, problem *packing.Problem) (string, error) {
 	total := 0
-	result := problem.EvaluateProblem()
+	result, err := problem.EvaluateProblem(ctx)
+	if err != nil {
+		return "", fmt.Errorf("evaluated %d of %d boards: %w", len(result), len(problem.Boards), err)
+	}
 	for _, res := range result {
 		if res.CanFit {
 			total++
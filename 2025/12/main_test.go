@@ -1,6 +1,8 @@
-package main
+package day12
 
 import (
+	"context"
+	"os"
 	"testing"
 )
 
@@ -14,8 +16,17 @@ func TestSolve1(t *testing.T) {
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			problem := ReadInput("test/" + tc.input)
-			result, err := Solve1(problem)
+			f, err := os.Open("test/" + tc.input)
+			if err != nil {
+				t.Fatalf("Failed to open input: %v", err)
+			}
+			defer f.Close()
+
+			problem, err := ReadInput(f)
+			if err != nil {
+				t.Fatalf("Failed to read input: %v", err)
+			}
+			result, err := Solve1(context.Background(), problem)
 			if err != nil {
 				t.Errorf("Unexpected error: %v", err)
 			}
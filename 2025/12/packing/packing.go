@@ -1,6 +1,7 @@
 package packing
 
 import (
+	"context"
 	"maps"
 	"sort"
 )
@@ -12,9 +13,12 @@ type EvaluationResult struct {
 	Reason string // optional
 }
 
-func (p *Problem) SolveWithBacktracking(board *Board) bool {
+// SolveWithBacktracking reports whether every piece the board asks for fits on
+// it. The search is abandoned, returning ctx.Err(), once ctx is done.
+func (p *Problem) SolveWithBacktracking(ctx context.Context, board *Board) (bool, error) {
 	// Initialize search state
 	state := &SearchState{
+		Done:        ctx.Done(),
 		BoardWidth:  board.Width,
 		BoardHeight: board.Height,
 		Occupied:    make([][]bool, board.Height),
@@ -53,7 +57,11 @@ func (p *Problem) SolveWithBacktracking(board *Board) bool {
 	})
 
 	// Kick off piece-type-based search
-	return p.backtrackByPieceType(state, 0)
+	found := p.backtrackByPieceType(state, 0)
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return found, nil
 }
 
 // backtrackByPieceType tries to place all pieces, one type at a time
@@ -96,6 +104,9 @@ func (p *Problem) placeCopiesOfPiece(
 
 	indices := state.PiecePlacementIdx[pieceId]
 	for i := startIdx; i < len(indices); i++ {
+		if state.cancelled() {
+			return false
+		}
 		plIndex := indices[i]
 		placement := state.Placements[plIndex]
 
@@ -130,6 +141,7 @@ type Placement struct {
 
 // SearchState represents the mutable state during backtracking
 type SearchState struct {
+	Done              <-chan struct{} // Closed when the search should be abandoned
 	BoardWidth        int
 	BoardHeight       int
 	Occupied          [][]bool      // Occupied[y][x]
@@ -167,6 +179,16 @@ func PrecomputePlacements(problem *Problem, board *Board) []Placement {
 	return placements
 }
 
+// cancelled reports whether the search should be abandoned
+func (s *SearchState) cancelled() bool {
+	select {
+	case <-s.Done:
+		return true
+	default:
+		return false
+	}
+}
+
 func placementHasRemaining(state *SearchState, pl Placement) bool {
 	if state.Remaining[pl.PieceId] < 1 {
 		return false
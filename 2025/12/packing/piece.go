@@ -1,6 +1,7 @@
 package packing
 
 import (
+	"2ajoyce/adventofcode/lib/vec"
 	"fmt"
 	"sort"
 )
@@ -15,6 +16,15 @@ func NewPoint(x, y int) Point {
 	return Point{X: x, Y: y}
 }
 
+// Vec returns the point as a vector
+func (p Point) Vec() vec.Vec2 {
+	return vec.Vec2{X: p.X, Y: p.Y}
+}
+
+func PointFromVec(v vec.Vec2) Point {
+	return Point{X: v.X, Y: v.Y}
+}
+
 type Piece struct {
 	Id     int
 	Cells  []Point // occupied cells in original orientation
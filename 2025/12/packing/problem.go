@@ -1,13 +1,16 @@
 package packing
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 type Problem struct {
 	Pieces []*Piece
 	Boards []*Board
 }
 
-func NewProblem(pieces []*Piece, boards []*Board) *Problem {
+func NewProblem(pieces []*Piece, boards []*Board) (*Problem, error) {
 	// Validate that every piece used in boards exists in pieces
 	pieceCounts := make(map[int]bool)
 	for _, p := range pieces {
@@ -16,7 +19,7 @@ func NewProblem(pieces []*Piece, boards []*Board) *Problem {
 	for _, b := range boards {
 		for pid := range b.PieceCounts {
 			if !pieceCounts[pid] {
-				panic("board references unknown piece Id")
+				return nil, fmt.Errorf("board references unknown piece Id %d", pid)
 			}
 		}
 	}
@@ -24,36 +27,44 @@ func NewProblem(pieces []*Piece, boards []*Board) *Problem {
 	return &Problem{
 		Pieces: pieces,
 		Boards: boards,
-	}
+	}, nil
 }
 
-func (p *Problem) EvaluateProblem() []EvaluationResult {
+// EvaluateProblem evaluates every board in turn. Once ctx is done it returns
+// the results of the boards evaluated so far along with ctx.Err().
+func (p *Problem) EvaluateProblem(ctx context.Context) ([]EvaluationResult, error) {
 	results := make([]EvaluationResult, 0, len(p.Boards))
 
 	for _, board := range p.Boards {
-		result := p.evaluateBoard(board)
+		result, err := p.evaluateBoard(ctx, board)
+		if err != nil {
+			return results, err
+		}
 		results = append(results, result)
 	}
-	return results
+	return results, nil
 }
 
-func (p *Problem) evaluateBoard(b *Board) EvaluationResult {
+func (p *Problem) evaluateBoard(ctx context.Context, b *Board) (EvaluationResult, error) {
 	ok, reason := p.AreaFilter(*b)
 	if !ok {
 		return EvaluationResult{
 			Board:  *b,
 			CanFit: false,
 			Reason: reason,
-		}
+		}, nil
 	}
 
-	canSolve := p.SolveWithBacktracking(b)
+	canSolve, err := p.SolveWithBacktracking(ctx, b)
+	if err != nil {
+		return EvaluationResult{}, err
+	}
 
 	return EvaluationResult{
 		Board:  *b,
 		CanFit: canSolve,
 		Reason: "",
-	}
+	}, nil
 }
 
 // AreaFilter checks that total piece area <= board area.
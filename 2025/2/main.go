@@ -1,79 +1,71 @@
-package main
+package day2
 
 import (
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
 	"bufio"
+	"context"
 	"fmt"
-	"os"
+	"io"
 	"sort"
 	"strconv"
 	"strings"
 )
 
-func main() {
-	// First Problem
-	input := make(chan *Span)
-	go ReadInput("input1.txt", input)
-	result, err := Solve1(input)
-	if err != nil {
-		panic(err)
-	}
-	fmt.Println(result)
-
-	// Second Problem
-	input = make(chan *Span)
-	go ReadInput("input2.txt", input)
-	result, err = Solve2(input)
-	if err != nil {
-		panic(err)
-	}
-	fmt.Println(result)
-}
+var solution = registry.Register(registry.Solution{
+	Year:   2025,
+	Day:    2,
+	Solver: solver.Channel(ReadInput, Solve1, Solve2),
+})
 
 type Span struct {
 	start []rune
 	end   []rune
 }
 
-// ReadInput reads the input from the filepath and sends each span to the provided channel.
-func ReadInput(filepath string, c chan *Span) {
-	f, err := os.Open(filepath)
-	if err != nil {
-		panic(err)
-	}
-	defer f.Close()
-
-	r := bufio.NewReader(f)
+// ReadInput reads the input from r and sends each span to the provided channel.
+// The channel is closed once the input is read, also when reading fails.
+func ReadInput(r io.Reader, c chan *Span) error {
+	defer close(c)
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := scanner.Text()
 		// Line has format 11-22,95-115
 		// Split the line into distinct spans
 		spans := strings.Split(line, ",")
-		var start, end []rune
 		for _, span := range spans {
 			splitSpan := strings.Split(span, "-")
-			start = StripPadding(splitSpan[0])
-			end = StripPadding(splitSpan[1])
+			if len(splitSpan) != 2 {
+				return fmt.Errorf("failed to parse span %q", span)
+			}
+			start, err := StripPadding(splitSpan[0])
+			if err != nil {
+				return err
+			}
+			end, err := StripPadding(splitSpan[1])
+			if err != nil {
+				return err
+			}
 			c <- &Span{start, end}
 		}
 	}
-	close(c)
+	return scanner.Err()
 }
 
-func StripPadding(s string) []rune {
+func StripPadding(s string) ([]rune, error) {
 	var output []rune
 	// No numbers should be zero padded, but we're going to convert to/from int just in case
 	i, err := strconv.Atoi(s)
 	if err != nil {
-		panic(fmt.Sprintf("Failed to convert '%s' to integer", s))
+		return nil, fmt.Errorf("failed to convert '%s' to integer", s)
 	}
 	for _, r := range fmt.Sprintf("%d", i) {
 		output = append(output, r)
 	}
-	return output
+	return output, nil
 }
 
-func Solve1(input chan *Span) (string, error) {
+func Solve1(ctx context.Context, input chan *Span) (string, error) {
 	result := 0
 	for span := range input {
 		invalidIds := CheckSpan(span)
@@ -84,7 +76,7 @@ func Solve1(input chan *Span) (string, error) {
 	return fmt.Sprintf("%d", result), nil
 }
 
-func Solve2(input chan *Span) (string, error) {
+func Solve2(ctx context.Context, input chan *Span) (string, error) {
 	result := 0
 	for span := range input {
 		invalidIds := CheckSpan2(span)
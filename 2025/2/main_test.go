@@ -1,6 +1,7 @@
-package main
+package day2
 
 import (
+	"context"
 	"fmt"
 	"testing"
 )
@@ -56,7 +57,7 @@ func TestSolve1(t *testing.T) {
 					inputChan <- &Span{start: line.Start(), end: line.End()}
 				}
 			}()
-			result, err := Solve1(inputChan)
+			result, err := Solve1(context.Background(), inputChan)
 			if err != nil {
 				t.Errorf("Unexpected error: %v", err)
 			}
@@ -96,7 +97,7 @@ func TestSolve2(t *testing.T) {
 					inputChan <- &Span{start: line.Start(), end: line.End()}
 				}
 			}()
-			result, err := Solve2(inputChan)
+			result, err := Solve2(context.Background(), inputChan)
 			if err != nil {
 				t.Errorf("Unexpected error: %v", err)
 			}
@@ -197,7 +198,10 @@ func TestStripPadding(t *testing.T) {
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			result := StripPadding(tc.input)
+			result, err := StripPadding(tc.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
 			// This check compares the len of []rune to len of string
 			// That comparision is not long term durable, but works for this tes
 			if len(result) != len(tc.output) {
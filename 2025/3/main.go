@@ -1,63 +1,51 @@
-package main
+package day3
 
 import (
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
 	"bufio"
+	"context"
 	"fmt"
-	"os"
+	"io"
 )
 
-func main() {
-	// First Problem
-	input := make(chan []int)
-	go ReadInput("input1.txt", input)
-	result, err := Solve1(input)
-	if err != nil {
-		panic(err)
-	}
-	fmt.Println(result)
-
-	// Second Problem
-	input = make(chan []int)
-	go ReadInput("input2.txt", input)
-	result, err = Solve2(input)
-	if err != nil {
-		panic(err)
-	}
-	fmt.Println(result)
-}
+var solution = registry.Register(registry.Solution{
+	Year:   2025,
+	Day:    3,
+	Solver: solver.Channel(ReadInput, Solve1, Solve2),
+})
 
-// ReadInput reads the input from the filepath and sends each line to the provided channel.
-func ReadInput(filepath string, c chan []int) {
-	f, err := os.Open(filepath)
-	if err != nil {
-		panic(err)
-	}
-	defer f.Close()
-
-	r := bufio.NewReader(f)
+// ReadInput reads the input from r and sends each line to the provided channel.
+// The channel is closed once the input is read, also when reading fails.
+func ReadInput(r io.Reader, c chan []int) error {
+	defer close(c)
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := scanner.Text()
-		c <- ParseLine(line)
+		digits, err := ParseLine(line)
+		if err != nil {
+			return err
+		}
+		c <- digits
 	}
-	close(c)
+	return scanner.Err()
 }
 
 // Parse line converts a string into the type of the channel
 // I'm not totally comfortable with this rune arithmatic approach, but it promises better performance
-func ParseLine(s string) []int {
+func ParseLine(s string) ([]int, error) {
 	out := make([]int, len(s))
 	for i, r := range s {
 		n := int(r - '0')
 		if n < 0 || n > 9 {
-			panic(fmt.Sprintf("invalid digit %q in %q", r, s))
+			return nil, fmt.Errorf("invalid digit %q in %q", r, s)
 		}
 		out[i] = n
 	}
-	return out
+	return out, nil
 }
 
-func Solve1(input chan []int) (string, error) {
+func Solve1(ctx context.Context, input chan []int) (string, error) {
 	total := 0
 	for line := range input {
 		digits := FindLargestPair(line)
@@ -66,7 +54,7 @@ func Solve1(input chan []int) (string, error) {
 	return fmt.Sprintf("%d", total), nil
 }
 
-func Solve2(input chan []int) (string, error) {
+func Solve2(ctx context.Context, input chan []int) (string, error) {
 	total := 0
 	for line := range input {
 		digits := FindLargestTwelve(line)
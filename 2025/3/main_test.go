@@ -1,6 +1,7 @@
-package main
+package day3
 
 import (
+	"context"
 	"testing"
 )
 
@@ -23,10 +24,14 @@ func TestSolve1(t *testing.T) {
 			go func() {
 				defer close(inputChan)
 				for _, line := range tc.input {
-					inputChan <- ParseLine(line)
+					parsed, err := ParseLine(line)
+					if err != nil {
+						t.Errorf("Failed to parse %q: %v", line, err)
+					}
+					inputChan <- parsed
 				}
 			}()
-			result, err := Solve1(inputChan)
+			result, err := Solve1(context.Background(), inputChan)
 			if err != nil {
 				t.Errorf("Unexpected error: %v", err)
 			}
@@ -56,10 +61,14 @@ func TestSolve2(t *testing.T) {
 			go func() {
 				defer close(inputChan)
 				for _, line := range tc.input {
-					inputChan <- ParseLine(line)
+					parsed, err := ParseLine(line)
+					if err != nil {
+						t.Errorf("Failed to parse %q: %v", line, err)
+					}
+					inputChan <- parsed
 				}
 			}()
-			result, err := Solve2(inputChan)
+			result, err := Solve2(context.Background(), inputChan)
 			if err != nil {
 				t.Errorf("Unexpected error: %v", err)
 			}
@@ -126,7 +135,11 @@ func TestFindLargestPair(t *testing.T) {
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			result := FindLargestPair(ParseLine(tc.input))
+			digits, err := ParseLine(tc.input)
+			if err != nil {
+				t.Fatalf("Failed to parse %q: %v", tc.input, err)
+			}
+			result := FindLargestPair(digits)
 			if len(result) != len(tc.output) {
 				t.Errorf("Expected %d, got %d", tc.output, result)
 			}
@@ -163,7 +176,11 @@ func TestFindLargest12(t *testing.T) {
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			result := FindLargestTwelve(ParseLine(tc.input))
+			digits, err := ParseLine(tc.input)
+			if err != nil {
+				t.Fatalf("Failed to parse %q: %v", tc.input, err)
+			}
+			result := FindLargestTwelve(digits)
 			if len(result) != len(tc.output) {
 				t.Errorf("Expected %d, got %d", tc.output, result)
 			}
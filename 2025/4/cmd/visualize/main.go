@@ -0,0 +1,27 @@
+//go:build visualize
+
+// Command visualize animates the paper rolls being removed from the second
+// problem's input. The fyne dependency needs cgo and the platform's graphics
+// headers, so it is only built with the visualize tag:
+//
+//	go run -tags visualize ./cmd/visualize
+package main
+
+import (
+	"os"
+
+	day4 "2ajoyce/adventofcode/2025/4"
+	"2ajoyce/adventofcode/lib/grid"
+)
+
+func main() {
+	f, err := os.Open("input2.txt")
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+
+	input := make(chan *grid.Grid[rune])
+	go day4.ReadInput(f, input)
+	day4.RunVisualization(<-input)
+}
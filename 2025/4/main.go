@@ -1,127 +1,125 @@
-package main
+package day4
 
 import (
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
+	"2ajoyce/adventofcode/lib/grid"
+	"2ajoyce/adventofcode/lib/record"
 	"bufio"
+	"context"
 	"fmt"
-	"os"
+	"image/color"
+	"io"
 )
 
-func main() {
-	// First Problem
-	input := make(chan [][]rune)
-	go ReadInput("input1.txt", input)
-	result, err := Solve1(input)
-	if err != nil {
-		panic(err)
-	}
-	fmt.Println(result)
-
-	// Second Problem
-	input = make(chan [][]rune)
-	go ReadInput("input2.txt", input)
-	result, err = Solve2(input)
-	if err != nil {
-		panic(err)
-	}
-	fmt.Println(result)
-
-	input = make(chan [][]rune)
-	go ReadInput("input2.txt", input)
-	RunVisualization(<-input)
-}
-
-// ReadInput reads the input from the filepath and sends each line to the provided channel.
-func ReadInput(filepath string, c chan [][]rune) {
-	f, err := os.Open(filepath)
-	if err != nil {
-		panic(err)
-	}
-	defer f.Close()
+var solution = registry.Register(registry.Solution{
+	Year:   2025,
+	Day:    4,
+	Solver: solver.Channel(ReadInput, Solve1, Solve2),
+})
 
-	r := bufio.NewReader(f)
+// ReadInput reads the whole grid from r and sends it to the provided channel.
+// The channel is closed once the input is read, also when reading fails.
+func ReadInput(r io.Reader, c chan *grid.Grid[rune]) error {
+	defer close(c)
 	scanner := bufio.NewScanner(r)
-	result := [][]rune{}
+	lines := []string{}
 	for scanner.Scan() {
-		line := scanner.Text()
-		result = append(result, ParseInput(line))
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	g, err := ParseInput(lines)
+	if err != nil {
+		return err
 	}
-	c <- result
-	close(c)
+	c <- g
+	return nil
 }
 
 // ParseInput parses the input into the necessary data structure.
 // On more complex inputs, this allows us to use lines of text as input for tests
-func ParseInput(input string) []rune {
-	return []rune(input)
+func ParseInput(lines []string) (*grid.Grid[rune], error) {
+	return grid.ParseRunes(lines)
 }
 
-func Solve1(input chan [][]rune) (string, error) {
+func Solve1(ctx context.Context, input chan *grid.Grid[rune]) (string, error) {
 	total := 0
 	// The use of a channel here is contrived, but most problems have been processed line by line
-	grid := <-input
-	// PrintGrid(grid)
-	h := CalculateHeatmap(grid)
+	g := <-input
+	// PrintGrid(g)
+	h := CalculateHeatmap(g)
 	// PrintHeatmap(h)
 
-	for y, row := range grid {
-		for x, c := range row {
-			if IsPaper(c) && h[y][x] < 4 {
-				total++
-			}
+	for c, r := range g.All() {
+		if IsPaper(r) && h.Get(c) < 4 {
+			total++
 		}
 	}
 
 	return fmt.Sprintf("%d", total), nil
 }
 
-func Solve2(input chan [][]rune) (string, error) {
+// Colors of the frames recorded with --record, the same as the visualization
+var paperPalette = record.Palette{
+	Runes: map[rune]color.Color{
+		'@': color.RGBA{R: 255, G: 255, B: 0, A: 255}, // Yellow
+		'.': color.RGBA{R: 20, G: 20, B: 20, A: 255},  // Dark Gray
+	},
+}
+
+func Solve2(ctx context.Context, input chan *grid.Grid[rune]) (string, error) {
 	total := 0
 	// The use of a channel here is contrived, but most problems have been processed line by line
-	grid := <-input
-	// PrintGrid(grid)
-	h := CalculateHeatmap(grid)
+	g := <-input
+	// PrintGrid(g)
+	h := CalculateHeatmap(g)
 	// PrintHeatmap(h)
+	recorder := record.FromContext(ctx)
+	recorder.Grid(g, &paperPalette)
 
 	for {
-		paperRemoved := false
-		// Iterate over the grid, removing all paper
-		for y, row := range grid {
-			for x, c := range row {
-				if IsPaper(c) && h[y][x] < 4 {
-					grid[y][x] = '.' // Remove the paper from the grid
-					total++
-					paperRemoved = true
-				}
-			}
-		}
-		if paperRemoved == false {
+		paperRemoved := RemovePaper(g, h)
+		if paperRemoved == 0 {
 			break // No more paper can be removed
 		}
+		total += paperRemoved
+		recorder.Grid(g, &paperPalette)
 		// Recalculate the new heatmap
-		h = CalculateHeatmap(grid)
+		h = CalculateHeatmap(g)
 	}
 
 	return fmt.Sprintf("%d", total), nil
 }
 
-func Visualization(grid [][]rune) {
-	RunVisualization(grid)
+// RemovePaper removes every roll of paper with fewer than four rolls around it
+// according to the heatmap, returning how many were removed.
+func RemovePaper(g *grid.Grid[rune], h *grid.Grid[int]) int {
+	removed := 0
+	for c, r := range g.All() {
+		if IsPaper(r) && h.Get(c) < 4 {
+			g.Set(c, '.') // Remove the paper from the grid
+			removed++
+		}
+	}
+	return removed
 }
 
-func PrintGrid(g [][]rune) {
-	for _, row := range g {
+func PrintGrid(g *grid.Grid[rune]) {
+	for y := 0; y < g.Height(); y++ {
 		fmt.Print("|")
-		for _, char := range row {
+		for _, char := range g.Row(y) {
 			fmt.Printf("%c|", char)
 		}
 		fmt.Println()
 	}
 }
 
-func PrintHeatmap(g [][]int) {
-	for _, row := range g {
+func PrintHeatmap(g *grid.Grid[int]) {
+	for y := 0; y < g.Height(); y++ {
 		fmt.Print("|")
-		for _, char := range row {
+		for _, char := range g.Row(y) {
 			fmt.Printf("%d|", char)
 		}
 		fmt.Println()
@@ -142,53 +140,18 @@ func IsPaper(r rune) bool {
 	return false
 }
 
-type Coord struct {
-	x int
-	y int
-}
-
-// Nearby returns the 8 coordinates surrounding c.
-// It removes coordinates that fall outside the range [0, max].
-func (c *Coord) Nearby(maxX, maxY int) []Coord {
-	var result []Coord
-
-	// Offsets for the 8 surrounding cells
-	deltas := []struct{ dx, dy int }{
-		{-1, -1}, {0, -1}, {1, -1},
-		{-1, 0}, {1, 0},
-		{-1, 1}, {0, 1}, {1, 1},
-	}
-
-	for _, d := range deltas {
-		nx := c.x + d.dx
-		ny := c.y + d.dy
-
-		// Check bounds (≥0 and ≤max)
-		if nx >= 0 && ny >= 0 && nx <= maxX && ny <= maxY {
-			result = append(result, Coord{nx, ny})
-		}
-	}
-
-	return result
-}
-
-func CalculateHeatmap(g [][]rune) [][]int {
+// CalculateHeatmap counts, for every square, the rolls of paper in the 8
+// squares surrounding it.
+func CalculateHeatmap(g *grid.Grid[rune]) *grid.Grid[int] {
 	// Initialize the heatmap
-	h := make([][]int, len(g))
-	for i, _ := range h {
-		h[i] = make([]int, len(g[0]))
-	}
+	h := grid.New[int](g.Width(), g.Height())
 
 	// Loop over every square in the input
-	for y, row := range g {
-		for x, char := range row {
-			// If the square is paper, increase the surrounding heatmap values by one
-			if IsPaper(char) {
-				current := Coord{x, y}
-				surrounding := current.Nearby(len(g[0])-1, len(g)-1)
-				for _, c := range surrounding {
-					h[c.y][c.x]++
-				}
+	for current, char := range g.All() {
+		// If the square is paper, increase the surrounding heatmap values by one
+		if IsPaper(char) {
+			for c := range g.Neighbors8(current) {
+				h.Set(c, h.Get(c)+1)
 			}
 		}
 	}
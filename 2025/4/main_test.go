@@ -1,6 +1,8 @@
-package main
+package day4
 
 import (
+	"2ajoyce/adventofcode/lib/grid"
+	"context"
 	"testing"
 )
 
@@ -25,16 +27,14 @@ func TestSolve1(t *testing.T) {
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			inputChan := make(chan [][]rune)
-			go func() {
-				defer close(inputChan)
-				input := [][]rune{}
-				for _, line := range tc.input {
-					input = append(input, ParseInput(line))
-				}
-				inputChan <- input
-			}()
-			result, err := Solve1(inputChan)
+			input, err := ParseInput(tc.input)
+			if err != nil {
+				t.Fatalf("Failed to parse input: %v", err)
+			}
+			inputChan := make(chan *grid.Grid[rune], 1)
+			inputChan <- input
+			close(inputChan)
+			result, err := Solve1(context.Background(), inputChan)
 			if err != nil {
 				t.Errorf("Unexpected error: %v", err)
 			}
@@ -66,16 +66,14 @@ func TestSolve2(t *testing.T) {
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			inputChan := make(chan [][]rune)
-			go func() {
-				defer close(inputChan)
-				input := [][]rune{}
-				for _, line := range tc.input {
-					input = append(input, ParseInput(line))
-				}
-				inputChan <- input
-			}()
-			result, err := Solve2(inputChan)
+			input, err := ParseInput(tc.input)
+			if err != nil {
+				t.Fatalf("Failed to parse input: %v", err)
+			}
+			inputChan := make(chan *grid.Grid[rune], 1)
+			inputChan <- input
+			close(inputChan)
+			result, err := Solve2(context.Background(), inputChan)
 			if err != nil {
 				t.Errorf("Unexpected error: %v", err)
 			}
@@ -1,10 +1,14 @@
-package main
+//go:build visualize
+
+package day4
 
 import (
 	"image"
 	"image/color"
 	"time"
 
+	"2ajoyce/adventofcode/lib/grid"
+
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/canvas"
@@ -27,8 +31,8 @@ var (
 )
 
 type vis struct {
-	grid        [][]rune
-	initialGrid [][]rune
+	grid        *grid.Grid[rune]
+	initialGrid *grid.Grid[rune]
 	total       int
 
 	canvas *gridCanvas
@@ -52,18 +56,14 @@ func (g *gridCanvas) CreateRenderer() fyne.WidgetRenderer {
 
 func (g *gridCanvas) draw(w, h int) image.Image {
 	img := image.NewRGBA(image.Rect(0, 0, w, h))
-	gridRunes := g.vis.grid
-
-	for y, row := range gridRunes {
-		for x, r := range row {
-			col := emptyColor
-			if IsPaper(r) {
-				col = paperColor
-			}
-			for i := 0; i < squareSize; i++ {
-				for j := 0; j < squareSize; j++ {
-					img.Set(x*squareSize+i, y*squareSize+j, col)
-				}
+	for c, r := range g.vis.grid.All() {
+		col := emptyColor
+		if IsPaper(r) {
+			col = paperColor
+		}
+		for i := 0; i < squareSize; i++ {
+			for j := 0; j < squareSize; j++ {
+				img.Set(c.X*squareSize+i, c.Y*squareSize+j, col)
 			}
 		}
 	}
@@ -80,8 +80,8 @@ func (r *gridRenderer) Layout(size fyne.Size) {
 }
 
 func (r *gridRenderer) MinSize() fyne.Size {
-	gridRunes := r.canvas.vis.grid
-	return fyne.NewSize(float32(len(gridRunes[0])*squareSize), float32(len(gridRunes)*squareSize))
+	g := r.canvas.vis.grid
+	return fyne.NewSize(float32(g.Width()*squareSize), float32(g.Height()*squareSize))
 }
 
 func (r *gridRenderer) Refresh() {
@@ -94,26 +94,18 @@ func (r *gridRenderer) Objects() []fyne.CanvasObject {
 
 func (r *gridRenderer) Destroy() {}
 
-func RunVisualization(grid [][]rune) (int, error) {
+func Visualization(g *grid.Grid[rune]) {
+	RunVisualization(g)
+}
+
+func RunVisualization(g *grid.Grid[rune]) (int, error) {
 	a := app.New()
 	w := a.NewWindow("Paper Removal Simulation")
 
-	initialGrid := make([][]rune, len(grid))
-	for i, row := range grid {
-		initialGrid[i] = make([]rune, len(row))
-		copy(initialGrid[i], row)
-	}
-
-	// create a deep copy of the input grid to avoid mutating caller data
-	gridCopy := make([][]rune, len(grid))
-	for i, row := range grid {
-		gridCopy[i] = make([]rune, len(row))
-		copy(gridCopy[i], row)
-	}
-
 	v := &vis{
-		grid:        gridCopy,
-		initialGrid: initialGrid,
+		// copy the input grid to avoid mutating caller data
+		grid:        g.Clone(),
+		initialGrid: g.Clone(),
 		paused:      true,
 		loop:        false,
 		speed:       200 * time.Millisecond,
@@ -134,12 +126,7 @@ func RunVisualization(grid [][]rune) (int, error) {
 
 	resetButton := widget.NewButton("Reset", func() {
 		v.total = 0
-		newGrid := make([][]rune, len(v.initialGrid))
-		for i, row := range v.initialGrid {
-			newGrid[i] = make([]rune, len(row))
-			copy(newGrid[i], row)
-		}
-		v.grid = newGrid
+		v.grid = v.initialGrid.Clone()
 		v.canvas.Refresh()
 	})
 
@@ -171,17 +158,9 @@ func RunVisualization(grid [][]rune) (int, error) {
 				// run the simulation step on the main UI thread to avoid races
 				fyne.DoAndWait(func() {
 					h := CalculateHeatmap(v.grid)
-					paperRemoved := false
-
-					for y, row := range v.grid {
-						for x, c := range row {
-							if IsPaper(c) && h[y][x] < 4 {
-								v.grid[y][x] = '.'
-								v.total++
-								paperRemoved = true
-							}
-						}
-					}
+					removed := RemovePaper(v.grid, h)
+					v.total += removed
+					paperRemoved := removed > 0
 
 					if paperRemoved {
 						v.canvas.Refresh()
@@ -191,12 +170,7 @@ func RunVisualization(grid [][]rune) (int, error) {
 						if v.loop {
 							// reset
 							v.total = 0
-							newGrid := make([][]rune, len(v.initialGrid))
-							for i, row := range v.initialGrid {
-								newGrid[i] = make([]rune, len(row))
-								copy(newGrid[i], row)
-							}
-							v.grid = newGrid
+							v.grid = v.initialGrid.Clone()
 							v.canvas.Refresh()
 						} else {
 							v.paused = true
@@ -1,10 +1,13 @@
-package main
+package day5
 
 import (
 	"2ajoyce/adventofcode/2025/5/interval"
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
 	"bufio"
+	"context"
 	"fmt"
-	"os"
+	"io"
 	"strconv"
 	"strings"
 )
@@ -13,41 +16,63 @@ type Range struct {
 	start, end int
 }
 
-func main() {
-	// First Problem
+var solution = registry.Register(registry.Solution{
+	Year:   2025,
+	Day:    5,
+	Solver: solver.New(ParseDatabase, part1, part2),
+})
+
+// Database holds both sections of the input, the fresh ingredient ranges and
+// the available ingredient IDs.
+type Database struct {
+	Ranges []Range
+	IDs    []int
+}
+
+// ParseDatabase collects everything ReadInput streams into a Database.
+func ParseDatabase(r io.Reader) (*Database, error) {
 	cRange := make(chan Range)
 	cInt := make(chan int)
-	go ReadInput("input1.txt", cRange, cInt)
-	result, err := Solve1(cRange, cInt)
-	if err != nil {
-		panic(err)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- ReadInput(r, cRange, cInt)
+	}()
+
+	db := &Database{}
+	for rg := range cRange {
+		db.Ranges = append(db.Ranges, rg)
 	}
-	fmt.Println(result)
-
-	// Second Problem
-	cRange = make(chan Range)
-	cInt = make(chan int)
-	go ReadInput("input2.txt", cRange, cInt)
-	result, err = Solve2(cRange)
-	if err != nil {
-		panic(err)
+	for i := range cInt {
+		db.IDs = append(db.IDs, i)
+	}
+	if err := <-errc; err != nil {
+		return nil, err
 	}
-	fmt.Println(result)
+	return db, nil
 }
 
-// ReadInput reads the input from the filepath and sends each line to the provided channel.
-func ReadInput(filepath string, cRange chan Range, cInt chan int) {
-	f, err := os.Open(filepath)
-	if err != nil {
-		panic(err)
-	}
-	defer f.Close()
+func part1(ctx context.Context, db *Database, _ solver.Options) (string, error) {
+	return Solve1(ctx, solver.Stream(ctx, db.Ranges), solver.Stream(ctx, db.IDs))
+}
 
-	r := bufio.NewReader(f)
+func part2(ctx context.Context, db *Database, _ solver.Options) (string, error) {
+	return Solve2(ctx, solver.Stream(ctx, db.Ranges))
+}
+
+// ReadInput reads the input from r and sends each range and ID to the provided channels.
+// Both channels are closed once the input is read, also when reading fails.
+func ReadInput(r io.Reader, cRange chan Range, cInt chan int) error {
 	scanner := bufio.NewScanner(r)
 
 	// The input will have a top section and a bottom section, separated by a newline
 	closedRange := false
+	defer func() {
+		// An input without IDs never reaches the bottom section
+		if !closedRange {
+			close(cRange)
+		}
+		close(cInt)
+	}()
 	for scanner.Scan() {
 		line := scanner.Text()
 		// Skip the newline when we get there
@@ -55,29 +80,44 @@ func ReadInput(filepath string, cRange chan Range, cInt chan int) {
 			continue
 		}
 		if strings.Contains(line, "-") {
-			cRange <- ParseRange(line)
+			rg, err := ParseRange(line)
+			if err != nil {
+				return err
+			}
+			cRange <- rg
 		} else {
 			// When we get the first search, we need to close the range channel
 			if !closedRange {
 				close(cRange)
 				closedRange = true
 			}
-			cInt <- StrToInt(line)
+			id, err := strconv.Atoi(line)
+			if err != nil {
+				return fmt.Errorf("failed to convert string %q to integer: %v", line, err)
+			}
+			cInt <- id
 		}
 	}
-	close(cInt)
+	return scanner.Err()
 }
 
-func ParseRange(input string) Range {
+func ParseRange(input string) (Range, error) {
 	arr := strings.Split(input, "-")
 	if len(arr) != 2 {
-		panic(fmt.Sprintf("error parsing range range %s", input))
+		return Range{}, fmt.Errorf("error parsing range %s", input)
+	}
+	start, err := strconv.Atoi(arr[0])
+	if err != nil {
+		return Range{}, fmt.Errorf("error parsing range %s: %v", input, err)
+	}
+	end, err := strconv.Atoi(arr[1])
+	if err != nil {
+		return Range{}, fmt.Errorf("error parsing range %s: %v", input, err)
 	}
-	r := Range{start: StrToInt(arr[0]), end: StrToInt(arr[1])}
-	return r
+	return Range{start: start, end: end}, nil
 }
 
-func Solve1(cRange chan Range, cInt chan int) (string, error) {
+func Solve1(ctx context.Context, cRange chan Range, cInt chan int) (string, error) {
 	total := 0
 
 	tree := interval.NewIntervalTree()
@@ -95,7 +135,7 @@ func Solve1(cRange chan Range, cInt chan int) (string, error) {
 	return fmt.Sprintf("%d", total), nil
 }
 
-func Solve2(cRange chan Range) (string, error) {
+func Solve2(ctx context.Context, cRange chan Range) (string, error) {
 	total := int64(0)
 
 	tree := interval.NewIntervalTree()
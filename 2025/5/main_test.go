@@ -1,6 +1,7 @@
-package main
+package day5
 
 import (
+	"context"
 	"testing"
 )
 
@@ -43,7 +44,7 @@ func TestSolve1(t *testing.T) {
 					cInt <- i
 				}
 			}()
-			result, err := Solve1(cRange, cInt)
+			result, err := Solve1(context.Background(), cRange, cInt)
 			if err != nil {
 				t.Errorf("Unexpected error: %v", err)
 			}
@@ -77,7 +78,7 @@ func TestSolve2(t *testing.T) {
 					cRange <- r
 				}
 			}()
-			result, err := Solve2(cRange)
+			result, err := Solve2(context.Background(), cRange)
 			if err != nil {
 				t.Errorf("Unexpected error: %v", err)
 			}
@@ -1,48 +1,32 @@
-package main
+package day6
 
 import (
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
 	"bufio"
+	"context"
 	"fmt"
-	"os"
+	"io"
 	"slices"
 	"strings"
 )
 
-func main() {
-	// First Problem
-	input := make(chan string)
-	go ReadInput("input1.txt", input)
-	result, err := Solve1(input)
-	if err != nil {
-		panic(err)
-	}
-	fmt.Println(result)
-
-	// Second Problem
-	input = make(chan string)
-	go ReadInput("input2.txt", input)
-	result, err = Solve2(input)
-	if err != nil {
-		panic(err)
-	}
-	fmt.Println(result)
-}
-
-// ReadInput reads the input from the filepath and sends each line to the provided channel.
-func ReadInput(filepath string, c chan string) {
-	f, err := os.Open(filepath)
-	if err != nil {
-		panic(err)
-	}
-	defer f.Close()
+var solution = registry.Register(registry.Solution{
+	Year:   2025,
+	Day:    6,
+	Solver: solver.Channel(ReadInput, Solve1, Solve2),
+})
 
-	r := bufio.NewReader(f)
+// ReadInput reads the input from r and sends each line to the provided channel.
+// The channel is closed once the input is read, also when reading fails.
+func ReadInput(r io.Reader, c chan string) error {
+	defer close(c)
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := scanner.Text()
 		c <- ParseInput(line)
 	}
-	close(c)
+	return scanner.Err()
 }
 
 // ParseInput parses the input into the necessary data structure.
@@ -51,7 +35,7 @@ func ParseInput(input string) string {
 	return input
 }
 
-func Solve1(input chan string) (string, error) {
+func Solve1(ctx context.Context, input chan string) (string, error) {
 	total := 0
 	problems := [][]int{}  // An array of the problems. Each problem is an array of numbers.
 	operands := []string{} // Each problem has an operand
@@ -96,7 +80,7 @@ func Solve1(input chan string) (string, error) {
 	return fmt.Sprintf("%d", total), nil
 }
 
-func Solve2(input chan string) (string, error) {
+func Solve2(ctx context.Context, input chan string) (string, error) {
 	total := 0
 	var problems [][]int   // An array of the problems. Each problem is an array of numbers.
 	operands := []string{} // Each problem has an operand
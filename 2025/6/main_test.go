@@ -1,6 +1,7 @@
-package main
+package day6
 
 import (
+	"context"
 	"testing"
 )
 
@@ -26,7 +27,7 @@ func TestSolve1(t *testing.T) {
 					inputChan <- ParseInput(line)
 				}
 			}()
-			result, err := Solve1(inputChan)
+			result, err := Solve1(context.Background(), inputChan)
 			if err != nil {
 				t.Errorf("Unexpected error: %v", err)
 			}
@@ -59,7 +60,7 @@ func TestSolve2(t *testing.T) {
 					inputChan <- ParseInput(line)
 				}
 			}()
-			result, err := Solve2(inputChan)
+			result, err := Solve2(context.Background(), inputChan)
 			if err != nil {
 				t.Errorf("Unexpected error: %v", err)
 			}
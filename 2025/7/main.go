@@ -1,58 +1,40 @@
-package main
+package day7
 
 import (
 	"2ajoyce/adventofcode/2025/7/graph"
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
 	"bufio"
+	"context"
 	"fmt"
+	"io"
 	"math"
-	"os"
 	"slices"
 )
 
-func main() {
-	// First Problem
-	input := make(chan string)
-	go ReadInput("input1.txt", input)
-	result, err := Solve1(input)
-	if err != nil {
-		panic(err)
-	}
-	fmt.Println(result)
-
-	// Second Problem
-	input = make(chan string)
-	go ReadInput("input2.txt", input)
-	result, err = Solve2(input)
-	if err != nil {
-		panic(err)
-	}
-	fmt.Println(result)
-
-	// Second Problem (revisited)
-	input = make(chan string)
-	go ReadInput("input2.txt", input)
-	result, err = Solve3(input)
-	if err != nil {
-		panic(err)
-	}
-	fmt.Println(result)
-}
-
-// ReadInput reads the input from the filepath and sends each line to the provided channel.
-func ReadInput(filepath string, c chan string) {
-	f, err := os.Open(filepath)
-	if err != nil {
-		panic(err)
-	}
-	defer f.Close()
-
-	r := bufio.NewReader(f)
+var solution = registry.Register(registry.Solution{
+	Year:   2025,
+	Day:    7,
+	Solver: solver.Channel(ReadInput, Solve1, Solve2),
+})
+
+var revisited = registry.Register(registry.Solution{
+	Year:   2025,
+	Day:    7,
+	Name:   "revisited",
+	Solver: solver.Channel(ReadInput, nil, Solve3),
+})
+
+// ReadInput reads the input from r and sends each line to the provided channel.
+// The channel is closed once the input is read, also when reading fails.
+func ReadInput(r io.Reader, c chan string) error {
+	defer close(c)
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := scanner.Text()
 		c <- ParseInput(line)
 	}
-	close(c)
+	return scanner.Err()
 }
 
 // ParseInput parses the input into the necessary data structure.
@@ -61,7 +43,7 @@ func ParseInput(input string) string {
 	return input
 }
 
-func Solve1(input chan string) (string, error) {
+func Solve1(ctx context.Context, input chan string) (string, error) {
 	total := 0
 	idx := [][]int{} // The locations of beams in each row
 	rowNum := 0
@@ -122,7 +104,7 @@ type Path struct {
 	PathCol  int // The column the path travels down
 }
 
-func Solve2(input chan string) (string, error) {
+func Solve2(ctx context.Context, input chan string) (string, error) {
 	total := 0
 	idx := [][]int{} // The locations of beams in each row
 	rowNum := 0
@@ -191,7 +173,7 @@ func Solve2(input chan string) (string, error) {
 
 // Having solved part 2 the slow way, I want to try again
 // without the graph structure
-func Solve3(input chan string) (string, error) {
+func Solve3(ctx context.Context, input chan string) (string, error) {
 	total := 0
 	rows := [][]rune{}
 	totals := [][]int{}
@@ -1,6 +1,7 @@
-package main
+package day7
 
 import (
+	"context"
 	"fmt"
 	"testing"
 )
@@ -54,7 +55,7 @@ func TestSolve1(t *testing.T) {
 					inputChan <- ParseInput(line)
 				}
 			}()
-			result, err := Solve1(inputChan)
+			result, err := Solve1(context.Background(), inputChan)
 			if err != nil {
 				t.Errorf("Unexpected error: %v", err)
 			}
@@ -158,7 +159,7 @@ func TestSolve2(t *testing.T) {
 					inputChan <- ParseInput(line)
 				}
 			}()
-			result, err := Solve2(inputChan)
+			result, err := Solve2(context.Background(), inputChan)
 			if err != nil {
 				t.Errorf("Unexpected error: %v", err)
 			}
@@ -262,7 +263,7 @@ func TestSolve3(t *testing.T) {
 					inputChan <- ParseInput(line)
 				}
 			}()
-			result, err := Solve3(inputChan)
+			result, err := Solve3(context.Background(), inputChan)
 			if err != nil {
 				t.Errorf("Unexpected error: %v", err)
 			}
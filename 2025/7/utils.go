@@ -1,4 +1,4 @@
-package main
+package day7
 
 import (
 	"fmt"
@@ -1,63 +1,56 @@
-package main
+package day8
 
 import (
 	"2ajoyce/adventofcode/2025/8/dsu"
 	"2ajoyce/adventofcode/2025/8/point"
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
 	"bufio"
+	"context"
 	"fmt"
-	"os"
+	"io"
 	"sort"
 )
 
-func main() {
-	// First Problem
-	input := make(chan *point.Point)
-	go ReadInput("input1.txt", input)
-	result, err := Solve1(input, 1000)
-	if err != nil {
-		panic(err)
-	}
-	fmt.Println(result)
+var solution = registry.Register(registry.Solution{
+	Year:   2025,
+	Day:    8,
+	Solver: solver.Channel(ReadInput, solve1, Solve2),
+})
 
-	// Second Problem
-	input = make(chan *point.Point)
-	go ReadInput("input2.txt", input)
-	result, err = Solve2(input)
-	if err != nil {
-		panic(err)
-	}
-	fmt.Println(result)
+// solve1 connects the 1000 closest pairs the puzzle asks for.
+func solve1(ctx context.Context, input chan *point.Point) (string, error) {
+	return Solve1(ctx, input, 1000)
 }
 
-// ReadInput reads the input from the filepath and sends each line to the provided channel.
-func ReadInput(filepath string, c chan *point.Point) {
-	f, err := os.Open(filepath)
-	if err != nil {
-		panic(err)
-	}
-	defer f.Close()
-
-	r := bufio.NewReader(f)
+// ReadInput reads the input from r and sends each line to the provided channel.
+// The channel is closed once the input is read, also when reading fails.
+func ReadInput(r io.Reader, c chan *point.Point) error {
+	defer close(c)
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := scanner.Text()
-		c <- ParseInput(line)
+		p, err := ParseInput(line)
+		if err != nil {
+			return err
+		}
+		c <- p
 	}
-	close(c)
+	return scanner.Err()
 }
 
 // ParseInput parses the input into the necessary data structure.
 // On more complex inputs, this allows us to use lines of text as input for tests
-func ParseInput(input string) *point.Point {
+func ParseInput(input string) (*point.Point, error) {
 	var x, y, z int
 	_, err := fmt.Sscanf(input, "%d,%d,%d", &x, &y, &z)
 	if err != nil {
-		panic(fmt.Sprintf("failed to parse input line %q: %v", input, err))
+		return nil, fmt.Errorf("failed to parse input line %q: %v", input, err)
 	}
-	return point.NewPoint(x, y, z)
+	return point.NewPoint(x, y, z), nil
 }
 
-func Solve1(input chan *point.Point, numConnections int) (string, error) {
+func Solve1(ctx context.Context, input chan *point.Point, numConnections int) (string, error) {
 	total := 0
 	points := []*point.Point{}
 
@@ -116,7 +109,7 @@ func Solve1(input chan *point.Point, numConnections int) (string, error) {
 	return fmt.Sprintf("%d", total), nil
 }
 
-func Solve2(input chan *point.Point) (string, error) {
+func Solve2(ctx context.Context, input chan *point.Point) (string, error) {
 	total := 0
 	points := []*point.Point{}
 
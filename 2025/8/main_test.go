@@ -1,7 +1,8 @@
-package main
+package day8
 
 import (
 	"2ajoyce/adventofcode/2025/8/point"
+	"context"
 	"fmt"
 	"testing"
 )
@@ -42,10 +43,14 @@ func TestSolve1(t *testing.T) {
 			go func() {
 				defer close(inputChan)
 				for _, line := range tc.input {
-					inputChan <- ParseInput(line)
+					parsed, err := ParseInput(line)
+					if err != nil {
+						t.Errorf("Failed to parse %q: %v", line, err)
+					}
+					inputChan <- parsed
 				}
 			}()
-			result, err := Solve1(inputChan, tc.numConnections)
+			result, err := Solve1(context.Background(), inputChan, tc.numConnections)
 			if err != nil {
 				t.Errorf("Unexpected error: %v", err)
 			}
@@ -93,10 +98,14 @@ func TestSolve2(t *testing.T) {
 			go func() {
 				defer close(inputChan)
 				for _, line := range tc.input {
-					inputChan <- ParseInput(line)
+					parsed, err := ParseInput(line)
+					if err != nil {
+						t.Errorf("Failed to parse %q: %v", line, err)
+					}
+					inputChan <- parsed
 				}
 			}()
-			result, err := Solve2(inputChan)
+			result, err := Solve2(context.Background(), inputChan)
 			if err != nil {
 				t.Errorf("Unexpected error: %v", err)
 			}
@@ -1,8 +1,8 @@
 package point
 
 import (
+	"2ajoyce/adventofcode/lib/vec"
 	"fmt"
-	"math"
 	"strconv"
 )
 
@@ -38,11 +38,16 @@ func (p *Point) String() string {
 
 // Calculates the Euclidean distance between two points
 func (p1 *Point) Distance(p2 *Point) float64 {
-	dx := float64(p1.x - p2.x)
-	dy := float64(p1.y - p2.y)
-	dz := float64(p1.z - p2.z)
+	return p1.Vec().Euclidean(p2.Vec())
+}
+
+// Vec returns the point as a vector, for the distance math
+func (p *Point) Vec() vec.Vec3 {
+	return vec.Vec3{X: p.x, Y: p.y, Z: p.z}
+}
 
-	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+func PointFromVec(v vec.Vec3) *Point {
+	return NewPoint(v.X, v.Y, v.Z)
 }
 
 // DistanceTo returns an map of distance -> *Point for all points in the input slice
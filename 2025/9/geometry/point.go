@@ -1,8 +1,8 @@
 package geometry
 
 import (
+	"2ajoyce/adventofcode/lib/vec"
 	"fmt"
-	"math"
 )
 
 type Point struct {
@@ -20,7 +20,14 @@ func (p *Point) String() string {
 
 // The manhattan distance between two points
 func (p *Point) DistanceTo(other *Point) float64 {
-	dx := p.X - other.X
-	dy := p.Y - other.Y
-	return math.Abs(float64(dx)) + math.Abs(float64(dy))
+	return float64(p.Vec().Manhattan(other.Vec()))
+}
+
+// Vec returns the point as a vector, DistanceTo measures with it
+func (p *Point) Vec() vec.Vec2 {
+	return vec.Vec2{X: p.X, Y: p.Y}
+}
+
+func PointFromVec(v vec.Vec2) *Point {
+	return NewPoint(v.X, v.Y)
 }
@@ -1,66 +1,56 @@
-package main
+package day9
 
 import (
 	"2ajoyce/adventofcode/2025/9/geometry"
 	"2ajoyce/adventofcode/2025/9/render"
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
 	"bufio"
+	"context"
 	"fmt"
-	"os"
+	"io"
 
 	"github.com/schollz/progressbar/v3"
 )
 
 // Todo: Refactor Solve2 and supporting methods
 // I almost ran out of time on this solution and I'm leaving this file a mess
-// The solution 
-
-func main() {
-	// First Problem
-	input := make(chan *geometry.Point)
-	go ReadInput("input1.txt", input)
-	result, err := Solve1(input)
-	if err != nil {
-		panic(err)
-	}
-	fmt.Println(result)
-
-	// Second Problem
-	input = make(chan *geometry.Point)
-	go ReadInput("input2.txt", input)
-	result, err = Solve2(input)
-	if err != nil {
-		panic(err)
-	}
-	fmt.Println(result)
-}
-
-// ReadInput reads the input from the filepath and sends each line to the provided channel.
-func ReadInput(filepath string, c chan *geometry.Point) {
-	f, err := os.Open(filepath)
-	if err != nil {
-		panic(err)
-	}
-	defer f.Close()
-
-	r := bufio.NewReader(f)
+// The solution
+
+var solution = registry.Register(registry.Solution{
+	Year:   2025,
+	Day:    9,
+	Solver: solver.Channel(ReadInput, Solve1, Solve2),
+})
+
+// ReadInput reads the input from r and sends each line to the provided channel.
+// The channel is closed once the input is read, also when reading fails.
+func ReadInput(r io.Reader, c chan *geometry.Point) error {
+	defer close(c)
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := scanner.Text()
-		c <- ParseInput(line)
+		p, err := ParseInput(line)
+		if err != nil {
+			return err
+		}
+		c <- p
 	}
-	close(c)
+	return scanner.Err()
 }
 
 // ParseInput parses the input into the necessary data structure.
 // On more complex inputs, this allows us to use lines of text as input for tests
-func ParseInput(input string) *geometry.Point {
+func ParseInput(input string) (*geometry.Point, error) {
 	// input is in the form "x,y"
 	var x, y int
-	fmt.Sscanf(input, "%d,%d", &x, &y)
-	return geometry.NewPoint(x, y)
+	if _, err := fmt.Sscanf(input, "%d,%d", &x, &y); err != nil {
+		return nil, fmt.Errorf("failed to parse input line %q: %v", input, err)
+	}
+	return geometry.NewPoint(x, y), nil
 }
 
-func Solve1(input chan *geometry.Point) (string, error) {
+func Solve1(ctx context.Context, input chan *geometry.Point) (string, error) {
 	total := 0
 	points := []*geometry.Point{}
 	for p := range input {
@@ -95,7 +85,7 @@ type rectResult struct {
 	i, j int
 }
 
-func Solve2(input chan *geometry.Point) (string, error) {
+func Solve2(ctx context.Context, input chan *geometry.Point) (string, error) {
 	points := []*geometry.Point{}
 	for p := range input {
 		points = append(points, p)
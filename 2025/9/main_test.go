@@ -1,7 +1,8 @@
-package main
+package day9
 
 import (
 	"2ajoyce/adventofcode/2025/9/geometry"
+	"context"
 	"fmt"
 	"testing"
 )
@@ -29,10 +30,14 @@ func TestSolve1(t *testing.T) {
 			go func() {
 				defer close(inputChan)
 				for _, point := range tc.input {
-					inputChan <- ParseInput(point)
+					parsed, err := ParseInput(point)
+					if err != nil {
+						t.Errorf("Failed to parse %q: %v", point, err)
+					}
+					inputChan <- parsed
 				}
 			}()
-			result, err := Solve1(inputChan)
+			result, err := Solve1(context.Background(), inputChan)
 			if err != nil {
 				t.Errorf("Unexpected error: %v", err)
 			}
@@ -76,10 +81,14 @@ func TestSolve2(t *testing.T) {
 			go func() {
 				defer close(inputChan)
 				for _, point := range tc.input {
-					inputChan <- ParseInput(point)
+					parsed, err := ParseInput(point)
+					if err != nil {
+						t.Errorf("Failed to parse %q: %v", point, err)
+					}
+					inputChan <- parsed
 				}
 			}()
-			result, err := Solve2(inputChan)
+			result, err := Solve2(context.Background(), inputChan)
 			if err != nil {
 				t.Errorf("Unexpected error: %v", err)
 			}
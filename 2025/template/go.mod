@@ -1,3 +0,0 @@
-module 2ajoyce/adventofcode/2025/template
-
-go 1.25.4
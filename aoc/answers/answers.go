@@ -0,0 +1,145 @@
+// Package answers keeps track of what is known about a day's answers: the one
+// the site accepted, the ones it rejected, and the bounds given by "too high"
+// and "too low" responses. It lives next to the day's code in answers.json.
+package answers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// FileName is the name of the answers file in a day's directory.
+const FileName = "answers.json"
+
+// Part is what is known about the answer to one part of a day.
+type Part struct {
+	Answer  string   `json:"answer,omitempty"`   // Answer accepted by the site
+	Wrong   []string `json:"wrong,omitempty"`    // Answers rejected by the site
+	TooHigh *int64   `json:"too_high,omitempty"` // Smallest answer known to be too high
+	TooLow  *int64   `json:"too_low,omitempty"`  // Largest answer known to be too low
+}
+
+// Answers holds each part of a day, keyed by part number. In the file it looks
+// like:
+//
+//	{"1": {"answer": "4890"}, "2": {"wrong": ["1995", "2000"], "too_low": 1995}}
+type Answers map[int]*Part
+
+// Load reads the answers file in dir. A day without one has no known answers.
+func Load(dir string) (Answers, error) {
+	a := Answers{}
+	data, err := os.ReadFile(filepath.Join(dir, FileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return a, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", filepath.Join(dir, FileName), err)
+	}
+	return a, nil
+}
+
+// Save writes the answers file in dir.
+func (a Answers) Save(dir string) error {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, FileName)
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("error writing %s: %v", path, err)
+	}
+	return nil
+}
+
+// Part returns what is known about a part, adding an empty entry if nothing is.
+func (a Answers) Part(part int) *Part {
+	if a[part] == nil {
+		a[part] = &Part{}
+	}
+	return a[part]
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// CHECKING
+/////////////////////////////////////////////////////////////////////////////////////
+
+// Check returns an error if answer is already known not to be the answer, so it
+// doesn't need to be submitted. That is the case if it was rejected before, if
+// it is outside the too high/too low bounds, or if a different answer was
+// already accepted. Submitting the accepted answer again is refused as well.
+func (p *Part) Check(answer string) error {
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return fmt.Errorf("the answer is empty")
+	}
+	if p.Answer != "" {
+		if answer == p.Answer {
+			return fmt.Errorf("%s was already accepted", answer)
+		}
+		return fmt.Errorf("%s is wrong, %s was already accepted", answer, p.Answer)
+	}
+	if slices.Contains(p.Wrong, answer) {
+		return fmt.Errorf("%s was already rejected", answer)
+	}
+	n, err := strconv.ParseInt(answer, 10, 64)
+	if err != nil {
+		// Only numbers can be compared against the bounds
+		return nil
+	}
+	if p.TooHigh != nil && n >= *p.TooHigh {
+		return fmt.Errorf("%s is too high, %d already was", answer, *p.TooHigh)
+	}
+	if p.TooLow != nil && n <= *p.TooLow {
+		return fmt.Errorf("%s is too low, %d already was", answer, *p.TooLow)
+	}
+	return nil
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// RECORDING
+/////////////////////////////////////////////////////////////////////////////////////
+
+// RecordCorrect records answer as the accepted answer, the golden value later
+// runs are checked against.
+func (p *Part) RecordCorrect(answer string) {
+	p.Answer = strings.TrimSpace(answer)
+}
+
+// RecordWrong records that answer was rejected without a hint.
+func (p *Part) RecordWrong(answer string) {
+	answer = strings.TrimSpace(answer)
+	if !slices.Contains(p.Wrong, answer) {
+		p.Wrong = append(p.Wrong, answer)
+	}
+}
+
+// RecordTooHigh records that answer was rejected for being too high, lowering
+// the upper bound if it is below the current one.
+func (p *Part) RecordTooHigh(answer string) {
+	p.RecordWrong(answer)
+	if n, err := strconv.ParseInt(strings.TrimSpace(answer), 10, 64); err == nil {
+		if p.TooHigh == nil || n < *p.TooHigh {
+			p.TooHigh = &n
+		}
+	}
+}
+
+// RecordTooLow records that answer was rejected for being too low, raising the
+// lower bound if it is above the current one.
+func (p *Part) RecordTooLow(answer string) {
+	p.RecordWrong(answer)
+	if n, err := strconv.ParseInt(strings.TrimSpace(answer), 10, 64); err == nil {
+		if p.TooLow == nil || n > *p.TooLow {
+			p.TooLow = &n
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package answers
+
+import (
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	p := &Part{}
+	p.RecordWrong("abc")
+	p.RecordTooHigh("500")
+	p.RecordTooHigh("800")
+	p.RecordTooLow("100")
+	p.RecordTooLow("50")
+
+	tests := []struct {
+		answer  string
+		wantErr bool
+	}{
+		{"", true},
+		{"abc", true},
+		{"500", true},
+		{"650", true},
+		{"100", true},
+		{"99", true},
+		{"101", false},
+		{"499", false},
+		{" 250\n", false},
+		{"def", false},
+	}
+	for _, tt := range tests {
+		if err := p.Check(tt.answer); (err != nil) != tt.wantErr {
+			t.Errorf("Check(%q) error = %v, wantErr %v", tt.answer, err, tt.wantErr)
+		}
+	}
+
+	p.RecordCorrect("250")
+	if err := p.Check("250"); err == nil {
+		t.Errorf("Expected an accepted answer not to be submitted again")
+	}
+	if err := p.Check("251"); err == nil {
+		t.Errorf("Expected any other answer to be wrong once one was accepted")
+	}
+}
+
+func TestRecordBounds(t *testing.T) {
+	p := &Part{}
+	p.RecordTooHigh("800")
+	p.RecordTooHigh("500")
+	p.RecordTooHigh("900")
+	p.RecordTooLow("50")
+	p.RecordTooLow("100")
+	p.RecordTooLow("10")
+
+	if *p.TooHigh != 500 || *p.TooLow != 100 {
+		t.Errorf("Expected the bounds to be 100 and 500, got %d and %d", *p.TooLow, *p.TooHigh)
+	}
+	if len(p.Wrong) != 6 {
+		t.Errorf("Expected every answer to be recorded as wrong, got %v", p.Wrong)
+	}
+}
+
+func TestLoadSave(t *testing.T) {
+	dir := t.TempDir()
+	a, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Expected a missing answers file to load as empty, got %v", err)
+	}
+	a.Part(1).RecordCorrect("4890")
+	a.Part(2).RecordTooLow("1995")
+	if err := a.Save(dir); err != nil {
+		t.Fatalf("Failed to save answers: %v", err)
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Failed to load answers: %v", err)
+	}
+	if loaded.Part(1).Answer != "4890" {
+		t.Errorf("Expected part 1 to be 4890, got %q", loaded.Part(1).Answer)
+	}
+	if p := loaded.Part(2); p.TooLow == nil || *p.TooLow != 1995 || len(p.Wrong) != 1 {
+		t.Errorf("Unexpected part 2 %+v", p)
+	}
+}
@@ -0,0 +1,335 @@
+// Package bench measures how long each phase of a solution takes and how much
+// memory it uses, and compares the numbers with a saved baseline.
+// The phases are parsing the input and solving each part. Parsing is measured
+// on the input already read into memory, and every part is given a freshly
+// parsed input that is not part of its measurement.
+package bench
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/metrics"
+	"sort"
+	"time"
+
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
+)
+
+// Config controls how a solution is benchmarked.
+type Config struct {
+	Runs        int // Number of times each phase is run
+	Parallelism int // Worker count handed to solutions that fan out
+}
+
+// Measurement summarises the runs of one phase.
+type Measurement struct {
+	Runs     int           `json:"runs"`
+	Wall     time.Duration `json:"wall_ns"`         // Mean wall time of a run
+	MinWall  time.Duration `json:"min_wall_ns"`     // Fastest run
+	Allocs   uint64        `json:"allocs"`          // Mean number of heap allocations of a run
+	Bytes    uint64        `json:"bytes"`           // Mean number of bytes allocated by a run
+	PeakHeap uint64        `json:"peak_heap_bytes"` // Largest live heap reached during any run, above what was live before it
+}
+
+// Result is the measurement of one phase of a solution.
+type Result struct {
+	Solution *registry.Solution
+	Phase    string // parse, part1 or part2
+	Measurement
+	Err error
+}
+
+// Key identifies the phase in a baseline, for example 2024/6/part1.
+func (r Result) Key() string {
+	if r.Solution.Name != "" {
+		return fmt.Sprintf("%d/%d/%s/%s", r.Solution.Year, r.Solution.Day, r.Solution.Name, r.Phase)
+	}
+	return fmt.Sprintf("%d/%d/%s", r.Solution.Year, r.Solution.Day, r.Phase)
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// MEASURING
+/////////////////////////////////////////////////////////////////////////////////////
+
+// Solution benchmarks the parse phase and every part a solution solves.
+// Parts are run on their default input file, and parsing is measured on the
+// input of the first part. Parts without an input are left out.
+func Solution(s *registry.Solution, cfg Config) []Result {
+	if cfg.Runs < 1 {
+		cfg.Runs = 1
+	}
+	opts := solver.Options{Parallelism: cfg.Parallelism}
+	if opts.Parallelism < 1 {
+		opts.Parallelism = 1
+	}
+
+	var results []Result
+	parsed := false
+	for part := 1; part <= 2; part++ {
+		if !s.Solves(part) {
+			continue
+		}
+		data, err := os.ReadFile(s.InputFile(part))
+		if err != nil {
+			continue
+		}
+		parse := func() (solver.Input, error) {
+			return s.Solver.Parse(bytes.NewReader(data))
+		}
+
+		if !parsed {
+			parsed = true
+			m, err := measure(cfg.Runs, func() (solver.Input, error) { return nil, nil }, func(solver.Input) error {
+				_, err := parse()
+				return err
+			})
+			results = append(results, Result{Solution: s, Phase: "parse", Measurement: m, Err: err})
+		}
+
+		m, err := measure(cfg.Runs, parse, func(input solver.Input) error {
+			_, err := solver.Solve(context.Background(), s.Solver, part, input, opts)
+			return err
+		})
+		results = append(results, Result{Solution: s, Phase: fmt.Sprintf("part%d", part), Measurement: m, Err: err})
+	}
+	return results
+}
+
+// measure calls run n times on what setup returns, only measuring run.
+// It stops at the first error.
+func measure(n int, setup func() (solver.Input, error), run func(solver.Input) error) (Measurement, error) {
+	m := Measurement{}
+	var totalWall time.Duration
+	var totalAllocs, totalBytes uint64
+	var before, after runtime.MemStats
+
+	for i := 0; i < n; i++ {
+		input, err := setup()
+		if err != nil {
+			return m, err
+		}
+
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		sampler := startHeapSampler()
+		start := time.Now()
+		err = run(input)
+		wall := time.Since(start)
+		peak := sampler.stop()
+		runtime.ReadMemStats(&after)
+		if err != nil {
+			return m, err
+		}
+
+		m.Runs++
+		totalWall += wall
+		if m.MinWall == 0 || wall < m.MinWall {
+			m.MinWall = wall
+		}
+		totalAllocs += after.Mallocs - before.Mallocs
+		totalBytes += after.TotalAlloc - before.TotalAlloc
+		if peak > before.HeapAlloc && peak-before.HeapAlloc > m.PeakHeap {
+			m.PeakHeap = peak - before.HeapAlloc
+		}
+	}
+
+	m.Wall = totalWall / time.Duration(m.Runs)
+	m.Allocs = totalAllocs / uint64(m.Runs)
+	m.Bytes = totalBytes / uint64(m.Runs)
+	return m, nil
+}
+
+// heapMetric is the live heap, which unlike runtime.ReadMemStats can be read
+// without stopping the world.
+const heapMetric = "/memory/classes/heap/objects:bytes"
+
+// heapSampler polls the size of the heap until it is stopped, keeping the
+// largest value seen.
+type heapSampler struct {
+	done chan struct{}
+	peak chan uint64
+}
+
+func startHeapSampler() *heapSampler {
+	h := &heapSampler{done: make(chan struct{}), peak: make(chan uint64)}
+	go func() {
+		sample := []metrics.Sample{{Name: heapMetric}}
+		var peak uint64
+		read := func() {
+			metrics.Read(sample)
+			if v := sample[0].Value.Uint64(); v > peak {
+				peak = v
+			}
+		}
+
+		ticker := time.NewTicker(time.Millisecond)
+		defer ticker.Stop()
+		for {
+			read()
+			select {
+			case <-h.done:
+				read()
+				h.peak <- peak
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return h
+}
+
+func (h *heapSampler) stop() uint64 {
+	close(h.done)
+	return <-h.peak
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// BASELINE
+/////////////////////////////////////////////////////////////////////////////////////
+
+// Baseline holds earlier measurements by Result.Key.
+type Baseline map[string]Measurement
+
+// LoadBaseline reads a baseline written by Save.
+func LoadBaseline(path string) (Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	b := Baseline{}
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", path, err)
+	}
+	return b, nil
+}
+
+// Update records the successful results in the baseline, keeping the earlier
+// measurements of phases that were not run.
+func (b Baseline) Update(results []Result) {
+	for _, r := range results {
+		if r.Err == nil {
+			b[r.Key()] = r.Measurement
+		}
+	}
+}
+
+// Save writes the baseline to path.
+func (b Baseline) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("error writing %s: %v", path, err)
+	}
+	return nil
+}
+
+// Regression is a phase that got slower or allocates more than its baseline
+// by more than the threshold.
+type Regression struct {
+	Result   Result
+	Baseline Measurement
+	Wall     float64 // Relative change of the mean wall time, 0.5 is 50% slower
+	Allocs   float64 // Relative change of the number of allocations
+}
+
+func (r Regression) String() string {
+	return fmt.Sprintf("%s: wall time %+.0f%% (%s -> %s), allocations %+.0f%% (%d -> %d)",
+		r.Result.Key(), r.Wall*100, r.Baseline.Wall, r.Result.Wall, r.Allocs*100, r.Baseline.Allocs, r.Result.Allocs)
+}
+
+// Compare returns the results that regressed by more than threshold compared to
+// the baseline. A threshold of 0.2 allows phases to be 20% slower.
+func (b Baseline) Compare(results []Result, threshold float64) []Regression {
+	var regressions []Regression
+	for _, r := range results {
+		base, ok := b[r.Key()]
+		if !ok || r.Err != nil {
+			continue
+		}
+		reg := Regression{
+			Result:   r,
+			Baseline: base,
+			Wall:     change(float64(base.Wall), float64(r.Wall)),
+			Allocs:   change(float64(base.Allocs), float64(r.Allocs)),
+		}
+		if reg.Wall > threshold || reg.Allocs > threshold {
+			regressions = append(regressions, reg)
+		}
+	}
+	sort.Slice(regressions, func(i, j int) bool { return regressions[i].Wall > regressions[j].Wall })
+	return regressions
+}
+
+func change(before, after float64) float64 {
+	if before == 0 {
+		return 0
+	}
+	return (after - before) / before
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// REPORTING
+/////////////////////////////////////////////////////////////////////////////////////
+
+// Markdown writes the results as a table to paste into a README. With a
+// baseline, the change in wall time is added as the last column.
+func Markdown(w io.Writer, results []Result, baseline Baseline) {
+	header := "| Day | Phase | Wall time | Fastest | Allocations | Allocated | Peak heap |"
+	separator := "| :-- | :---- | --------: | ------: | ----------: | --------: | --------: |"
+	if baseline != nil {
+		header += " vs baseline |"
+		separator += " ----------: |"
+	}
+	fmt.Fprintln(w, header)
+	fmt.Fprintln(w, separator)
+
+	for _, r := range results {
+		fmt.Fprintf(w, "| %s | %s |", r.Solution, r.Phase)
+		if r.Err != nil {
+			fmt.Fprintf(w, " error: %v | | | | |", r.Err)
+		} else {
+			fmt.Fprintf(w, " %s | %s | %d | %s | %s |", round(r.Wall), round(r.MinWall), r.Allocs, bytesString(r.Bytes), bytesString(r.PeakHeap))
+		}
+		if baseline != nil {
+			if base, ok := baseline[r.Key()]; ok && r.Err == nil {
+				fmt.Fprintf(w, " %+.0f%% |", change(float64(base.Wall), float64(r.Wall))*100)
+			} else {
+				fmt.Fprint(w, " |")
+			}
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// round keeps durations readable in a table.
+func round(d time.Duration) time.Duration {
+	switch {
+	case d > time.Second:
+		return d.Round(time.Millisecond)
+	case d > time.Millisecond:
+		return d.Round(time.Microsecond)
+	default:
+		return d
+	}
+}
+
+func bytesString(b uint64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := uint64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
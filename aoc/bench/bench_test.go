@@ -0,0 +1,119 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
+)
+
+var sink [][]byte
+
+func newSolution(t *testing.T) *registry.Solution {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "input.txt"), []byte("3\n"), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+	return &registry.Solution{
+		Year: 1999,
+		Day:  1,
+		Dir:  dir,
+		Solver: solver.New(
+			func(r io.Reader) (string, error) {
+				data, err := io.ReadAll(r)
+				return string(data), err
+			},
+			func(_ context.Context, input string, opts solver.Options) (string, error) {
+				// Allocate a megabyte that stays live until the part is done
+				sink = make([][]byte, 0, 16)
+				for i := 0; i < 16; i++ {
+					sink = append(sink, make([]byte, 64*1024))
+				}
+				sink = nil
+				return input, nil
+			},
+			nil,
+		),
+	}
+}
+
+func TestSolution(t *testing.T) {
+	results := Solution(newSolution(t), Config{Runs: 3})
+	if len(results) != 2 {
+		t.Fatalf("Expected parse and part 1 to be measured, got %d results", len(results))
+	}
+	if results[0].Phase != "parse" || results[1].Phase != "part1" {
+		t.Errorf("Unexpected phases %s and %s", results[0].Phase, results[1].Phase)
+	}
+	if key := results[1].Key(); key != "1999/1/part1" {
+		t.Errorf("Unexpected key %s", key)
+	}
+
+	part1 := results[1]
+	if part1.Err != nil || part1.Runs != 3 {
+		t.Fatalf("Expected 3 runs of part 1, got %+v", part1)
+	}
+	if part1.Allocs < 16 || part1.Bytes < 1024*1024 {
+		t.Errorf("Expected at least 16 allocations of a megabyte in total, got %d and %d bytes", part1.Allocs, part1.Bytes)
+	}
+	if part1.Wall < part1.MinWall {
+		t.Errorf("Expected the mean wall time %s to be at least the fastest %s", part1.Wall, part1.MinWall)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	s := &registry.Solution{Year: 1999, Day: 1}
+	baseline := Baseline{
+		"1999/1/parse": {Wall: 100 * time.Millisecond, Allocs: 100},
+		"1999/1/part1": {Wall: 100 * time.Millisecond, Allocs: 100},
+		"1999/1/part2": {Wall: 100 * time.Millisecond, Allocs: 100},
+	}
+	results := []Result{
+		{Solution: s, Phase: "parse", Measurement: Measurement{Wall: 110 * time.Millisecond, Allocs: 100}},
+		{Solution: s, Phase: "part1", Measurement: Measurement{Wall: 150 * time.Millisecond, Allocs: 100}},
+		{Solution: s, Phase: "part2", Measurement: Measurement{Wall: 50 * time.Millisecond, Allocs: 200}},
+	}
+
+	regressions := baseline.Compare(results, 0.2)
+	if len(regressions) != 2 {
+		t.Fatalf("Expected part 1 and part 2 to regress, got %v", regressions)
+	}
+	if regressions[0].Result.Phase != "part1" || regressions[0].Wall != 0.5 {
+		t.Errorf("Expected part 1 to be 50%% slower first, got %v", regressions[0])
+	}
+	if regressions[1].Result.Phase != "part2" || regressions[1].Allocs != 1 {
+		t.Errorf("Expected part 2 to allocate twice as much, got %v", regressions[1])
+	}
+}
+
+func TestBaseline(t *testing.T) {
+	s := &registry.Solution{Year: 1999, Day: 1, Name: "alt"}
+	path := filepath.Join(t.TempDir(), "bench.json")
+	baseline := Baseline{"1999/1/alt/part2": {Runs: 1, Wall: time.Second}}
+	baseline.Update([]Result{{Solution: s, Phase: "part1", Measurement: Measurement{Runs: 5, Wall: time.Millisecond}}})
+	if err := baseline.Save(path); err != nil {
+		t.Fatalf("Failed to save baseline: %v", err)
+	}
+
+	loaded, err := LoadBaseline(path)
+	if err != nil {
+		t.Fatalf("Failed to load baseline: %v", err)
+	}
+	if len(loaded) != 2 || loaded["1999/1/alt/part1"].Wall != time.Millisecond || loaded["1999/1/alt/part2"].Wall != time.Second {
+		t.Errorf("Unexpected baseline %+v", loaded)
+	}
+
+	var buf bytes.Buffer
+	Markdown(&buf, []Result{{Solution: s, Phase: "part1", Measurement: Measurement{Wall: 2 * time.Millisecond, Bytes: 2048}}}, loaded)
+	table := buf.String()
+	if !strings.Contains(table, "| 1999 Day 1 (alt) | part1 | 2ms |") || !strings.Contains(table, "| 2.0 KiB |") || !strings.HasSuffix(table, " +100% |\n") {
+		t.Errorf("Unexpected table:\n%s", table)
+	}
+}
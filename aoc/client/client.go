@@ -0,0 +1,209 @@
+// Package client talks to the Advent of Code website on behalf of the runner.
+// Every request is authenticated with the session cookie from the config file,
+// identifies itself with a User-Agent, and is spaced out from the previous one.
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultBaseURL     = "https://adventofcode.com"
+	DefaultMinInterval = 5 * time.Second
+	userAgent          = "github.com/2ajoyce/adventofcode/aoc"
+)
+
+/////////////////////////////////////////////////////////////////////////////////////
+// CONFIG
+/////////////////////////////////////////////////////////////////////////////////////
+
+// Config is read from a JSON file, by default aoc/config.json in the user's
+// config directory:
+//
+//	{"session": "53616c7465645f5f...", "contact": "me@example.com"}
+type Config struct {
+	Session string `json:"session"`           // Value of the session cookie set when logging in to the site
+	Contact string `json:"contact,omitempty"` // Added to the User-Agent so the site can reach whoever runs the tool
+	BaseURL string `json:"base_url,omitempty"`
+}
+
+// DefaultConfigPath returns where the config file lives unless told otherwise.
+func DefaultConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "aoc", "config.json"), nil
+}
+
+// LoadConfig reads the config file at path.
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("error parsing %s: %v", path, err)
+	}
+	cfg.Session = strings.TrimSpace(cfg.Session)
+	if cfg.Session == "" {
+		return cfg, fmt.Errorf("%s has no session token", path)
+	}
+	return cfg, nil
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// CLIENT
+/////////////////////////////////////////////////////////////////////////////////////
+
+type Client struct {
+	BaseURL     string
+	Session     string
+	UserAgent   string
+	MinInterval time.Duration // Minimum time between two requests
+	// StateFile remembers when the last request was made so that the interval
+	// is also respected across separate runs. Leave empty to only throttle
+	// within the process.
+	StateFile  string
+	HTTPClient *http.Client
+
+	mu          sync.Mutex
+	lastRequest time.Time
+	now         func() time.Time
+	sleep       func(time.Duration)
+}
+
+// New returns a client for the site described by cfg.
+func New(cfg Config) *Client {
+	c := &Client{
+		BaseURL:     cfg.BaseURL,
+		Session:     cfg.Session,
+		UserAgent:   userAgent,
+		MinInterval: DefaultMinInterval,
+		HTTPClient:  &http.Client{Timeout: 30 * time.Second},
+		now:         time.Now,
+		sleep:       time.Sleep,
+	}
+	if c.BaseURL == "" {
+		c.BaseURL = DefaultBaseURL
+	}
+	if cfg.Contact != "" {
+		c.UserAgent += " by " + cfg.Contact
+	}
+	if dir, err := os.UserCacheDir(); err == nil {
+		c.StateFile = filepath.Join(dir, "aoc", "last-request")
+	}
+	return c
+}
+
+// Input downloads the puzzle input for a day.
+func (c *Client) Input(year, day int) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/%d/day/%d/input", c.BaseURL, year, day), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading input for %d day %d: %v", year, day, err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return body, nil
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("input for %d day %d is not available yet", year, day)
+	case resp.StatusCode == http.StatusBadRequest || resp.StatusCode >= 500:
+		// The site answers both of these when the session cookie is wrong or expired
+		return nil, fmt.Errorf("error fetching input for %d day %d: %s, check the session token", year, day, resp.Status)
+	default:
+		return nil, fmt.Errorf("error fetching input for %d day %d: %s", year, day, resp.Status)
+	}
+}
+
+// do sends the request with the session cookie and User-Agent, waiting first
+// if the previous request was too recent.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.wait()
+
+	req.Header.Set("User-Agent", c.UserAgent)
+	req.AddCookie(&http.Cookie{Name: "session", Value: c.Session})
+	resp, err := c.HTTPClient.Do(req)
+	c.recordRequest()
+	if err != nil {
+		return nil, fmt.Errorf("error requesting %s: %v", req.URL, err)
+	}
+	return resp, nil
+}
+
+func (c *Client) wait() {
+	last := c.lastRequest
+	if c.StateFile != "" {
+		if info, err := os.Stat(c.StateFile); err == nil && info.ModTime().After(last) {
+			last = info.ModTime()
+		}
+	}
+	if last.IsZero() {
+		return
+	}
+	if remaining := c.MinInterval - c.now().Sub(last); remaining > 0 {
+		c.sleep(remaining)
+	}
+}
+
+func (c *Client) recordRequest() {
+	c.lastRequest = c.now()
+	if c.StateFile == "" {
+		return
+	}
+	// The state file's modification time is the time of the last request
+	if err := os.MkdirAll(filepath.Dir(c.StateFile), 0755); err != nil {
+		return
+	}
+	if err := os.WriteFile(c.StateFile, nil, 0644); err != nil {
+		return
+	}
+	os.Chtimes(c.StateFile, c.lastRequest, c.lastRequest)
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// CACHE
+/////////////////////////////////////////////////////////////////////////////////////
+
+// InputFile is the name inputs are cached under in a day's directory. The runner
+// falls back to it when a day has no input1.txt or input2.txt.
+const InputFile = "input.txt"
+
+// FetchInput returns the path of the cached input for a day, downloading it
+// into dir first if it is not there yet.
+func (c *Client) FetchInput(year, day int, dir string) (string, error) {
+	path := filepath.Join(dir, InputFile)
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	input, err := c.Input(year, day)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, input, 0644); err != nil {
+		return "", fmt.Errorf("error caching input in %s: %v", path, err)
+	}
+	return path, nil
+}
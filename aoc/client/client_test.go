@@ -0,0 +1,175 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSite stands in for the Advent of Code website.
+type fakeSite struct {
+	*httptest.Server
+	requests []*http.Request
+}
+
+func newFakeSite(t *testing.T, handler http.HandlerFunc) *fakeSite {
+	site := &fakeSite{}
+	site.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		site.requests = append(site.requests, r)
+		handler(w, r)
+	}))
+	t.Cleanup(site.Close)
+	return site
+}
+
+func newTestClient(site *fakeSite) *Client {
+	c := New(Config{Session: "secret", Contact: "me@example.com", BaseURL: site.URL})
+	c.StateFile = ""
+	c.MinInterval = 0
+	return c
+}
+
+func TestInput(t *testing.T) {
+	site := newFakeSite(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/2024/day/6/input" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("....#.....\n"))
+	})
+	c := newTestClient(site)
+
+	input, err := c.Input(2024, 6)
+	if err != nil {
+		t.Fatalf("Failed to fetch input: %v", err)
+	}
+	if string(input) != "....#.....\n" {
+		t.Errorf("Unexpected input %q", input)
+	}
+
+	req := site.requests[0]
+	if cookie, err := req.Cookie("session"); err != nil || cookie.Value != "secret" {
+		t.Errorf("Expected the session cookie to be sent, got %v", req.Header.Get("Cookie"))
+	}
+	if ua := req.Header.Get("User-Agent"); !strings.HasPrefix(ua, "github.com/2ajoyce/adventofcode") || !strings.HasSuffix(ua, "by me@example.com") {
+		t.Errorf("Unexpected User-Agent %q", ua)
+	}
+
+	if _, err := c.Input(2024, 7); err == nil || !strings.Contains(err.Error(), "not available yet") {
+		t.Errorf("Expected a missing input to report it is not available yet, got %v", err)
+	}
+}
+
+func TestInputBadSession(t *testing.T) {
+	site := newFakeSite(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Puzzle inputs differ by user.  Please log in to get your puzzle input.", http.StatusBadRequest)
+	})
+	c := newTestClient(site)
+
+	if _, err := c.Input(2024, 6); err == nil || !strings.Contains(err.Error(), "session token") {
+		t.Errorf("Expected an error about the session token, got %v", err)
+	}
+}
+
+func TestMinInterval(t *testing.T) {
+	site := newFakeSite(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("input"))
+	})
+	c := newTestClient(site)
+	c.MinInterval = time.Minute
+
+	now := time.Date(2024, 12, 6, 5, 0, 0, 0, time.UTC)
+	var slept []time.Duration
+	c.now = func() time.Time { return now }
+	c.sleep = func(d time.Duration) {
+		slept = append(slept, d)
+		now = now.Add(d)
+	}
+
+	c.Input(2024, 6)
+	now = now.Add(20 * time.Second)
+	c.Input(2024, 7)
+
+	if len(slept) != 1 || slept[0] != 40*time.Second {
+		t.Errorf("Expected a single 40s wait before the second request, got %v", slept)
+	}
+	if len(site.requests) != 2 {
+		t.Errorf("Expected 2 requests, got %d", len(site.requests))
+	}
+}
+
+func TestMinIntervalAcrossRuns(t *testing.T) {
+	site := newFakeSite(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("input"))
+	})
+	stateFile := filepath.Join(t.TempDir(), "last-request")
+
+	now := time.Now()
+	var slept time.Duration
+	newClient := func() *Client {
+		c := newTestClient(site)
+		c.StateFile = stateFile
+		c.MinInterval = DefaultMinInterval
+		c.now = func() time.Time { return now }
+		c.sleep = func(d time.Duration) { slept += d }
+		return c
+	}
+
+	newClient().Input(2024, 6)
+	now = now.Add(2 * time.Second)
+	newClient().Input(2024, 7)
+
+	if slept != DefaultMinInterval-2*time.Second {
+		t.Errorf("Expected the second client to wait %s, waited %s", DefaultMinInterval-2*time.Second, slept)
+	}
+}
+
+func TestFetchInputCaches(t *testing.T) {
+	site := newFakeSite(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("1   2\n"))
+	})
+	c := newTestClient(site)
+	dir := t.TempDir()
+
+	for i := 0; i < 2; i++ {
+		path, err := c.FetchInput(2024, 1, dir)
+		if err != nil {
+			t.Fatalf("Failed to fetch input: %v", err)
+		}
+		if path != filepath.Join(dir, "input.txt") {
+			t.Errorf("Expected the input to be cached as input.txt, got %s", path)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil || string(data) != "1   2\n" {
+			t.Errorf("Unexpected cached input %q (%v)", data, err)
+		}
+	}
+	if len(site.requests) != 1 {
+		t.Errorf("Expected the second fetch to use the cache, got %d requests", len(site.requests))
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"session": " secret\n", "contact": "me@example.com"}`), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.Session != "secret" || cfg.Contact != "me@example.com" {
+		t.Errorf("Unexpected config %+v", cfg)
+	}
+
+	if err := os.WriteFile(path, []byte(`{}`), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if _, err := LoadConfig(path); err == nil {
+		t.Errorf("Expected a config without a session token to be rejected")
+	}
+}
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Outcome is how the site responded to a submitted answer.
+type Outcome int
+
+const (
+	Unknown       Outcome = iota // The response page was not recognised
+	Correct                      // The answer was accepted
+	Incorrect                    // The answer was rejected without a hint
+	TooHigh                      // The answer was rejected for being too high
+	TooLow                       // The answer was rejected for being too low
+	TooRecent                    // Nothing was checked, an answer was given too recently
+	AlreadySolved                // Nothing was checked, the part is already solved or not unlocked yet
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case Correct:
+		return "correct"
+	case Incorrect:
+		return "incorrect"
+	case TooHigh:
+		return "too high"
+	case TooLow:
+		return "too low"
+	case TooRecent:
+		return "too recent"
+	case AlreadySolved:
+		return "already solved"
+	default:
+		return "unknown"
+	}
+}
+
+// Response is the parsed response page for a submitted answer.
+type Response struct {
+	Outcome Outcome
+	Wait    time.Duration // How long the site asks to wait before the next answer
+	Message string        // Text of the response page's article, without markup
+}
+
+// Submit posts answer for a part of a day and parses the response page.
+func (c *Client) Submit(year, day, part int, answer string) (Response, error) {
+	form := url.Values{"level": {strconv.Itoa(part)}, "answer": {answer}}
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/%d/day/%d/answer", c.BaseURL, year, day), strings.NewReader(form.Encode()))
+	if err != nil {
+		return Response{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return Response{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Response{}, fmt.Errorf("error reading response for %d day %d: %v", year, day, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Response{}, fmt.Errorf("error submitting answer for %d day %d: %s, check the session token", year, day, resp.Status)
+	}
+
+	r := ParseResponse(string(body))
+	if r.Outcome == Unknown {
+		return r, fmt.Errorf("unrecognised response for %d day %d: %q", year, day, r.Message)
+	}
+	return r, nil
+}
+
+var (
+	article  = regexp.MustCompile(`(?s)<article[^>]*>(.*?)</article>`)
+	tag      = regexp.MustCompile(`<[^>]*>`)
+	space    = regexp.MustCompile(`\s+`)
+	leftWait = regexp.MustCompile(`You have (?:(\d+)m )?(\d+)s left to wait`)
+	tryAgain = regexp.MustCompile(`[Pp]lease wait (one|\d+) minutes? before trying again`)
+)
+
+// ParseResponse works out the outcome from the text of a response page.
+func ParseResponse(page string) Response {
+	text := page
+	if m := article.FindStringSubmatch(page); m != nil {
+		text = m[1]
+	}
+	text = html.UnescapeString(tag.ReplaceAllString(text, ""))
+	r := Response{Message: strings.TrimSpace(space.ReplaceAllString(text, " "))}
+
+	switch {
+	case strings.Contains(r.Message, "That's the right answer"):
+		r.Outcome = Correct
+	case strings.Contains(r.Message, "your answer is too high"):
+		r.Outcome = TooHigh
+	case strings.Contains(r.Message, "your answer is too low"):
+		r.Outcome = TooLow
+	case strings.Contains(r.Message, "That's not the right answer"):
+		r.Outcome = Incorrect
+	case strings.Contains(r.Message, "You gave an answer too recently"):
+		r.Outcome = TooRecent
+	case strings.Contains(r.Message, "You don't seem to be solving the right level"):
+		r.Outcome = AlreadySolved
+	}
+
+	if m := leftWait.FindStringSubmatch(r.Message); m != nil {
+		minutes, _ := strconv.Atoi(m[1])
+		seconds, _ := strconv.Atoi(m[2])
+		r.Wait = time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+	} else if m := tryAgain.FindStringSubmatch(r.Message); m != nil {
+		minutes := 1
+		if m[1] != "one" {
+			minutes, _ = strconv.Atoi(m[1])
+		}
+		r.Wait = time.Duration(minutes) * time.Minute
+	}
+	return r
+}
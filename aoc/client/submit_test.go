@@ -0,0 +1,71 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// Response pages are trimmed down to the article the outcome is read from.
+const (
+	correctPage   = `<main><article><p>That's the right answer!  You are <em class="star">one gold star</em> closer to finding the Chief Historian. <a href="/2024/day/6#part2">[Continue to Part Two]</a></p></article></main>`
+	tooHighPage   = `<main><article><p>That's not the right answer; your answer is too high.  If you're stuck, make sure you're using the full input data; there are also some general tips on the <a href="/2024/about">about page</a>, or you can ask for hints on the <a href="https://www.reddit.com/r/adventofcode/" target="_blank">subreddit</a>.  Please wait one minute before trying again. <a href="/2024/day/6">[Return to Day 6]</a></p></article></main>`
+	tooLowPage    = `<main><article><p>That's not the right answer; your answer is too low.  Please wait 5 minutes before trying again. <a href="/2024/day/6">[Return to Day 6]</a></p></article></main>`
+	incorrectPage = `<main><article><p>That's not the right answer.  If you're stuck, make sure you're using the full input data.  Please wait one minute before trying again. <a href="/2024/day/6">[Return to Day 6]</a></p></article></main>`
+	tooRecentPage = `<main><article><p>You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have 4m 32s left to wait. <a href="/2024/day/6">[Return to Day 6]</a></p></article></main>`
+	solvedPage    = `<main><article><p>You don't seem to be solving the right level.  Did you already complete it? <a href="/2024/day/6">[Return to Day 6]</a></p></article></main>`
+)
+
+func TestParseResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		page    string
+		outcome Outcome
+		wait    time.Duration
+	}{
+		{"correct", correctPage, Correct, 0},
+		{"too high", tooHighPage, TooHigh, time.Minute},
+		{"too low", tooLowPage, TooLow, 5 * time.Minute},
+		{"incorrect", incorrectPage, Incorrect, time.Minute},
+		{"too recent", tooRecentPage, TooRecent, 4*time.Minute + 32*time.Second},
+		{"already solved", solvedPage, AlreadySolved, 0},
+		{"unknown", "<html><body>Something else</body></html>", Unknown, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := ParseResponse(tt.page)
+			if r.Outcome != tt.outcome {
+				t.Errorf("Expected outcome %s, got %s", tt.outcome, r.Outcome)
+			}
+			if r.Wait != tt.wait {
+				t.Errorf("Expected to wait %s, got %s", tt.wait, r.Wait)
+			}
+		})
+	}
+}
+
+func TestSubmit(t *testing.T) {
+	site := newFakeSite(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/2024/day/6/answer" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.FormValue("level") != "2" || r.FormValue("answer") != "1995" {
+			w.Write([]byte(incorrectPage))
+			return
+		}
+		w.Write([]byte(correctPage))
+	})
+	c := newTestClient(site)
+
+	r, err := c.Submit(2024, 6, 2, "1995")
+	if err != nil {
+		t.Fatalf("Failed to submit answer: %v", err)
+	}
+	if r.Outcome != Correct {
+		t.Errorf("Expected the answer to be correct, got %s: %s", r.Outcome, r.Message)
+	}
+	if r, _ := c.Submit(2024, 6, 1, "1995"); r.Outcome != Incorrect {
+		t.Errorf("Expected the answer to part 1 to be incorrect, got %s", r.Outcome)
+	}
+}
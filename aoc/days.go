@@ -0,0 +1,41 @@
+package main
+
+// Every day registers itself with the registry when its package is
+// initialized, so importing a day is all it takes to make it runnable.
+import (
+	_ "2ajoyce/adventofcode/2024/1"
+	_ "2ajoyce/adventofcode/2024/2"
+	_ "2ajoyce/adventofcode/2024/3"
+	_ "2ajoyce/adventofcode/2024/4"
+	_ "2ajoyce/adventofcode/2024/5"
+	_ "2ajoyce/adventofcode/2024/day10"
+	_ "2ajoyce/adventofcode/2024/day11"
+	_ "2ajoyce/adventofcode/2024/day12"
+	_ "2ajoyce/adventofcode/2024/day13"
+	_ "2ajoyce/adventofcode/2024/day14"
+	_ "2ajoyce/adventofcode/2024/day15"
+	_ "2ajoyce/adventofcode/2024/day16"
+	_ "2ajoyce/adventofcode/2024/day17"
+	_ "2ajoyce/adventofcode/2024/day18"
+	_ "2ajoyce/adventofcode/2024/day19"
+	_ "2ajoyce/adventofcode/2024/day20"
+	_ "2ajoyce/adventofcode/2024/day21"
+	_ "2ajoyce/adventofcode/2024/day6"
+	_ "2ajoyce/adventofcode/2024/day6_2"
+	_ "2ajoyce/adventofcode/2024/day7"
+	_ "2ajoyce/adventofcode/2024/day8"
+	_ "2ajoyce/adventofcode/2024/day9"
+
+	_ "2ajoyce/adventofcode/2025/1"
+	_ "2ajoyce/adventofcode/2025/10"
+	_ "2ajoyce/adventofcode/2025/11"
+	_ "2ajoyce/adventofcode/2025/12"
+	_ "2ajoyce/adventofcode/2025/2"
+	_ "2ajoyce/adventofcode/2025/3"
+	_ "2ajoyce/adventofcode/2025/4"
+	_ "2ajoyce/adventofcode/2025/5"
+	_ "2ajoyce/adventofcode/2025/6"
+	_ "2ajoyce/adventofcode/2025/7"
+	_ "2ajoyce/adventofcode/2025/8"
+	_ "2ajoyce/adventofcode/2025/9"
+)
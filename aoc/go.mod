@@ -0,0 +1,3 @@
+module 2ajoyce/adventofcode/aoc
+
+go 1.25.4
@@ -0,0 +1,505 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"2ajoyce/adventofcode/aoc/answers"
+	"2ajoyce/adventofcode/aoc/bench"
+	"2ajoyce/adventofcode/aoc/client"
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/runner"
+	"2ajoyce/adventofcode/aoc/scaffold"
+	"2ajoyce/adventofcode/aoc/verify"
+)
+
+const usage = `Usage: aoc <command> [arguments]
+
+Commands:
+  run <year> <day> [--part N] [--input path] [--parallelism N] [--name name] [--config path]
+      [--timeout duration] [--cpuprofile] [--memprofile] [--trace] [--blockprofile]
+      [--record path] [--record-every N] [--record-scale N] [--play] [--fps F] [--cast path]
+  fetch <year> <day> [--config path]
+  submit <year> <day> <part> [answer] [--input path] [--parallelism N] [--name name] [--config path]
+  verify [year [day]] [--parallelism N] [--timeout duration]
+  bench [year [day]] [--runs N] [--parallelism N] [--baseline path] [--update] [--threshold F]
+  list
+  new <year> <day> [--kind lines|grid|graph]
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "run":
+		err = runCommand(os.Args[2:])
+	case "fetch":
+		err = fetchCommand(os.Args[2:])
+	case "submit":
+		err = submitCommand(os.Args[2:])
+	case "verify":
+		err = verifyCommand(os.Args[2:])
+	case "bench":
+		err = benchCommand(os.Args[2:])
+	case "list":
+		err = listCommand(os.Args[2:])
+	case "new":
+		err = newCommand(os.Args[2:])
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+func runCommand(args []string) error {
+	flags := flag.NewFlagSet("run", flag.ExitOnError)
+	part := flags.Int("part", 0, "part to run, 0 runs both")
+	input := flags.String("input", "", "input file, defaults to the day's input.txt")
+	parallelism := flags.Int("parallelism", 1, "worker count for solutions that fan out")
+	name := flags.String("name", "", "name of an alternative solution for the day")
+	configPath := flags.String("config", "", "config file holding the session token, used to fetch missing inputs")
+	timeout := flags.Duration("timeout", 0, "cancel a part that runs longer than this, for example 30s")
+	var profiles runner.Profiles
+	flags.BoolVar(&profiles.CPU, "cpuprofile", false, "write a CPU profile of each part to the day's profiles directory")
+	flags.BoolVar(&profiles.Memory, "memprofile", false, "write a memory profile of each part to the day's profiles directory")
+	flags.BoolVar(&profiles.Trace, "trace", false, "write an execution trace of each part to the day's profiles directory")
+	flags.BoolVar(&profiles.Block, "blockprofile", false, "write a blocking profile of each part to the day's profiles directory")
+	var recording runner.Recording
+	flags.StringVar(&recording.Path, "record", "", "record the parts that support it as out.gif, or as numbered PNGs for out.png")
+	flags.IntVar(&recording.Every, "record-every", 1, "keep one recorded frame out of every N")
+	flags.IntVar(&recording.Scale, "record-scale", 4, "pixels per cell in recorded frames")
+	var play runner.Playback
+	flags.BoolVar(&play.Terminal, "play", false, "play the parts that support it back in the terminal")
+	flags.Float64Var(&play.FPS, "fps", 10, "frames per second when playing back, 0 plays as fast as possible")
+	flags.StringVar(&play.Cast, "cast", "", "save the playback as an asciicast v2 file")
+
+	positional, err := parseInterspersed(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 {
+		return fmt.Errorf("run takes a year and a day, got %v", positional)
+	}
+	year, day, err := parseYearDay(positional[0], positional[1])
+	if err != nil {
+		return err
+	}
+	if *part < 0 || *part > 2 {
+		return fmt.Errorf("part must be 1 or 2, got %d", *part)
+	}
+
+	solution, err := registry.Lookup(year, day, *name)
+	if err != nil {
+		return err
+	}
+
+	cfg := runner.Config{
+		Part:        *part,
+		InputFile:   *input,
+		Parallelism: *parallelism,
+		Profiles:    profiles,
+		Timeout:     *timeout,
+		Recording:   recording,
+		Playback:    play,
+	}
+	// Inputs are only fetched automatically once a session token is configured
+	if c, err := loadClient(*configPath); err == nil {
+		cfg.Fetcher = c
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	results := runner.Run(ctx, solution, cfg)
+	runner.Print(os.Stdout, solution, results)
+
+	for _, r := range results {
+		if r.Err != nil {
+			return fmt.Errorf("part %d failed", r.Part)
+		}
+	}
+	return nil
+}
+
+func fetchCommand(args []string) error {
+	flags := flag.NewFlagSet("fetch", flag.ExitOnError)
+	configPath := flags.String("config", "", "config file holding the session token")
+
+	positional, err := parseInterspersed(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 {
+		return fmt.Errorf("fetch takes a year and a day, got %v", positional)
+	}
+	year, day, err := parseYearDay(positional[0], positional[1])
+	if err != nil {
+		return err
+	}
+	solution, err := registry.Lookup(year, day, "")
+	if err != nil {
+		return err
+	}
+	c, err := loadClient(*configPath)
+	if err != nil {
+		return err
+	}
+
+	path, err := c.FetchInput(year, day, solution.Dir)
+	if err != nil {
+		return err
+	}
+	fmt.Println(path)
+	return nil
+}
+
+func submitCommand(args []string) error {
+	flags := flag.NewFlagSet("submit", flag.ExitOnError)
+	input := flags.String("input", "", "input file to solve when no answer is given, defaults to the day's input.txt")
+	parallelism := flags.Int("parallelism", 1, "worker count for solutions that fan out")
+	name := flags.String("name", "", "name of an alternative solution for the day")
+	configPath := flags.String("config", "", "config file holding the session token")
+
+	positional, err := parseInterspersed(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 3 && len(positional) != 4 {
+		return fmt.Errorf("submit takes a year, a day, a part and optionally the answer, got %v", positional)
+	}
+	year, day, err := parseYearDay(positional[0], positional[1])
+	if err != nil {
+		return err
+	}
+	part, err := strconv.Atoi(positional[2])
+	if err != nil || part < 1 || part > 2 {
+		return fmt.Errorf("part must be 1 or 2, got %s", positional[2])
+	}
+	solution, err := registry.Lookup(year, day, *name)
+	if err != nil {
+		return err
+	}
+	c, err := loadClient(*configPath)
+	if err != nil {
+		return err
+	}
+
+	// Without an answer on the command line, the solution is run to get one
+	var answer string
+	if len(positional) == 4 {
+		answer = positional[3]
+	} else {
+		results := runner.Run(context.Background(), solution, runner.Config{
+			Part:        part,
+			InputFile:   *input,
+			Parallelism: *parallelism,
+			Fetcher:     c,
+		})
+		if len(results) == 0 {
+			return fmt.Errorf("%s does not solve part %d", solution, part)
+		}
+		if results[0].Err != nil {
+			return fmt.Errorf("error solving part %d: %v", part, results[0].Err)
+		}
+		answer = results[0].Answer
+	}
+
+	resp, err := submitAnswer(c, solution.Dir, year, day, part, answer)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s part %d: %s is %s\n", solution, part, answer, resp.Outcome)
+	fmt.Println(resp.Message)
+	if resp.Outcome != client.Correct {
+		return fmt.Errorf("the answer was not accepted")
+	}
+	return nil
+}
+
+// submitAnswer submits answer unless the day's answers file already rules it
+// out, then records the outcome in the answers file in dir.
+func submitAnswer(c *client.Client, dir string, year, day, part int, answer string) (client.Response, error) {
+	answer = strings.TrimSpace(answer)
+	known, err := answers.Load(dir)
+	if err != nil {
+		return client.Response{}, err
+	}
+	p := known.Part(part)
+	if err := p.Check(answer); err != nil {
+		return client.Response{}, fmt.Errorf("not submitting: %v", err)
+	}
+
+	resp, err := c.Submit(year, day, part, answer)
+	if err != nil {
+		return resp, err
+	}
+	switch resp.Outcome {
+	case client.Correct:
+		p.RecordCorrect(answer)
+	case client.TooHigh:
+		p.RecordTooHigh(answer)
+	case client.TooLow:
+		p.RecordTooLow(answer)
+	case client.Incorrect:
+		p.RecordWrong(answer)
+	default:
+		// The answer was not checked, so there is nothing to record
+		return resp, nil
+	}
+	return resp, known.Save(dir)
+}
+
+// loadClient builds a client from the config file at path, or the default
+// config file if path is empty.
+func loadClient(path string) (*client.Client, error) {
+	if path == "" {
+		var err error
+		if path, err = client.DefaultConfigPath(); err != nil {
+			return nil, fmt.Errorf("no config file: %w", fs.ErrNotExist)
+		}
+	}
+	cfg, err := client.LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return client.New(cfg), nil
+}
+
+func verifyCommand(args []string) error {
+	flags := flag.NewFlagSet("verify", flag.ExitOnError)
+	parallelism := flags.Int("parallelism", 1, "worker count for solutions that fan out")
+	timeout := flags.Duration("timeout", 0, "fail a part that runs longer than this, for example 30s")
+
+	positional, err := parseInterspersed(flags, args)
+	if err != nil {
+		return err
+	}
+	solutions, err := selectSolutions("verify", positional)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	var results []verify.Result
+	for _, s := range solutions {
+		results = append(results, verify.Solution(ctx, s, *parallelism, *timeout)...)
+	}
+	verify.Print(os.Stdout, results)
+	if verify.Failed(results) {
+		return fmt.Errorf("some answers differ from the golden answers")
+	}
+	return nil
+}
+
+func benchCommand(args []string) error {
+	flags := flag.NewFlagSet("bench", flag.ExitOnError)
+	runs := flags.Int("runs", 5, "number of times each phase is run")
+	parallelism := flags.Int("parallelism", 1, "worker count for solutions that fan out")
+	baselinePath := flags.String("baseline", "", "baseline to compare with, defaults to bench.json in the repository root")
+	update := flags.Bool("update", false, "save the results to the baseline")
+	threshold := flags.Float64("threshold", 0.2, "relative slowdown or increase in allocations reported as a regression")
+
+	positional, err := parseInterspersed(flags, args)
+	if err != nil {
+		return err
+	}
+	solutions, err := selectSolutions("bench", positional)
+	if err != nil {
+		return err
+	}
+	if *baselinePath == "" {
+		root, err := findRoot()
+		if err != nil {
+			return err
+		}
+		*baselinePath = filepath.Join(root, "bench.json")
+	}
+	baseline, err := bench.LoadBaseline(*baselinePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		// The first run becomes the baseline
+		*update = true
+	} else if err != nil {
+		return err
+	}
+
+	var results []bench.Result
+	for _, s := range solutions {
+		fmt.Fprintf(os.Stderr, "Benchmarking %s\n", s)
+		results = append(results, bench.Solution(s, bench.Config{Runs: *runs, Parallelism: *parallelism})...)
+	}
+	bench.Markdown(os.Stdout, results, baseline)
+
+	regressions := baseline.Compare(results, *threshold)
+	if len(regressions) > 0 {
+		fmt.Printf("\n%d regression(s) above %.0f%%:\n", len(regressions), *threshold*100)
+		for _, r := range regressions {
+			fmt.Printf("  %s\n", r)
+		}
+	}
+
+	if *update {
+		if baseline == nil {
+			baseline = bench.Baseline{}
+		}
+		baseline.Update(results)
+		if err := baseline.Save(*baselinePath); err != nil {
+			return err
+		}
+		fmt.Printf("\nSaved baseline to %s\n", *baselinePath)
+	} else if len(regressions) > 0 {
+		return fmt.Errorf("%d phase(s) regressed", len(regressions))
+	}
+	return nil
+}
+
+// selectSolutions returns the registered solutions for the optional year and
+// day given to a command, or all of them.
+func selectSolutions(command string, positional []string) ([]*registry.Solution, error) {
+	if len(positional) > 2 {
+		return nil, fmt.Errorf("%s takes at most a year and a day, got %v", command, positional)
+	}
+	year, day := 0, 0
+	var err error
+	if len(positional) == 1 {
+		if year, err = strconv.Atoi(positional[0]); err != nil {
+			return nil, fmt.Errorf("invalid year %q: %v", positional[0], err)
+		}
+	}
+	if len(positional) == 2 {
+		if year, day, err = parseYearDay(positional[0], positional[1]); err != nil {
+			return nil, err
+		}
+	}
+
+	var solutions []*registry.Solution
+	for _, s := range registry.All() {
+		if (year == 0 || s.Year == year) && (day == 0 || s.Day == day) {
+			solutions = append(solutions, s)
+		}
+	}
+	return solutions, nil
+}
+
+func listCommand(args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("list takes no arguments")
+	}
+	for _, s := range registry.All() {
+		var parts string
+		for part := 1; part <= 2; part++ {
+			if s.Solves(part) {
+				parts += fmt.Sprintf(" %d", part)
+			}
+		}
+		fmt.Printf("%s: parts%s\n", s.String(), parts)
+	}
+	return nil
+}
+
+func newCommand(args []string) error {
+	flags := flag.NewFlagSet("new", flag.ExitOnError)
+	kind := flags.String("kind", "lines", "parser skeleton to generate: lines, grid or graph")
+
+	positional, err := parseInterspersed(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 {
+		return fmt.Errorf("new takes a year and a day, got %v", positional)
+	}
+	year, day, err := parseYearDay(positional[0], positional[1])
+	if err != nil {
+		return err
+	}
+	k, err := scaffold.ParseKind(*kind)
+	if err != nil {
+		return err
+	}
+	root, err := findRoot()
+	if err != nil {
+		return err
+	}
+
+	result, err := scaffold.New(scaffold.Day{Root: root, Year: year, Day: day, Kind: k})
+	if err != nil {
+		return err
+	}
+	for _, f := range result.Created {
+		fmt.Printf("Created: %s\n", f)
+	}
+	for _, f := range result.Skipped {
+		fmt.Printf("Skipping existing file: %s\n", f)
+	}
+	fmt.Printf("Done. Created %d file(s), skipped %d existing file(s).\n", len(result.Created), len(result.Skipped))
+	return nil
+}
+
+// findRoot returns the root of the repository, the closest directory above the
+// working directory that holds go.work.
+func findRoot() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.work")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no go.work found above the working directory")
+		}
+		dir = parent
+	}
+}
+
+// parseInterspersed parses flags that may appear before, between or after the
+// positional arguments, which the flag package does not allow on its own.
+func parseInterspersed(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		if flags.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+}
+
+func parseYearDay(yearArg, dayArg string) (int, int, error) {
+	year, err := strconv.Atoi(yearArg)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid year %q: %v", yearArg, err)
+	}
+	day, err := strconv.Atoi(dayArg)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid day %q: %v", dayArg, err)
+	}
+	if day < 1 || day > 25 {
+		return 0, 0, fmt.Errorf("day must be between 1 and 25, got %d", day)
+	}
+	return year, day, nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"2ajoyce/adventofcode/aoc/answers"
+	"2ajoyce/adventofcode/aoc/client"
+)
+
+func TestSubmitAnswer(t *testing.T) {
+	// The fake site knows the answer is 42 and hints at it
+	submitted := 0
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		submitted++
+		var page string
+		switch answer := r.FormValue("answer"); {
+		case answer == "42":
+			page = "<article><p>That's the right answer!</p></article>"
+		case answer == "abc":
+			page = "<article><p>That's not the right answer.</p></article>"
+		case len(answer) > 2 || answer > "42":
+			page = "<article><p>That's not the right answer; your answer is too high.</p></article>"
+		default:
+			page = "<article><p>That's not the right answer; your answer is too low.</p></article>"
+		}
+		w.Write([]byte(page))
+	}))
+	defer site.Close()
+	c := client.New(client.Config{Session: "secret", BaseURL: site.URL})
+	c.StateFile = ""
+	c.MinInterval = 0
+	dir := t.TempDir()
+
+	tests := []struct {
+		answer    string
+		outcome   client.Outcome
+		submitted bool
+	}{
+		{"50", client.TooHigh, true},
+		{"60", client.Unknown, false},
+		{"10", client.TooLow, true},
+		{"5", client.Unknown, false},
+		{"abc", client.Incorrect, true},
+		{"abc", client.Unknown, false},
+		{"42", client.Correct, true},
+		{"42", client.Unknown, false},
+	}
+	for _, tt := range tests {
+		before := submitted
+		resp, err := submitAnswer(c, dir, 2024, 6, 1, tt.answer)
+		if tt.submitted != (submitted > before) {
+			t.Errorf("Submitting %s: expected submitted to be %v", tt.answer, tt.submitted)
+		}
+		if !tt.submitted {
+			if err == nil {
+				t.Errorf("Expected %s to be refused", tt.answer)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Failed to submit %s: %v", tt.answer, err)
+		}
+		if resp.Outcome != tt.outcome {
+			t.Errorf("Submitting %s: expected %s, got %s", tt.answer, tt.outcome, resp.Outcome)
+		}
+	}
+
+	known, err := answers.Load(dir)
+	if err != nil {
+		t.Fatalf("Failed to load answers: %v", err)
+	}
+	if p := known.Part(1); p.Answer != "42" || len(p.Wrong) != 3 || *p.TooHigh != 50 || *p.TooLow != 10 {
+		t.Errorf("Unexpected answers recorded %+v", p)
+	}
+}
@@ -0,0 +1,110 @@
+package registry
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+
+	"2ajoyce/adventofcode/aoc/solver"
+)
+
+// Solution is everything the runner needs to know about a day.
+type Solution struct {
+	Year   int
+	Day    int
+	Name   string // Distinguishes alternative solutions to the same day, empty for the main one
+	Dir    string // Directory holding the day's source and input files, filled in by Register
+	Solver solver.Solver
+}
+
+// Solves reports whether the solution solves the part.
+func (s *Solution) Solves(part int) bool {
+	return solver.Solves(s.Solver, part)
+}
+
+// InputFile returns the default input file for a part of the solution.
+// 2025 days keep a file per part (input1.txt, input2.txt) while 2024 days use a
+// single input.txt, so the part specific file wins if it exists.
+func (s *Solution) InputFile(part int) string {
+	partFile := filepath.Join(s.Dir, fmt.Sprintf("input%d.txt", part))
+	if _, err := os.Stat(partFile); err == nil {
+		return partFile
+	}
+	return filepath.Join(s.Dir, "input.txt")
+}
+
+func (s *Solution) String() string {
+	if s.Name != "" {
+		return fmt.Sprintf("%d Day %d (%s)", s.Year, s.Day, s.Name)
+	}
+	return fmt.Sprintf("%d Day %d", s.Year, s.Day)
+}
+
+type key struct {
+	year, day int
+	name      string
+}
+
+var (
+	mu        sync.RWMutex
+	solutions = make(map[key]*Solution)
+)
+
+// Register adds a solution to the registry and returns it.
+// It is meant to be called while initializing a day's package, and records the
+// directory of the calling file as the day's directory. Registering the same
+// year, day and name twice panics.
+func Register(s Solution) *Solution {
+	if _, file, _, ok := runtime.Caller(1); ok {
+		s.Dir = filepath.Dir(file)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	k := key{year: s.Year, day: s.Day, name: s.Name}
+	if _, exists := solutions[k]; exists {
+		panic(fmt.Sprintf("solution for %s is already registered", s.String()))
+	}
+	solutions[k] = &s
+	return &s
+}
+
+// Lookup returns the registered solution for a day.
+func Lookup(year, day int, name string) (*Solution, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	s, ok := solutions[key{year: year, day: day, name: name}]
+	if !ok {
+		if name != "" {
+			return nil, fmt.Errorf("no solution named %q registered for %d day %d", name, year, day)
+		}
+		return nil, fmt.Errorf("no solution registered for %d day %d", year, day)
+	}
+	return s, nil
+}
+
+// All returns every registered solution ordered by year, day and name.
+func All() []*Solution {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	all := make([]*Solution, 0, len(solutions))
+	for _, s := range solutions {
+		all = append(all, s)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Year != all[j].Year {
+			return all[i].Year < all[j].Year
+		}
+		if all[i].Day != all[j].Day {
+			return all[i].Day < all[j].Day
+		}
+		return all[i].Name < all[j].Name
+	})
+	return all
+}
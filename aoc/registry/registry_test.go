@@ -0,0 +1,46 @@
+package registry
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"2ajoyce/adventofcode/aoc/solver"
+)
+
+func TestRegisterAndLookup(t *testing.T) {
+	parse := func(lines []string) ([]string, error) { return lines, nil }
+	part := func(_ context.Context, lines []string, parallelism int) ([]string, error) { return []string{"42"}, nil }
+	registered := Register(Solution{Year: 1999, Day: 1, Solver: solver.Lines(parse, part, nil)})
+
+	_, file, _, _ := runtime.Caller(0)
+	if registered.Dir != filepath.Dir(file) {
+		t.Errorf("Expected Dir to be %s, got %s", filepath.Dir(file), registered.Dir)
+	}
+
+	s, err := Lookup(1999, 1, "")
+	if err != nil {
+		t.Fatalf("Failed to look up solution: %v", err)
+	}
+	if s != registered {
+		t.Errorf("Expected Lookup to return the registered solution")
+	}
+	if !s.Solves(1) || s.Solves(2) {
+		t.Errorf("Expected only part 1 to be solved")
+	}
+
+	if _, err := Lookup(1999, 2, ""); err == nil {
+		t.Errorf("Expected an error looking up an unregistered day")
+	}
+}
+
+func TestRegisterDuplicatePanics(t *testing.T) {
+	Register(Solution{Year: 1999, Day: 2})
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected registering the same day twice to panic")
+		}
+	}()
+	Register(Solution{Year: 1999, Day: 2})
+}
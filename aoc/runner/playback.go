@@ -0,0 +1,74 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"2ajoyce/adventofcode/lib/playback"
+)
+
+// Playback selects how the simulations of each part are played back. Solutions
+// draw frames through playback.FromContext, parts that draw none leave no cast.
+type Playback struct {
+	Terminal bool      // Redraw frames in place on Output
+	FPS      float64   // Frames per second, 0 draws as fast as possible
+	Cast     string    // asciicast v2 file to save the frames to, empty saves nothing
+	Output   io.Writer // Where frames are drawn, os.Stdout when nil
+	Input    io.Reader // Read to resume after a pause, os.Stdin when nil
+}
+
+func (p Playback) enabled() bool {
+	return p.Terminal || p.Cast != ""
+}
+
+// playing is the playback of a single part
+type playing struct {
+	player *playback.Player
+	cast   *os.File
+}
+
+// start returns a context carrying a new player for a part
+func (p Playback) start(ctx context.Context, part int, both bool) (context.Context, *playing, error) {
+	options := []playback.Option{playback.WithFrameRate(p.FPS)}
+	if p.Terminal {
+		output, input := p.Output, p.Input
+		if output == nil {
+			output = os.Stdout
+		}
+		if input == nil {
+			input = os.Stdin
+		}
+		options = append(options, playback.WithTerminal(output), playback.WithInput(input))
+	}
+	pl := &playing{}
+	if p.Cast != "" {
+		path := partPath(p.Cast, part, both)
+		f, err := os.Create(path)
+		if err != nil {
+			return ctx, nil, fmt.Errorf("error creating %s: %v", path, err)
+		}
+		pl.cast = f
+		options = append(options, playback.WithCast(f))
+	}
+	pl.player = playback.New(options...)
+	return playback.NewContext(ctx, pl.player), pl, nil
+}
+
+// stop restores the terminal and closes the cast, returning its path. A cast
+// without frames is removed.
+func (pl *playing) stop() (string, error) {
+	err := pl.player.Close()
+	if pl.cast == nil {
+		return "", err
+	}
+	path := pl.cast.Name()
+	if closeErr := pl.cast.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("error writing %s: %v", path, closeErr)
+	}
+	if pl.player.Frames() == 0 {
+		return "", os.Remove(path)
+	}
+	return path, err
+}
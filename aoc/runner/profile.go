@@ -0,0 +1,134 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"time"
+
+	"2ajoyce/adventofcode/aoc/registry"
+)
+
+// ProfileDir is the directory profiles are written to inside a day's directory.
+const ProfileDir = "profiles"
+
+// Profiles selects the profiles captured while each part is parsed and solved.
+// They are written to the day's profiles directory, named after the time the run
+// started and the part, for example profiles/20241217-093000-part2.cpu.pprof.
+type Profiles struct {
+	CPU    bool
+	Memory bool
+	Trace  bool
+	Block  bool
+}
+
+func (p Profiles) enabled() bool {
+	return p.CPU || p.Memory || p.Trace || p.Block
+}
+
+// profiler captures the profiles of a single part.
+type profiler struct {
+	Profiles
+	prefix  string
+	cpu     *os.File
+	trace   *os.File
+	written []string
+}
+
+// startProfiles starts capturing the selected profiles of a part.
+func startProfiles(p Profiles, s *registry.Solution, part int, started time.Time) (*profiler, error) {
+	dir := filepath.Join(s.Dir, ProfileDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("error creating %s: %v", dir, err)
+	}
+	pr := &profiler{
+		Profiles: p,
+		prefix:   filepath.Join(dir, fmt.Sprintf("%s-part%d", started.Format("20060102-150405"), part)),
+	}
+
+	if p.CPU {
+		f, err := pr.create("cpu.pprof")
+		if err != nil {
+			return nil, err
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("error starting CPU profile: %v", err)
+		}
+		pr.cpu = f
+	}
+	if p.Trace {
+		f, err := pr.create("trace.out")
+		if err != nil {
+			pr.stop()
+			return nil, err
+		}
+		if err := trace.Start(f); err != nil {
+			f.Close()
+			pr.stop()
+			return nil, fmt.Errorf("error starting trace: %v", err)
+		}
+		pr.trace = f
+	}
+	if p.Block {
+		// Record every blocking event, the workers in a day only block on
+		// channels and wait groups so this stays cheap
+		runtime.SetBlockProfileRate(1)
+	}
+	return pr, nil
+}
+
+func (pr *profiler) create(suffix string) (*os.File, error) {
+	path := pr.prefix + "." + suffix
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("error creating %s: %v", path, err)
+	}
+	pr.written = append(pr.written, path)
+	return f, nil
+}
+
+// stop stops every profile and writes the ones taken at the end, returning the
+// files written.
+func (pr *profiler) stop() ([]string, error) {
+	var firstErr error
+	keep := func(err error) {
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if pr.cpu != nil {
+		pprof.StopCPUProfile()
+		keep(pr.cpu.Close())
+	}
+	if pr.trace != nil {
+		trace.Stop()
+		keep(pr.trace.Close())
+	}
+	if pr.Block {
+		keep(pr.writeProfile("block", "block.pprof"))
+		runtime.SetBlockProfileRate(0)
+	}
+	if pr.Memory {
+		// Bring the allocation statistics up to date
+		runtime.GC()
+		keep(pr.writeProfile("allocs", "mem.pprof"))
+	}
+	return pr.written, firstErr
+}
+
+func (pr *profiler) writeProfile(name, suffix string) error {
+	f, err := pr.create(suffix)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := pprof.Lookup(name).WriteTo(f, 0); err != nil {
+		return fmt.Errorf("error writing %s profile: %v", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"2ajoyce/adventofcode/lib/record"
+)
+
+// Recording selects the animation captured while each part is solved. Solutions
+// add frames through record.FromContext, parts that add none write nothing.
+type Recording struct {
+	Path  string // .gif file, or .png file numbered per frame, empty records nothing
+	Every int    // Keep one frame out of every Every, 0 keeps them all
+	Scale int    // Pixels per cell, 0 uses the recorder's default
+}
+
+func (r Recording) enabled() bool {
+	return r.Path != ""
+}
+
+// start returns a context carrying a new recorder for a part
+func (r Recording) start(ctx context.Context) (context.Context, *record.Recorder) {
+	recorder := record.New(record.WithFrameSkip(r.Every), record.WithScale(r.Scale))
+	return record.NewContext(ctx, recorder), recorder
+}
+
+// save writes the frames of a part, returning the files written. Errors while
+// capturing are reported even when they kept every frame out.
+func (r Recording) save(recorder *record.Recorder, part int, both bool) ([]string, error) {
+	if err := recorder.Err(); err != nil {
+		return nil, fmt.Errorf("error recording part %d: %v", part, err)
+	}
+	if recorder.Len() == 0 {
+		return nil, nil
+	}
+	written, err := recorder.Save(partPath(r.Path, part, both))
+	if err != nil {
+		return written, fmt.Errorf("error recording part %d: %v", part, err)
+	}
+	return written, nil
+}
+
+// partPath adds the part to a file name when both parts run, so the second part
+// does not overwrite the first. out.gif becomes out-part1.gif.
+func partPath(path string, part int, both bool) string {
+	if !both {
+		return path
+	}
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s-part%d%s", strings.TrimSuffix(path, ext), part, ext)
+}
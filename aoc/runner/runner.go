@@ -0,0 +1,242 @@
+package runner
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
+	"2ajoyce/adventofcode/lib/record"
+)
+
+// Config controls how a solution is run.
+type Config struct {
+	Part        int    // Part to run, 0 runs both
+	InputFile   string // Overrides the solution's default input file
+	Parallelism int    // Worker count handed to solutions that fan out
+	// Fetcher downloads the day's input when the default input file is missing.
+	// Leave nil to fail instead.
+	Fetcher Fetcher
+	// Profiles selects the profiles captured for each part.
+	Profiles Profiles
+	// Timeout cancels a part that takes longer to solve, 0 lets it run until
+	// it is done or the context given to Run is cancelled.
+	Timeout time.Duration
+	// Recording selects the animation captured for each part.
+	Recording Recording
+	// Playback selects how the simulations of each part are played back.
+	Playback Playback
+}
+
+// Fetcher downloads the input for a day into dir and returns its path.
+type Fetcher interface {
+	FetchInput(year, day int, dir string) (string, error)
+}
+
+// Result is the outcome of running a single part.
+type Result struct {
+	Part          int
+	InputFile     string
+	Answer        string
+	ParseDuration time.Duration
+	Duration      time.Duration
+	Profiles      []string // Profile files written for the part
+	Recording     []string // Animation files written for the part
+	Cast          string   // asciicast file written for the part
+	Err           error
+}
+
+// Run runs the requested parts of a solution and times each of them.
+// Parts the solution does not implement are skipped. The input is parsed again
+// for every part since parts may modify what they are given.
+// Parts are cancelled when ctx is done or when they run into cfg.Timeout.
+func Run(ctx context.Context, s *registry.Solution, cfg Config) []Result {
+	opts := solver.Options{Parallelism: cfg.Parallelism}
+	if opts.Parallelism < 1 {
+		opts.Parallelism = 1
+	}
+
+	started := time.Now()
+	var results []Result
+	for _, part := range parts(cfg.Part) {
+		if !s.Solves(part) {
+			continue
+		}
+
+		inputFile := cfg.InputFile
+		if inputFile == "" {
+			inputFile = s.InputFile(part)
+			if _, err := os.Stat(inputFile); os.IsNotExist(err) && cfg.Fetcher != nil {
+				fetched, err := cfg.Fetcher.FetchInput(s.Year, s.Day, s.Dir)
+				if err != nil {
+					results = append(results, Result{Part: part, InputFile: inputFile, Err: err})
+					continue
+				}
+				inputFile = fetched
+			}
+		}
+		results = append(results, runPart(ctx, s, part, inputFile, opts, cfg, started))
+	}
+	return results
+}
+
+// runPart solves a part, capturing the selected profiles, recording and
+// playback while it runs.
+func runPart(ctx context.Context, s *registry.Solution, part int, inputFile string, opts solver.Options, cfg Config, started time.Time) Result {
+	both := cfg.Part == 0 && s.Solves(1) && s.Solves(2)
+	var recorder *record.Recorder
+	if cfg.Recording.enabled() {
+		ctx, recorder = cfg.Recording.start(ctx)
+	}
+	var pl *playing
+	if cfg.Playback.enabled() {
+		var err error
+		if ctx, pl, err = cfg.Playback.start(ctx, part, both); err != nil {
+			return Result{Part: part, InputFile: inputFile, Err: err}
+		}
+	}
+
+	result := profilePart(ctx, s, part, inputFile, opts, cfg, started)
+	keep := func(err error) {
+		if err != nil && result.Err == nil {
+			result.Err = err
+		}
+	}
+	if pl != nil {
+		var err error
+		result.Cast, err = pl.stop()
+		keep(err)
+	}
+	if recorder != nil {
+		var err error
+		result.Recording, err = cfg.Recording.save(recorder, part, both)
+		keep(err)
+	}
+	return result
+}
+
+// profilePart solves a part, capturing the selected profiles while it runs.
+func profilePart(ctx context.Context, s *registry.Solution, part int, inputFile string, opts solver.Options, cfg Config, started time.Time) Result {
+	if !cfg.Profiles.enabled() {
+		return solvePart(ctx, s, part, inputFile, opts, cfg.Timeout)
+	}
+	pr, err := startProfiles(cfg.Profiles, s, part, started)
+	if err != nil {
+		return Result{Part: part, InputFile: inputFile, Err: err}
+	}
+	result := solvePart(ctx, s, part, inputFile, opts, cfg.Timeout)
+	result.Profiles, err = pr.stop()
+	if err != nil && result.Err == nil {
+		result.Err = err
+	}
+	return result
+}
+
+func solvePart(ctx context.Context, s *registry.Solution, part int, inputFile string, opts solver.Options, timeout time.Duration) Result {
+	result := Result{Part: part, InputFile: inputFile}
+
+	f, err := os.Open(inputFile)
+	if err != nil {
+		result.Err = fmt.Errorf("error reading input: %v", err)
+		return result
+	}
+	defer f.Close()
+
+	start := time.Now()
+	input, err := s.Solver.Parse(f)
+	result.ParseDuration = time.Since(start)
+	if err != nil {
+		result.Err = fmt.Errorf("error parsing input: %v", err)
+		return result
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	start = time.Now()
+	result.Answer, result.Err = solver.Solve(ctx, s.Solver, part, input, opts)
+	result.Duration = time.Since(start)
+	if errors.Is(result.Err, context.DeadlineExceeded) {
+		result.Err = fmt.Errorf("timed out after %s: %v", timeout, result.Err)
+	}
+	return result
+}
+
+func parts(part int) []int {
+	if part == 0 {
+		return []int{1, 2}
+	}
+	return []int{part}
+}
+
+// Print writes the results of a run in the same format for every year.
+// Answers spanning several lines are indented underneath the part header.
+func Print(w io.Writer, s *registry.Solution, results []Result) {
+	fmt.Fprintln(w, s.String())
+	if len(results) == 0 {
+		fmt.Fprintln(w, "  No parts solved")
+		return
+	}
+	for _, r := range results {
+		lines := strings.Split(r.Answer, "\n")
+		switch {
+		case r.Err != nil:
+			fmt.Fprintf(w, "  Part %d: error: %v\n", r.Part, r.Err)
+		case len(lines) == 1:
+			fmt.Fprintf(w, "  Part %d: %s (%s, parsed in %s)\n", r.Part, r.Answer, r.Duration, r.ParseDuration)
+		default:
+			fmt.Fprintf(w, "  Part %d: (%s, parsed in %s)\n", r.Part, r.Duration, r.ParseDuration)
+			for _, line := range lines {
+				fmt.Fprintf(w, "    %s\n", line)
+			}
+		}
+		for _, profile := range r.Profiles {
+			fmt.Fprintf(w, "    Profile: %s\n", profile)
+		}
+		switch len(r.Recording) {
+		case 0:
+		case 1:
+			fmt.Fprintf(w, "    Recording: %s\n", r.Recording[0])
+		default:
+			fmt.Fprintf(w, "    Recording: %s to %s (%d frames)\n", r.Recording[0], r.Recording[len(r.Recording)-1], len(r.Recording))
+		}
+		if r.Cast != "" {
+			fmt.Fprintf(w, "    Cast: %s\n", r.Cast)
+		}
+	}
+}
+
+// WriteOutput runs every part of a solution on inputFile and writes the answers
+// to outputFile, one line per answer, the way the 2024 days used to when they
+// owned their own main function.
+func WriteOutput(s *registry.Solution, inputFile, outputFile string, parallelism int) error {
+	results := Run(context.Background(), s, Config{InputFile: inputFile, Parallelism: parallelism})
+
+	var answers []string
+	for _, r := range results {
+		if r.Err != nil {
+			return fmt.Errorf("error solving part %d: %v", r.Part, r.Err)
+		}
+		answers = append(answers, r.Answer)
+	}
+
+	output, err := os.Create(outputFile)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %v", outputFile, err)
+	}
+	defer output.Close()
+
+	writer := bufio.NewWriter(output)
+	if _, err := writer.WriteString(strings.Join(answers, "\n")); err != nil {
+		return fmt.Errorf("error writing to %s: %v", outputFile, err)
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,307 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
+	"2ajoyce/adventofcode/lib/playback"
+	"2ajoyce/adventofcode/lib/record"
+)
+
+func writeInput(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "input.txt")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+	return path
+}
+
+func TestRun(t *testing.T) {
+	s := &registry.Solution{
+		Year: 1999,
+		Day:  1,
+		Solver: solver.New(
+			func(r io.Reader) (string, error) {
+				data, err := io.ReadAll(r)
+				return string(data), err
+			},
+			func(_ context.Context, input string, opts solver.Options) (string, error) {
+				return input, nil
+			},
+			func(_ context.Context, input string, opts solver.Options) (string, error) {
+				return "", errors.New("not solved")
+			},
+		),
+	}
+	input := writeInput(t, "answer")
+
+	results := Run(context.Background(), s, Config{InputFile: input})
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	if results[0].Answer != "answer" || results[0].Err != nil {
+		t.Errorf("Expected part 1 to answer 'answer', got %q (%v)", results[0].Answer, results[0].Err)
+	}
+	if results[1].Err == nil {
+		t.Errorf("Expected part 2 to fail")
+	}
+
+	results = Run(context.Background(), s, Config{Part: 1, InputFile: filepath.Join(t.TempDir(), "missing.txt")})
+	if len(results) != 1 || results[0].Err == nil {
+		t.Errorf("Expected a missing input file to fail, got %+v", results)
+	}
+}
+
+func TestWriteOutput(t *testing.T) {
+	s := &registry.Solution{
+		Year: 1999,
+		Day:  1,
+		Solver: solver.Lines(
+			func(lines []string) ([]string, error) { return lines, nil },
+			func(context.Context, []string, int) ([]string, error) { return []string{"Total: 1"}, nil },
+			func(context.Context, []string, int) ([]string, error) { return []string{"Total: 2"}, nil },
+		),
+	}
+	input := writeInput(t, "")
+	output := filepath.Join(t.TempDir(), "output.txt")
+
+	if err := WriteOutput(s, input, output, 1); err != nil {
+		t.Fatalf("Failed to write output: %v", err)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	if string(data) != "Total: 1\nTotal: 2" {
+		t.Errorf("Expected 'Total: 1\\nTotal: 2', got %q", string(data))
+	}
+}
+
+type fakeFetcher struct {
+	fetched []string
+}
+
+func (f *fakeFetcher) FetchInput(year, day int, dir string) (string, error) {
+	path := filepath.Join(dir, "input.txt")
+	f.fetched = append(f.fetched, path)
+	return path, os.WriteFile(path, []byte("fetched"), 0644)
+}
+
+func TestRunFetchesMissingInput(t *testing.T) {
+	s := &registry.Solution{
+		Year: 1999,
+		Day:  1,
+		Dir:  t.TempDir(),
+		Solver: solver.New(
+			func(r io.Reader) (string, error) {
+				data, err := io.ReadAll(r)
+				return string(data), err
+			},
+			func(_ context.Context, input string, opts solver.Options) (string, error) {
+				return input, nil
+			},
+			nil,
+		),
+	}
+	fetcher := &fakeFetcher{}
+
+	results := Run(context.Background(), s, Config{Fetcher: fetcher})
+	if len(results) != 1 || results[0].Answer != "fetched" {
+		t.Fatalf("Expected part 1 to solve the fetched input, got %+v", results)
+	}
+	Run(context.Background(), s, Config{Fetcher: fetcher})
+	if len(fetcher.fetched) != 1 {
+		t.Errorf("Expected the input to be fetched once, got %v", fetcher.fetched)
+	}
+}
+
+func TestRunProfiles(t *testing.T) {
+	s := &registry.Solution{
+		Year: 1999,
+		Day:  1,
+		Dir:  t.TempDir(),
+		Solver: solver.New(
+			func(r io.Reader) (string, error) {
+				data, err := io.ReadAll(r)
+				return string(data), err
+			},
+			func(_ context.Context, input string, opts solver.Options) (string, error) {
+				return input, nil
+			},
+			func(_ context.Context, input string, opts solver.Options) (string, error) {
+				return input, nil
+			},
+		),
+	}
+	input := writeInput(t, "answer")
+
+	results := Run(context.Background(), s, Config{InputFile: input, Profiles: Profiles{CPU: true, Memory: true, Trace: true, Block: true}})
+	for _, r := range results {
+		if r.Err != nil {
+			t.Fatalf("Failed to run part %d: %v", r.Part, r.Err)
+		}
+		if len(r.Profiles) != 4 {
+			t.Errorf("Expected 4 profiles for part %d, got %v", r.Part, r.Profiles)
+		}
+		for _, profile := range r.Profiles {
+			if filepath.Dir(profile) != filepath.Join(s.Dir, ProfileDir) {
+				t.Errorf("Expected %s to be in the day's profiles directory", profile)
+			}
+			if !strings.Contains(filepath.Base(profile), fmt.Sprintf("-part%d.", r.Part)) {
+				t.Errorf("Expected %s to name part %d", profile, r.Part)
+			}
+			if info, err := os.Stat(profile); err != nil || info.Size() == 0 {
+				t.Errorf("Expected %s to be written (%v)", profile, err)
+			}
+		}
+	}
+}
+
+func TestRunRecording(t *testing.T) {
+	palette := &record.Palette{}
+	s := &registry.Solution{
+		Year: 1999,
+		Day:  1,
+		Solver: solver.New(
+			func(r io.Reader) (string, error) { return "", nil },
+			func(ctx context.Context, input string, opts solver.Options) (string, error) {
+				for i := 0; i < 3; i++ {
+					record.FromContext(ctx).Cells(2, 2, palette, func(x, y int) rune { return '#' })
+				}
+				return "recorded", nil
+			},
+			func(ctx context.Context, input string, opts solver.Options) (string, error) {
+				return "not recorded", nil
+			},
+		),
+	}
+	input := writeInput(t, "")
+	path := filepath.Join(t.TempDir(), "out.gif")
+
+	results := Run(context.Background(), s, Config{InputFile: input, Recording: Recording{Path: path}})
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	want := filepath.Join(filepath.Dir(path), "out-part1.gif")
+	if results[0].Err != nil || len(results[0].Recording) != 1 || results[0].Recording[0] != want {
+		t.Errorf("Expected part 1 to be recorded to %s, got %v (%v)", want, results[0].Recording, results[0].Err)
+	}
+	if _, err := os.Stat(want); err != nil {
+		t.Errorf("Expected %s to be written: %v", want, err)
+	}
+	if results[1].Err != nil || len(results[1].Recording) != 0 {
+		t.Errorf("Expected part 2 to record nothing, got %v (%v)", results[1].Recording, results[1].Err)
+	}
+
+	results = Run(context.Background(), s, Config{Part: 1, InputFile: input, Recording: Recording{Path: path}})
+	if len(results) != 1 || len(results[0].Recording) != 1 || results[0].Recording[0] != path {
+		t.Errorf("Expected a single part to be recorded to %s, got %+v", path, results)
+	}
+}
+
+func TestRecordingSaveReportsCaptureErrors(t *testing.T) {
+	// A palette too large for a frame keeps every frame out
+	palette := &record.Palette{Runes: map[rune]color.Color{}}
+	for i := 0; i < 255; i++ {
+		palette.Runes[rune(i)] = color.Black
+	}
+	recorder := record.New()
+	recorder.Cells(1, 1, palette, func(x, y int) rune { return 0 })
+
+	recording := Recording{Path: filepath.Join(t.TempDir(), "out.gif")}
+	if _, err := recording.save(recorder, 1, false); err == nil {
+		t.Errorf("Expected the capture error to be reported")
+	}
+}
+
+func TestRunPlayback(t *testing.T) {
+	style := &playback.Style{}
+	s := &registry.Solution{
+		Year: 1999,
+		Day:  1,
+		Solver: solver.New(
+			func(r io.Reader) (string, error) { return "", nil },
+			func(ctx context.Context, input string, opts solver.Options) (string, error) {
+				for i := 0; i < 2; i++ {
+					if err := playback.FromContext(ctx).Lines([]string{"#."}, style, fmt.Sprintf("Tick %d", i)); err != nil {
+						return "", err
+					}
+				}
+				return "played", nil
+			},
+			func(ctx context.Context, input string, opts solver.Options) (string, error) {
+				return "not played", nil
+			},
+		),
+	}
+	input := writeInput(t, "")
+	dir := t.TempDir()
+	var terminal strings.Builder
+
+	results := Run(context.Background(), s, Config{InputFile: input, Playback: Playback{
+		Terminal: true,
+		Cast:     filepath.Join(dir, "out.cast"),
+		Output:   &terminal,
+		Input:    strings.NewReader(""),
+	}})
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	want := filepath.Join(dir, "out-part1.cast")
+	if results[0].Err != nil || results[0].Cast != want {
+		t.Errorf("Expected part 1 to be cast to %s, got %q (%v)", want, results[0].Cast, results[0].Err)
+	}
+	if data, err := os.ReadFile(want); err != nil || strings.Count(string(data), "\n") != 3 {
+		t.Errorf("Expected a header and 2 events in %s, got %q (%v)", want, data, err)
+	}
+	if !strings.Contains(terminal.String(), "Tick 1") {
+		t.Errorf("Expected the frames to be drawn on the terminal, got %q", terminal.String())
+	}
+	if results[1].Err != nil || results[1].Cast != "" {
+		t.Errorf("Expected part 2 to cast nothing, got %q (%v)", results[1].Cast, results[1].Err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "out-part2.cast")); !os.IsNotExist(err) {
+		t.Errorf("Expected the empty cast of part 2 to be removed, got %v", err)
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	s := &registry.Solution{
+		Year: 1999,
+		Day:  1,
+		Solver: solver.New(
+			func(r io.Reader) (string, error) { return "", nil },
+			func(ctx context.Context, input string, opts solver.Options) (string, error) {
+				evaluated := 0
+				for {
+					select {
+					case <-ctx.Done():
+						return "", fmt.Errorf("evaluated %d boards: %w", evaluated, ctx.Err())
+					case <-time.After(time.Millisecond):
+						evaluated++
+					}
+				}
+			},
+			nil,
+		),
+	}
+	input := writeInput(t, "")
+
+	results := Run(context.Background(), s, Config{InputFile: input, Timeout: 20 * time.Millisecond})
+	if len(results) != 1 || results[0].Err == nil {
+		t.Fatalf("Expected part 1 to time out, got %+v", results)
+	}
+	if err := results[0].Err.Error(); !strings.HasPrefix(err, "timed out after 20ms: evaluated ") {
+		t.Errorf("Expected the timeout to report the progress made, got %q", err)
+	}
+}
@@ -0,0 +1,353 @@
+// Package scaffold generates the skeleton of a new day: its module, a parser
+// and solver skeleton for the kind of puzzle, a table-driven test, and the
+// bookkeeping needed for the runner and the year's README to know about it.
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var templates embed.FS
+
+// Kind selects the parser skeleton generated for a day.
+type Kind string
+
+const (
+	KindLines Kind = "lines" // One value per line of input
+	KindGrid  Kind = "grid"  // The whole input as a [][]rune grid
+	KindGraph Kind = "graph" // An adjacency list built from "aaa: bbb ccc" lines
+)
+
+type kind struct {
+	chanType string
+	example  example
+}
+
+type example struct {
+	Input  []string
+	Output string
+}
+
+var kinds = map[Kind]kind{
+	KindLines: {chanType: "string", example: example{Input: []string{"123", "456"}, Output: "6"}},
+	KindGrid:  {chanType: "[][]rune", example: example{Input: []string{"#..", ".#.", "..#"}, Output: "3"}},
+	KindGraph: {chanType: "*Graph", example: example{Input: []string{"aaa: bbb ccc", "bbb: ccc"}, Output: "3"}},
+}
+
+// ParseKind returns the Kind named s.
+func ParseKind(s string) (Kind, error) {
+	k := Kind(s)
+	if _, ok := kinds[k]; !ok {
+		return "", fmt.Errorf("unknown kind %q, expected lines, grid or graph", s)
+	}
+	return k, nil
+}
+
+// Day describes the day to generate.
+type Day struct {
+	Root string // Root of the repository, the directory holding go.work
+	Year int
+	Day  int
+	Kind Kind
+}
+
+// Result lists what New did.
+type Result struct {
+	Dir     string
+	Created []string // Files written, relative to Root
+	Skipped []string // Files left alone because they already existed, relative to Root
+}
+
+// templateData is what the templates are rendered with.
+type templateData struct {
+	Year      int
+	Day       int
+	Kind      Kind
+	Module    string
+	GoVersion string
+	ChanType  string
+	Example   example
+	Parts     []int
+}
+
+// New generates the day described by d. Files that already exist are never
+// overwritten, so running it again only fills in what is missing.
+// A year that has no directory yet is bootstrapped with a README.
+func New(d Day) (*Result, error) {
+	k, ok := kinds[d.Kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown kind %q", d.Kind)
+	}
+	if d.Day < 1 || d.Day > 25 {
+		return nil, fmt.Errorf("day must be between 1 and 25, got %d", d.Day)
+	}
+
+	yearDir := filepath.Join(d.Root, strconv.Itoa(d.Year))
+	dayDir := filepath.Join(yearDir, strconv.Itoa(d.Day))
+	// 2024 named its directories dayN
+	if _, err := os.Stat(filepath.Join(yearDir, fmt.Sprintf("day%d", d.Day))); err == nil {
+		return nil, fmt.Errorf("%d day %d already exists in %s", d.Year, d.Day, filepath.Join(yearDir, fmt.Sprintf("day%d", d.Day)))
+	}
+
+	goVersion, err := workGoVersion(d.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Dir: dayDir}
+
+	readme := filepath.Join(yearDir, "README.md")
+	if _, err := os.Stat(readme); os.IsNotExist(err) {
+		if err := os.MkdirAll(yearDir, 0755); err != nil {
+			return nil, fmt.Errorf("error creating %s: %v", yearDir, err)
+		}
+		if err := os.WriteFile(readme, []byte(yearReadme(d.Year)), 0644); err != nil {
+			return nil, fmt.Errorf("error writing %s: %v", readme, err)
+		}
+		result.Created = append(result.Created, rel(d.Root, readme))
+	}
+
+	data := templateData{
+		Year:      d.Year,
+		Day:       d.Day,
+		Kind:      d.Kind,
+		Module:    fmt.Sprintf("2ajoyce/adventofcode/%d/%d", d.Year, d.Day),
+		GoVersion: goVersion,
+		ChanType:  k.chanType,
+		Example:   k.example,
+		Parts:     []int{1, 2},
+	}
+	files := []struct{ name, template string }{
+		{"go.mod", "go.mod.tmpl"},
+		{"main.go", string(d.Kind) + ".go.tmpl"},
+		{"main_test.go", "main_test.go.tmpl"},
+		{"utils.go", "utils.go.tmpl"},
+	}
+	if err := os.MkdirAll(dayDir, 0755); err != nil {
+		return nil, fmt.Errorf("error creating %s: %v", dayDir, err)
+	}
+	for _, f := range files {
+		path := filepath.Join(dayDir, f.name)
+		if _, err := os.Stat(path); err == nil {
+			result.Skipped = append(result.Skipped, rel(d.Root, path))
+			continue
+		}
+		content, err := render(f.template, data)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			return nil, fmt.Errorf("error writing %s: %v", path, err)
+		}
+		result.Created = append(result.Created, rel(d.Root, path))
+	}
+
+	if err := addToWorkspace(d.Root, fmt.Sprintf("./%d/%d", d.Year, d.Day)); err != nil {
+		return nil, err
+	}
+	if err := addToRunner(d.Root, data.Module); err != nil {
+		return nil, err
+	}
+	if err := addReadmeRow(readme, d.Day); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func render(name string, data templateData) ([]byte, error) {
+	t, err := template.ParseFS(templates, "templates/"+name)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing template %s: %v", name, err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("error rendering template %s: %v", name, err)
+	}
+	if !strings.HasSuffix(name, ".go.tmpl") {
+		return buf.Bytes(), nil
+	}
+	content, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("error formatting template %s: %v", name, err)
+	}
+	return content, nil
+}
+
+func rel(root, path string) string {
+	if r, err := filepath.Rel(root, path); err == nil {
+		return r
+	}
+	return path
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// WORKSPACE
+/////////////////////////////////////////////////////////////////////////////////////
+
+var goDirective = regexp.MustCompile(`(?m)^go (\S+)$`)
+
+// workGoVersion returns the go version the workspace is declared with, which
+// every module in the repository uses too.
+func workGoVersion(root string) (string, error) {
+	work, err := os.ReadFile(filepath.Join(root, "go.work"))
+	if err != nil {
+		return "", fmt.Errorf("error reading go.work: %v", err)
+	}
+	m := goDirective.FindSubmatch(work)
+	if m == nil {
+		return "", fmt.Errorf("go.work has no go directive")
+	}
+	return string(m[1]), nil
+}
+
+// addToWorkspace adds dir to the use block of go.work, after the other
+// directories of the same year.
+func addToWorkspace(root, dir string) error {
+	path := filepath.Join(root, "go.work")
+	work, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading go.work: %v", err)
+	}
+
+	lines := strings.Split(string(work), "\n")
+	insertAt := -1
+	inUse := false
+	yearPrefix := dir[:strings.LastIndex(dir, "/")+1]
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "use (":
+			inUse = true
+		case inUse && trimmed == dir:
+			return nil
+		case inUse && trimmed == ")":
+			if insertAt < 0 {
+				insertAt = i
+			}
+			inUse = false
+		case inUse && strings.HasPrefix(trimmed, yearPrefix):
+			insertAt = i + 1
+		}
+	}
+	if insertAt < 0 {
+		return fmt.Errorf("go.work has no use block")
+	}
+
+	lines = append(lines[:insertAt], append([]string{"\t" + dir}, lines[insertAt:]...)...)
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		return fmt.Errorf("error writing go.work: %v", err)
+	}
+	return nil
+}
+
+// addToRunner imports the day's package in aoc/days.go so that it registers
+// itself with the runner.
+func addToRunner(root, module string) error {
+	path := filepath.Join(root, "aoc", "days.go")
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %v", path, err)
+	}
+
+	importLine := fmt.Sprintf("_ %q", module)
+	if bytes.Contains(src, []byte(importLine)) {
+		return nil
+	}
+	end := bytes.LastIndex(src, []byte("\n)"))
+	if end < 0 {
+		return fmt.Errorf("%s has no import block", path)
+	}
+
+	updated := append([]byte{}, src[:end]...)
+	updated = append(updated, "\n\t"+importLine...)
+	updated = append(updated, src[end:]...)
+	formatted, err := format.Source(updated)
+	if err != nil {
+		return fmt.Errorf("error formatting %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, formatted, 0644); err != nil {
+		return fmt.Errorf("error writing %s: %v", path, err)
+	}
+	return nil
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// README
+/////////////////////////////////////////////////////////////////////////////////////
+
+func yearReadme(year int) string {
+	return fmt.Sprintf(`# Advent of Code %d
+
+This repository contains my solutions for Advent of Code %d, implemented in Go.
+
+## Challenge Types and Algorithms
+
+| Day | Challenge Type | Algorithm / Technique |
+| :-- | :------------- | :-------------------- |
+`, year, year)
+}
+
+// addReadmeRow adds a placeholder row for the day to the challenge table,
+// padding the cells to the width of the existing columns.
+func addReadmeRow(path string, day int) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %v", path, err)
+	}
+
+	lines := strings.Split(strings.TrimRight(string(content), "\n"), "\n")
+	separator := -1
+	last := -1
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "|") {
+			if last >= 0 {
+				break
+			}
+			continue
+		}
+		cells := splitRow(line)
+		if len(cells) > 0 && strings.HasPrefix(cells[0], ":--") && separator < 0 {
+			separator = i
+		}
+		if separator >= 0 && i > separator && len(cells) > 0 && cells[0] == strconv.Itoa(day) {
+			return nil
+		}
+		last = i
+	}
+	if separator < 0 {
+		return fmt.Errorf("%s has no challenge table", path)
+	}
+
+	cells := []string{strconv.Itoa(day), fmt.Sprintf("[TODO](%d/main.go)", day), "TODO"}
+	for i, width := range splitRow(lines[separator]) {
+		if i < len(cells) && len(cells[i]) < len(width) {
+			cells[i] += strings.Repeat(" ", len(width)-len(cells[i]))
+		}
+	}
+	row := "| " + strings.Join(cells, " | ") + " |"
+
+	lines = append(lines[:last+1], append([]string{row}, lines[last+1:]...)...)
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("error writing %s: %v", path, err)
+	}
+	return nil
+}
+
+func splitRow(line string) []string {
+	line = strings.Trim(strings.TrimSpace(line), "|")
+	cells := strings.Split(line, "|")
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+	return cells
+}
@@ -0,0 +1,169 @@
+package scaffold
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testWork = `go 1.25.4
+
+use (
+	./aoc
+	./2025/1
+	./2025/2
+)
+`
+
+const testDays = `package main
+
+import (
+	_ "2ajoyce/adventofcode/2025/1"
+	_ "2ajoyce/adventofcode/2025/2"
+)
+`
+
+const testReadme = `# Advent of Code 2025
+
+| Day | Challenge Type                 | Algorithm / Technique |
+| :-- | :----------------------------- | :-------------------- |
+| 1   | [Simulation](1/main.go)        | Modulo Arithmetic     |
+| 2   | [Interval](2/main.go)          | Pattern Detection     |
+
+Trailing notes.
+`
+
+func setUpRoot(t *testing.T) string {
+	root := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+	write("go.work", testWork)
+	write("aoc/days.go", testDays)
+	write("2025/README.md", testReadme)
+	return root
+}
+
+func readFile(t *testing.T, path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestNew(t *testing.T) {
+	for _, kind := range []Kind{KindLines, KindGrid, KindGraph} {
+		t.Run(string(kind), func(t *testing.T) {
+			root := setUpRoot(t)
+			result, err := New(Day{Root: root, Year: 2025, Day: 3, Kind: kind})
+			if err != nil {
+				t.Fatalf("Failed to scaffold day: %v", err)
+			}
+			if len(result.Created) != 4 || len(result.Skipped) != 0 {
+				t.Errorf("Expected 4 created and 0 skipped files, got %v and %v", result.Created, result.Skipped)
+			}
+
+			goMod := readFile(t, filepath.Join(root, "2025/3/go.mod"))
+			if !strings.HasPrefix(goMod, "module 2ajoyce/adventofcode/2025/3\n\ngo 1.25.4\n") {
+				t.Errorf("Unexpected go.mod:\n%s", goMod)
+			}
+
+			fset := token.NewFileSet()
+			for _, name := range []string{"main.go", "main_test.go", "utils.go"} {
+				f, err := parser.ParseFile(fset, filepath.Join(root, "2025/3", name), nil, 0)
+				if err != nil {
+					t.Fatalf("Generated %s does not parse: %v", name, err)
+				}
+				if f.Name.Name != "day3" {
+					t.Errorf("Expected %s to be in package day3, got %s", name, f.Name.Name)
+				}
+			}
+			if main := readFile(t, filepath.Join(root, "2025/3/main.go")); !strings.Contains(main, "Day:    3,") {
+				t.Errorf("Expected main.go to register day 3:\n%s", main)
+			}
+
+			work := readFile(t, filepath.Join(root, "go.work"))
+			if !strings.Contains(work, "\t./2025/2\n\t./2025/3\n)") {
+				t.Errorf("Expected the day to be added to go.work:\n%s", work)
+			}
+			days := readFile(t, filepath.Join(root, "aoc/days.go"))
+			if !strings.Contains(days, `_ "2ajoyce/adventofcode/2025/3"`) {
+				t.Errorf("Expected the day to be imported in days.go:\n%s", days)
+			}
+			readme := readFile(t, filepath.Join(root, "2025/README.md"))
+			row := "| 3   | [TODO](3/main.go)              | TODO                  |\n\nTrailing notes."
+			if !strings.Contains(readme, row) {
+				t.Errorf("Expected a padded row for day 3 at the end of the table:\n%s", readme)
+			}
+		})
+	}
+}
+
+func TestNewSkipsExistingFiles(t *testing.T) {
+	root := setUpRoot(t)
+	day := Day{Root: root, Year: 2025, Day: 3, Kind: KindLines}
+	if _, err := New(day); err != nil {
+		t.Fatalf("Failed to scaffold day: %v", err)
+	}
+	mainFile := filepath.Join(root, "2025/3/main.go")
+	if err := os.WriteFile(mainFile, []byte("package day3\n"), 0644); err != nil {
+		t.Fatalf("Failed to write main.go: %v", err)
+	}
+
+	result, err := New(day)
+	if err != nil {
+		t.Fatalf("Failed to scaffold day again: %v", err)
+	}
+	if len(result.Created) != 0 || len(result.Skipped) != 4 {
+		t.Errorf("Expected every file to be skipped, got %v and %v", result.Created, result.Skipped)
+	}
+	if readFile(t, mainFile) != "package day3\n" {
+		t.Errorf("Expected main.go not to be overwritten")
+	}
+	if work := readFile(t, filepath.Join(root, "go.work")); strings.Count(work, "./2025/3") != 1 {
+		t.Errorf("Expected go.work to list the day once:\n%s", work)
+	}
+	if readme := readFile(t, filepath.Join(root, "2025/README.md")); strings.Count(readme, "(3/main.go)") != 1 {
+		t.Errorf("Expected the README to list the day once:\n%s", readme)
+	}
+}
+
+func TestNewBootstrapsYear(t *testing.T) {
+	root := setUpRoot(t)
+	result, err := New(Day{Root: root, Year: 2026, Day: 1, Kind: KindGrid})
+	if err != nil {
+		t.Fatalf("Failed to scaffold day: %v", err)
+	}
+	if len(result.Created) != 5 {
+		t.Errorf("Expected the README and 4 day files to be created, got %v", result.Created)
+	}
+
+	readme := readFile(t, filepath.Join(root, "2026/README.md"))
+	if !strings.HasPrefix(readme, "# Advent of Code 2026\n") || !strings.Contains(readme, "| 1   | [TODO](1/main.go) |") {
+		t.Errorf("Unexpected README for the new year:\n%s", readme)
+	}
+	work := readFile(t, filepath.Join(root, "go.work"))
+	if !strings.Contains(work, "\t./2026/1\n)") {
+		t.Errorf("Expected the new year to be added at the end of go.work:\n%s", work)
+	}
+}
+
+func TestNewRejectsExisting2024Layout(t *testing.T) {
+	root := setUpRoot(t)
+	if err := os.MkdirAll(filepath.Join(root, "2024/day6"), 0755); err != nil {
+		t.Fatalf("Failed to create day directory: %v", err)
+	}
+	if _, err := New(Day{Root: root, Year: 2024, Day: 6, Kind: KindLines}); err == nil {
+		t.Errorf("Expected an error for a day that already exists as day6")
+	}
+}
@@ -0,0 +1,224 @@
+// Package solver defines the interface shared by every day, whichever year's
+// calling convention it was written in, and the adapters that wrap them.
+package solver
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrUnsolved is returned by parts a day does not solve.
+var ErrUnsolved = errors.New("part not solved")
+
+// Options tunes how a part is solved.
+type Options struct {
+	Parallelism int // Worker count for solutions that fan out, at least 1
+}
+
+// Input is whatever a solver's Parse produced. It is handed back to the same
+// solver's parts unchanged.
+type Input any
+
+// Solver solves both parts of a day.
+//
+// Parts are free to modify the parsed input, so callers must parse the input
+// again before solving another part.
+//
+// Parts should stop and return ctx.Err() once ctx is done, wrapped with how far
+// they got, for example "evaluated 12 of 1000 boards: context deadline exceeded".
+// Goroutines a part starts must have returned by then.
+type Solver interface {
+	Parse(r io.Reader) (Input, error)
+	Part1(ctx context.Context, input Input, opts Options) (string, error)
+	Part2(ctx context.Context, input Input, opts Options) (string, error)
+}
+
+// Solves reports whether s solves the part. Solvers built by this package know
+// which of their parts are missing; any other solver is assumed to solve both.
+func Solves(s Solver, part int) bool {
+	if p, ok := s.(interface{ Solves(part int) bool }); ok {
+		return p.Solves(part)
+	}
+	return part == 1 || part == 2
+}
+
+// Solve runs the requested part of s.
+func Solve(ctx context.Context, s Solver, part int, input Input, opts Options) (string, error) {
+	switch part {
+	case 1:
+		return s.Part1(ctx, input, opts)
+	case 2:
+		return s.Part2(ctx, input, opts)
+	default:
+		return "", ErrUnsolved
+	}
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// ADAPTERS
+/////////////////////////////////////////////////////////////////////////////////////
+
+// Part solves one part of a day from its parsed input.
+type Part[T any] func(ctx context.Context, input T, opts Options) (string, error)
+
+type funcSolver[T any] struct {
+	parse func(r io.Reader) (T, error)
+	parts [2]Part[T]
+}
+
+// New builds a Solver out of a parse function and a function per part.
+// Either part may be nil when the day does not solve it.
+func New[T any](parse func(r io.Reader) (T, error), part1, part2 Part[T]) Solver {
+	return &funcSolver[T]{parse: parse, parts: [2]Part[T]{part1, part2}}
+}
+
+func (s *funcSolver[T]) Parse(r io.Reader) (Input, error) {
+	return s.parse(r)
+}
+
+func (s *funcSolver[T]) Part1(ctx context.Context, input Input, opts Options) (string, error) {
+	return s.solve(ctx, 0, input, opts)
+}
+
+func (s *funcSolver[T]) Part2(ctx context.Context, input Input, opts Options) (string, error) {
+	return s.solve(ctx, 1, input, opts)
+}
+
+func (s *funcSolver[T]) Solves(part int) bool {
+	return part >= 1 && part <= 2 && s.parts[part-1] != nil
+}
+
+func (s *funcSolver[T]) solve(ctx context.Context, index int, input Input, opts Options) (string, error) {
+	part := s.parts[index]
+	if part == nil {
+		return "", ErrUnsolved
+	}
+	typed, ok := input.(T)
+	if !ok {
+		return "", fmt.Errorf("input of type %T was not parsed by this solver", input)
+	}
+	if opts.Parallelism < 1 {
+		opts.Parallelism = 1
+	}
+	return part(ctx, typed, opts)
+}
+
+// LinesPart is a part in the 2024 convention, returning its results one per line.
+type LinesPart[T any] func(ctx context.Context, input T, parallelism int) ([]string, error)
+
+// Lines adapts the 2024 convention: the input is split into lines and parsed,
+// and each part takes the parsed input and a worker count and returns its
+// results one per line.
+func Lines[T any](parse func(lines []string) (T, error), part1, part2 LinesPart[T]) Solver {
+	return New(
+		func(r io.Reader) (T, error) {
+			lines, err := ReadLines(r)
+			if err != nil {
+				var zero T
+				return zero, err
+			}
+			return parse(lines)
+		},
+		linesPart(part1),
+		linesPart(part2),
+	)
+}
+
+// Serial adapts a 2024 part that neither fans out nor runs long enough to need
+// cancelling to the signature Lines expects.
+func Serial[T any](part func(input T) ([]string, error)) LinesPart[T] {
+	return func(_ context.Context, input T, _ int) ([]string, error) {
+		return part(input)
+	}
+}
+
+func linesPart[T any](part LinesPart[T]) Part[T] {
+	if part == nil {
+		return nil
+	}
+	return func(ctx context.Context, input T, opts Options) (string, error) {
+		results, err := part(ctx, input, opts.Parallelism)
+		if err != nil {
+			return "", err
+		}
+		return strings.Join(results, "\n"), nil
+	}
+}
+
+// ChannelPart is a part in the 2025 convention, draining its input from a channel.
+type ChannelPart[T any] func(ctx context.Context, input chan T) (string, error)
+
+// Channel adapts the 2025 convention: read streams the parsed input into a
+// channel and closes it, also when it fails, and each part drains such a
+// channel. The streamed values are collected by Parse so that every part gets a
+// fresh channel.
+func Channel[T any](read func(r io.Reader, c chan T) error, part1, part2 ChannelPart[T]) Solver {
+	return New(
+		func(r io.Reader) ([]T, error) {
+			c := make(chan T)
+			errc := make(chan error, 1)
+			go func() {
+				// A panic in read would take the whole process down with it
+				defer func() {
+					if p := recover(); p != nil {
+						errc <- fmt.Errorf("panic reading input: %v", p)
+					}
+				}()
+				errc <- read(r, c)
+			}()
+			values := []T{}
+			for v := range c {
+				values = append(values, v)
+			}
+			if err := <-errc; err != nil {
+				return nil, err
+			}
+			return values, nil
+		},
+		channelPart(part1),
+		channelPart(part2),
+	)
+}
+
+func channelPart[T any](part ChannelPart[T]) Part[[]T] {
+	if part == nil {
+		return nil
+	}
+	return func(ctx context.Context, values []T, _ Options) (string, error) {
+		// Stop streaming if the part returns without draining the channel
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		return part(ctx, Stream(ctx, values))
+	}
+}
+
+// Stream sends values on a new channel, closing it once they have all been read.
+// It stops early, leaving the channel open, once ctx is done.
+func Stream[T any](ctx context.Context, values []T) chan T {
+	c := make(chan T)
+	go func() {
+		for _, v := range values {
+			select {
+			case c <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+		close(c)
+	}()
+	return c
+}
+
+// ReadLines reads every line of r.
+func ReadLines(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	lines := make([]string, 0)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
@@ -0,0 +1,107 @@
+package solver
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	parse := func(lines []string) ([]int, error) {
+		numbers := []int{}
+		for _, line := range lines {
+			n, err := strconv.Atoi(line)
+			if err != nil {
+				return nil, err
+			}
+			numbers = append(numbers, n)
+		}
+		return numbers, nil
+	}
+	sum := func(_ context.Context, numbers []int, parallelism int) ([]string, error) {
+		total := 0
+		for _, n := range numbers {
+			total += n
+		}
+		return []string{fmt.Sprintf("Total: %d", total), fmt.Sprintf("Workers: %d", parallelism)}, nil
+	}
+	s := Lines(parse, sum, nil)
+
+	input, err := s.Parse(strings.NewReader("1\n2\n3\n"))
+	if err != nil {
+		t.Fatalf("Failed to parse input: %v", err)
+	}
+	result, err := s.Part1(context.Background(), input, Options{Parallelism: 4})
+	if err != nil {
+		t.Fatalf("Failed to solve part 1: %v", err)
+	}
+	if result != "Total: 6\nWorkers: 4" {
+		t.Errorf("Expected results joined by newlines, got %q", result)
+	}
+
+	if _, err := s.Part2(context.Background(), input, Options{}); !errors.Is(err, ErrUnsolved) {
+		t.Errorf("Expected ErrUnsolved for part 2, got %v", err)
+	}
+	if Solves(s, 2) {
+		t.Errorf("Expected part 2 not to be solved")
+	}
+
+	if _, err := s.Parse(strings.NewReader("x\n")); err == nil {
+		t.Errorf("Expected parse errors to be returned")
+	}
+	if _, err := s.Part1(context.Background(), "not parsed by s", Options{}); err == nil {
+		t.Errorf("Expected an error for input of the wrong type")
+	}
+}
+
+func TestChannel(t *testing.T) {
+	read := func(r io.Reader, c chan string) error {
+		defer close(c)
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			if scanner.Text() == "panic" {
+				panic("unexpected line")
+			}
+			if scanner.Text() == "" {
+				return errors.New("empty line")
+			}
+			c <- scanner.Text()
+		}
+		return scanner.Err()
+	}
+	count := func(_ context.Context, input chan string) (string, error) {
+		n := 0
+		for range input {
+			n++
+		}
+		return strconv.Itoa(n), nil
+	}
+	s := Channel(read, count, count)
+
+	input, err := s.Parse(strings.NewReader("a\nb\nc\n"))
+	if err != nil {
+		t.Fatalf("Failed to parse input: %v", err)
+	}
+	// Both parts drain their channel, so each one needs its own
+	for part := 1; part <= 2; part++ {
+		result, err := Solve(context.Background(), s, part, input, Options{})
+		if err != nil {
+			t.Fatalf("Failed to solve part %d: %v", part, err)
+		}
+		if result != "3" {
+			t.Errorf("Expected part %d to count 3 lines, got %s", part, result)
+		}
+	}
+
+	if _, err := s.Parse(strings.NewReader("a\n\nc\n")); err == nil || err.Error() != "empty line" {
+		t.Errorf("Expected the error from read to be returned, got %v", err)
+	}
+	if _, err := s.Parse(strings.NewReader("a\npanic\n")); err == nil {
+		t.Errorf("Expected a panic in read to be returned as an error")
+	}
+}
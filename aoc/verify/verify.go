@@ -0,0 +1,117 @@
+// Package verify checks solutions against the golden answers recorded in each
+// day's answers file, so that changes to shared code can be checked against
+// every day's real input at once.
+package verify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"2ajoyce/adventofcode/aoc/answers"
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/runner"
+)
+
+// Status is the outcome of checking one part.
+type Status int
+
+const (
+	Pass     Status = iota // The answer matches the golden answer
+	Fail                   // The answer differs from the golden answer, or the part failed
+	NoInput                // The day has no cached input to run on
+	NoAnswer               // No golden answer is recorded for the part
+)
+
+func (s Status) String() string {
+	switch s {
+	case Pass:
+		return "pass"
+	case Fail:
+		return "FAIL"
+	case NoInput:
+		return "no input"
+	default:
+		return "no answer"
+	}
+}
+
+// Result is the outcome of checking one part of a solution.
+type Result struct {
+	Solution *registry.Solution
+	Part     int
+	Status   Status
+	Want     string
+	Got      string
+	Err      error
+}
+
+// Solution checks every part a solution solves against its golden answer.
+// Parts without a golden answer or without an input are not run, and parts that
+// run longer than timeout fail, unless timeout is 0.
+func Solution(ctx context.Context, s *registry.Solution, parallelism int, timeout time.Duration) []Result {
+	known, err := answers.Load(s.Dir)
+	var results []Result
+	for part := 1; part <= 2; part++ {
+		if !s.Solves(part) {
+			continue
+		}
+		r := Result{Solution: s, Part: part}
+		if err != nil {
+			r.Status = Fail
+			r.Err = err
+			results = append(results, r)
+			continue
+		}
+
+		r.Want = known.Part(part).Answer
+		inputFile := s.InputFile(part)
+		switch _, statErr := os.Stat(inputFile); {
+		case statErr != nil:
+			r.Status = NoInput
+		case r.Want == "":
+			r.Status = NoAnswer
+		default:
+			run := runner.Run(ctx, s, runner.Config{Part: part, InputFile: inputFile, Parallelism: parallelism, Timeout: timeout})[0]
+			r.Got, r.Err = strings.TrimSpace(run.Answer), run.Err
+			if r.Err == nil && r.Got == r.Want {
+				r.Status = Pass
+			} else {
+				r.Status = Fail
+			}
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// Print writes a line per part and a summary of how many parts have each status.
+func Print(w io.Writer, results []Result) {
+	counts := make(map[Status]int)
+	for _, r := range results {
+		counts[r.Status]++
+		switch {
+		case r.Err != nil:
+			fmt.Fprintf(w, "%-28s part %d: %s: %v\n", r.Solution, r.Part, r.Status, r.Err)
+		case r.Status == Fail:
+			fmt.Fprintf(w, "%-28s part %d: %s: want %q, got %q\n", r.Solution, r.Part, r.Status, r.Want, r.Got)
+		default:
+			fmt.Fprintf(w, "%-28s part %d: %s\n", r.Solution, r.Part, r.Status)
+		}
+	}
+	fmt.Fprintf(w, "\n%d passed, %d failed, %d without input, %d without answer\n",
+		counts[Pass], counts[Fail], counts[NoInput], counts[NoAnswer])
+}
+
+// Failed reports whether any part failed.
+func Failed(results []Result) bool {
+	for _, r := range results {
+		if r.Status == Fail {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package verify
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"2ajoyce/adventofcode/aoc/answers"
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/solver"
+)
+
+func newSolution(t *testing.T, input string, known answers.Answers) *registry.Solution {
+	dir := t.TempDir()
+	if input != "" {
+		if err := os.WriteFile(filepath.Join(dir, "input.txt"), []byte(input), 0644); err != nil {
+			t.Fatalf("Failed to write input: %v", err)
+		}
+	}
+	if known != nil {
+		if err := known.Save(dir); err != nil {
+			t.Fatalf("Failed to save answers: %v", err)
+		}
+	}
+	return &registry.Solution{
+		Year: 1999,
+		Day:  1,
+		Dir:  dir,
+		Solver: solver.New(
+			func(r io.Reader) (string, error) {
+				data, err := io.ReadAll(r)
+				return string(data), err
+			},
+			func(_ context.Context, input string, opts solver.Options) (string, error) {
+				return strings.ToUpper(input), nil
+			},
+			func(_ context.Context, input string, opts solver.Options) (string, error) {
+				return strings.ToLower(input), nil
+			},
+		),
+	}
+}
+
+func TestSolution(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		known answers.Answers
+		want  []Status
+	}{
+		{"pass", "Answer", answers.Answers{1: {Answer: "ANSWER"}, 2: {Answer: "answer"}}, []Status{Pass, Pass}},
+		{"fail", "Answer", answers.Answers{1: {Answer: "ANSWER"}, 2: {Answer: "Answer"}}, []Status{Pass, Fail}},
+		{"no answer", "Answer", answers.Answers{1: {Answer: "ANSWER"}}, []Status{Pass, NoAnswer}},
+		{"no answers file", "Answer", nil, []Status{NoAnswer, NoAnswer}},
+		{"no input", "", answers.Answers{1: {Answer: "ANSWER"}}, []Status{NoInput, NoInput}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := Solution(context.Background(), newSolution(t, tt.input, tt.known), 1, 0)
+			if len(results) != len(tt.want) {
+				t.Fatalf("Expected %d results, got %d", len(tt.want), len(results))
+			}
+			for i, r := range results {
+				if r.Status != tt.want[i] {
+					t.Errorf("Expected part %d to be %s, got %s (%q vs %q)", r.Part, tt.want[i], r.Status, r.Want, r.Got)
+				}
+			}
+			if Failed(results) != (tt.name == "fail") {
+				t.Errorf("Unexpected Failed(%v)", results)
+			}
+		})
+	}
+}
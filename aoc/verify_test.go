@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"2ajoyce/adventofcode/aoc/registry"
+	"2ajoyce/adventofcode/aoc/verify"
+)
+
+// TestGoldenAnswers runs every registered day on its cached input and compares
+// the answers with the ones recorded in the day's answers file. Days without an
+// input or a recorded answer are skipped.
+func TestGoldenAnswers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping the real inputs in short mode")
+	}
+	for _, s := range registry.All() {
+		for _, r := range verify.Solution(context.Background(), s, 1, 0) {
+			name := fmt.Sprintf("%d/%d/part%d", s.Year, s.Day, r.Part)
+			if s.Name != "" {
+				name = fmt.Sprintf("%d/%d/%s/part%d", s.Year, s.Day, s.Name, r.Part)
+			}
+			t.Run(name, func(t *testing.T) {
+				switch r.Status {
+				case verify.NoInput, verify.NoAnswer:
+					t.Skip(r.Status)
+				case verify.Fail:
+					if r.Err != nil {
+						t.Fatalf("Failed to solve: %v", r.Err)
+					}
+					t.Errorf("Expected %q, got %q", r.Want, r.Got)
+				}
+			})
+		}
+	}
+}
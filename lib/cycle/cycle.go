@@ -0,0 +1,128 @@
+// Package cycle finds where a deterministic process starts repeating itself,
+// so the state after a huge number of steps can be worked out without taking
+// every step.
+//
+// A process is a start state and a step function returning the next state. The
+// step function must not modify the state it is given, a process built on a
+// simulation.Simulation steps a Clone. States are compared through a key, which
+// for a simulation is its StateHash.
+package cycle
+
+import (
+	"fmt"
+)
+
+// Cycle describes the states of a process: after Start steps it reaches a state
+// that comes back every Period steps from then on.
+type Cycle[S any] struct {
+	Start  int // Number of steps before the first state that repeats
+	Period int // Number of steps between repeats
+
+	start   S
+	step    func(S) S
+	history []S // States 0 to Start+Period-1, when recorded
+}
+
+// Brent finds the cycle with Brent's algorithm, which only keeps two states
+// around at a time. It gives up with an error after maxSteps steps, 0 means no
+// limit.
+func Brent[S any, K comparable](start S, step func(S) S, key func(S) K, maxSteps int) (*Cycle[S], error) {
+	steps := 0
+	next := func(s S) (S, error) {
+		steps++
+		if maxSteps > 0 && steps > maxSteps {
+			return s, fmt.Errorf("no cycle found within %d steps", maxSteps)
+		}
+		return step(s), nil
+	}
+
+	// Find the period. The tortoise waits at every power of two for the hare to
+	// come back around to it.
+	power, period := 1, 1
+	tortoise, tortoiseKey := start, key(start)
+	hare, err := next(start)
+	if err != nil {
+		return nil, err
+	}
+	hareKey := key(hare)
+	for tortoiseKey != hareKey {
+		if power == period {
+			tortoise, tortoiseKey = hare, hareKey
+			power *= 2
+			period = 0
+		}
+		if hare, err = next(hare); err != nil {
+			return nil, err
+		}
+		hareKey = key(hare)
+		period++
+	}
+
+	// Find the start. With the hare a period ahead of the tortoise, they first
+	// meet at the start of the cycle.
+	tortoise, hare = start, start
+	for i := 0; i < period; i++ {
+		if hare, err = next(hare); err != nil {
+			return nil, err
+		}
+	}
+	tortoiseKey, hareKey = key(tortoise), key(hare)
+	cycleStart := 0
+	for tortoiseKey != hareKey {
+		if tortoise, err = next(tortoise); err != nil {
+			return nil, err
+		}
+		if hare, err = next(hare); err != nil {
+			return nil, err
+		}
+		tortoiseKey, hareKey = key(tortoise), key(hare)
+		cycleStart++
+	}
+
+	return &Cycle[S]{Start: cycleStart, Period: period, start: start, step: step}, nil
+}
+
+// History finds the cycle by recording every state until one repeats. It takes
+// fewer steps than Brent and lets At return states without stepping again, at
+// the cost of keeping every state in memory. It gives up with an error after
+// maxSteps steps, 0 means no limit.
+func History[S any, K comparable](start S, step func(S) S, key func(S) K, maxSteps int) (*Cycle[S], error) {
+	seen := make(map[K]int) // Key -> step it was first seen at
+	history := []S{}
+	state := start
+	for n := 0; ; n++ {
+		k := key(state)
+		if first, ok := seen[k]; ok {
+			return &Cycle[S]{Start: first, Period: n - first, start: start, step: step, history: history}, nil
+		}
+		if maxSteps > 0 && n >= maxSteps {
+			return nil, fmt.Errorf("no cycle found within %d steps", maxSteps)
+		}
+		seen[k] = n
+		history = append(history, state)
+		state = step(state)
+	}
+}
+
+// Index returns the step before the end of the first cycle that has the same
+// state as step n.
+func (c *Cycle[S]) Index(n int) int {
+	if n < c.Start {
+		return n
+	}
+	return c.Start + (n-c.Start)%c.Period
+}
+
+// At returns the state after n steps, for example after 1e12 ticks, taking at
+// most Start+Period steps. With a recorded history it takes none.
+func (c *Cycle[S]) At(n int) S {
+	index := c.Index(n)
+	if c.history != nil {
+		return c.history[index]
+	}
+	state := c.start
+	for i := 0; i < index; i++ {
+		state = c.step(state)
+	}
+	return state
+}
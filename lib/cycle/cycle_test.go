@@ -0,0 +1,141 @@
+package cycle
+
+import (
+	"testing"
+
+	"2ajoyce/adventofcode/lib/simulation"
+)
+
+// A process that runs 0 1 2 3 4 5 6 7 3 4 5 6 7 3 ...
+func tableStep(n int) int {
+	if n == 7 {
+		return 3
+	}
+	return n + 1
+}
+
+func identity(n int) int { return n }
+
+type finder func(start int, step func(int) int, key func(int) int, maxSteps int) (*Cycle[int], error)
+
+var finders = []struct {
+	name string
+	find finder
+}{
+	{name: "Brent", find: Brent[int, int]},
+	{name: "History", find: History[int, int]},
+}
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		name      string
+		start     int
+		step      func(int) int
+		wantStart int
+		period    int
+	}{
+		{name: "Tail and loop", start: 0, step: tableStep, wantStart: 3, period: 5},
+		{name: "Starts in the loop", start: 4, step: tableStep, wantStart: 0, period: 5},
+		{name: "Fixed point", start: 9, step: identity, wantStart: 0, period: 1},
+		{name: "Modular", start: 1, step: func(n int) int { return n * 3 % 7 }, wantStart: 0, period: 6},
+	}
+
+	for _, f := range finders {
+		for _, test := range tests {
+			t.Run(f.name+"/"+test.name, func(t *testing.T) {
+				c, err := f.find(test.start, test.step, identity, 0)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if c.Start != test.wantStart || c.Period != test.period {
+					t.Errorf("Expected start %d and period %d, got %d and %d", test.wantStart, test.period, c.Start, c.Period)
+				}
+			})
+		}
+	}
+}
+
+func TestMaxSteps(t *testing.T) {
+	increment := func(n int) int { return n + 1 }
+	for _, f := range finders {
+		t.Run(f.name, func(t *testing.T) {
+			if _, err := f.find(0, increment, identity, 100); err == nil {
+				t.Errorf("Expected an error for a process that never repeats")
+			}
+		})
+	}
+}
+
+func TestAt(t *testing.T) {
+	for _, f := range finders {
+		t.Run(f.name, func(t *testing.T) {
+			c, err := f.find(0, tableStep, identity, 0)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			// Compare against taking every step
+			state := 0
+			for n := 0; n < 50; n++ {
+				if got := c.At(n); got != state {
+					t.Errorf("At(%d): expected %d, got %d", n, state, got)
+				}
+				state = tableStep(state)
+			}
+
+			// 1e12 - 3 is a multiple of 5, so it lands back on the start of the loop
+			if got := c.At(1_000_000_000_000 + 3); got != 3 {
+				t.Errorf("At(1e12+3): expected 3, got %d", got)
+			}
+		})
+	}
+}
+
+func TestSimulation(t *testing.T) {
+	// Two robots walking across a 5x3 map and wrapping around its edges
+	sim := simulation.NewSimulation(5, 3)
+	for _, robot := range []struct {
+		coord     simulation.Coord
+		direction simulation.Direction
+	}{
+		{simulation.Coord{X: 0, Y: 0}, simulation.East},
+		{simulation.Coord{X: 2, Y: 1}, simulation.South},
+	} {
+		e, err := simulation.NewEntity("robot")
+		if err != nil {
+			t.Fatalf("Failed to create entity: %v", err)
+		}
+		if _, err := sim.AddEntity(e, []simulation.Coord{robot.coord}, robot.direction); err != nil {
+			t.Fatalf("Failed to add entity: %v", err)
+		}
+	}
+
+	tick := func(s simulation.Simulation) simulation.Simulation {
+		next := s.Clone()
+		for _, e := range next.GetEntities() {
+			if err := next.MoveEntity(e.GetId(), true); err != nil {
+				t.Fatalf("Failed to move entity: %v", err)
+			}
+		}
+		return next
+	}
+	key := func(s simulation.Simulation) uint64 { return s.StateHash() }
+
+	c, err := Brent(sim, tick, key, 1000)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// The robots line up again once both have wrapped, after lcm(5, 3) ticks
+	if c.Start != 0 || c.Period != 15 {
+		t.Errorf("Expected start 0 and period 15, got %d and %d", c.Start, c.Period)
+	}
+
+	// After 1e12 ticks, 1e12 % 15 = 10 ticks have effectively been taken
+	expected := sim
+	for i := 0; i < 10; i++ {
+		expected = tick(expected)
+	}
+	if got := c.At(1_000_000_000_000); got.StateHash() != expected.StateHash() {
+		t.Errorf("Expected the state after 1e12 ticks to match the state after 10")
+	}
+}
@@ -0,0 +1,5 @@
+module 2ajoyce/adventofcode/lib
+
+go 1.23.4
+
+require github.com/google/uuid v1.6.0
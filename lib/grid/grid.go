@@ -0,0 +1,303 @@
+// Package grid holds a rectangular grid of cells, the shape most puzzle inputs
+// come in. Cells are addressed with a simulation.Coord, with (0, 0) in the top
+// left corner and Y growing downwards, the same as the lines of the input.
+package grid
+
+import (
+	"fmt"
+	"iter"
+	"strings"
+
+	"2ajoyce/adventofcode/lib/simulation"
+)
+
+/////////////////////////////////////////////////////////////////////////////////////
+// GRID
+/////////////////////////////////////////////////////////////////////////////////////
+
+// Grid is a rectangular grid of cells of type T.
+// The cells are stored row by row in a single slice.
+type Grid[T any] struct {
+	width, height int
+	cells         []T
+}
+
+// New returns a grid of the given size with every cell set to the zero value.
+func New[T any](width, height int) *Grid[T] {
+	return &Grid[T]{
+		width:  width,
+		height: height,
+		cells:  make([]T, width*height),
+	}
+}
+
+// Parse builds a grid from the lines of an input, mapping every rune to a cell.
+// All lines must be the same length. A trailing empty line is ignored.
+func Parse[T any](lines []string, cell func(r rune) (T, error)) (*Grid[T], error) {
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("grid is empty")
+	}
+
+	width := len([]rune(lines[0]))
+	g := New[T](width, len(lines))
+	for y, line := range lines {
+		runes := []rune(line)
+		if len(runes) != width {
+			return nil, fmt.Errorf("line %d is %d cells wide, expected %d", y+1, len(runes), width)
+		}
+		for x, r := range runes {
+			v, err := cell(r)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cell %q at (%d, %d): %v", r, x, y, err)
+			}
+			g.cells[y*width+x] = v
+		}
+	}
+	return g, nil
+}
+
+// ParseRunes builds a grid holding the runes of the lines as they are.
+func ParseRunes(lines []string) (*Grid[rune], error) {
+	return Parse(lines, func(r rune) (rune, error) { return r, nil })
+}
+
+func (g *Grid[T]) Width() int {
+	return g.width
+}
+
+func (g *Grid[T]) Height() int {
+	return g.height
+}
+
+// InBounds reports whether c is a cell of the grid.
+func (g *Grid[T]) InBounds(c simulation.Coord) bool {
+	return c.X >= 0 && c.Y >= 0 && c.X < g.width && c.Y < g.height
+}
+
+// Get returns the cell at c. It panics if c is out of bounds, use Lookup when
+// that can happen.
+func (g *Grid[T]) Get(c simulation.Coord) T {
+	if !g.InBounds(c) {
+		panic(fmt.Sprintf("coordinate %s is outside the %dx%d grid", c.String(), g.width, g.height))
+	}
+	return g.cells[c.Y*g.width+c.X]
+}
+
+// Lookup returns the cell at c, and false if c is out of bounds.
+func (g *Grid[T]) Lookup(c simulation.Coord) (T, bool) {
+	if !g.InBounds(c) {
+		var zero T
+		return zero, false
+	}
+	return g.cells[c.Y*g.width+c.X], true
+}
+
+// Set sets the cell at c. It panics if c is out of bounds.
+func (g *Grid[T]) Set(c simulation.Coord, v T) {
+	if !g.InBounds(c) {
+		panic(fmt.Sprintf("coordinate %s is outside the %dx%d grid", c.String(), g.width, g.height))
+	}
+	g.cells[c.Y*g.width+c.X] = v
+}
+
+// Clone returns a copy of the grid. Cells are copied as values.
+func (g *Grid[T]) Clone() *Grid[T] {
+	return &Grid[T]{
+		width:  g.width,
+		height: g.height,
+		cells:  append([]T(nil), g.cells...),
+	}
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// ITERATION
+/////////////////////////////////////////////////////////////////////////////////////
+
+// All iterates over every cell, row by row.
+func (g *Grid[T]) All() iter.Seq2[simulation.Coord, T] {
+	return func(yield func(simulation.Coord, T) bool) {
+		for i, v := range g.cells {
+			if !yield(simulation.Coord{X: i % g.width, Y: i / g.width}, v) {
+				return
+			}
+		}
+	}
+}
+
+// Offsets of the four orthogonal neighbours, clockwise from north
+var orthogonal = []simulation.Direction{
+	simulation.North,
+	simulation.East,
+	simulation.South,
+	simulation.West,
+}
+
+// Offsets of all eight neighbours, clockwise from north
+var surrounding = []simulation.Direction{
+	{VX: 0, VY: -1},
+	{VX: 1, VY: -1},
+	{VX: 1, VY: 0},
+	{VX: 1, VY: 1},
+	{VX: 0, VY: 1},
+	{VX: -1, VY: 1},
+	{VX: -1, VY: 0},
+	{VX: -1, VY: -1},
+}
+
+// Neighbors4 iterates over the orthogonal neighbours of c that are inside the
+// grid, clockwise from north.
+func (g *Grid[T]) Neighbors4(c simulation.Coord) iter.Seq[simulation.Coord] {
+	return g.neighbors(c, orthogonal)
+}
+
+// Neighbors8 iterates over the orthogonal and diagonal neighbours of c that are
+// inside the grid, clockwise from north.
+func (g *Grid[T]) Neighbors8(c simulation.Coord) iter.Seq[simulation.Coord] {
+	return g.neighbors(c, surrounding)
+}
+
+func (g *Grid[T]) neighbors(c simulation.Coord, offsets []simulation.Direction) iter.Seq[simulation.Coord] {
+	return func(yield func(simulation.Coord) bool) {
+		for _, d := range offsets {
+			n := c.Move(d)
+			if g.InBounds(n) && !yield(n) {
+				return
+			}
+		}
+	}
+}
+
+// Row returns row y. The slice shares the grid's storage, so setting one of its
+// cells sets the cell in the grid.
+func (g *Grid[T]) Row(y int) []T {
+	return g.cells[y*g.width : (y+1)*g.width : (y+1)*g.width]
+}
+
+// Column returns a copy of column x, top to bottom. It panics if x is out of
+// bounds.
+func (g *Grid[T]) Column(x int) []T {
+	if x < 0 || x >= g.width {
+		panic(fmt.Sprintf("column %d is outside the %dx%d grid", x, g.width, g.height))
+	}
+	column := make([]T, g.height)
+	for y := range column {
+		column[y] = g.cells[y*g.width+x]
+	}
+	return column
+}
+
+// FindAll returns the coordinates of every cell matching match, row by row.
+func (g *Grid[T]) FindAll(match func(T) bool) []simulation.Coord {
+	var found []simulation.Coord
+	for c, v := range g.All() {
+		if match(v) {
+			found = append(found, c)
+		}
+	}
+	return found
+}
+
+// Find returns the coordinate of the first cell matching match, row by row, and
+// false if no cell does.
+func (g *Grid[T]) Find(match func(T) bool) (simulation.Coord, bool) {
+	for c, v := range g.All() {
+		if match(v) {
+			return c, true
+		}
+	}
+	return simulation.Coord{}, false
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// TRANSFORMATIONS
+/////////////////////////////////////////////////////////////////////////////////////
+
+// The transformations return a new grid and leave the original as it is.
+
+// remap builds a grid of the given size where every cell is taken from the cell
+// of g that from returns.
+func (g *Grid[T]) remap(width, height int, from func(x, y int) simulation.Coord) *Grid[T] {
+	result := New[T](width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			src := from(x, y)
+			result.cells[y*width+x] = g.cells[src.Y*g.width+src.X]
+		}
+	}
+	return result
+}
+
+// Transpose mirrors the grid along its main diagonal, turning rows into columns.
+func (g *Grid[T]) Transpose() *Grid[T] {
+	return g.remap(g.height, g.width, func(x, y int) simulation.Coord {
+		return simulation.Coord{X: y, Y: x}
+	})
+}
+
+// RotateRight rotates the grid a quarter turn clockwise.
+func (g *Grid[T]) RotateRight() *Grid[T] {
+	return g.remap(g.height, g.width, func(x, y int) simulation.Coord {
+		return simulation.Coord{X: y, Y: g.height - 1 - x}
+	})
+}
+
+// RotateLeft rotates the grid a quarter turn counter-clockwise.
+func (g *Grid[T]) RotateLeft() *Grid[T] {
+	return g.remap(g.height, g.width, func(x, y int) simulation.Coord {
+		return simulation.Coord{X: g.width - 1 - y, Y: x}
+	})
+}
+
+// FlipHorizontal mirrors the grid left to right.
+func (g *Grid[T]) FlipHorizontal() *Grid[T] {
+	return g.remap(g.width, g.height, func(x, y int) simulation.Coord {
+		return simulation.Coord{X: g.width - 1 - x, Y: y}
+	})
+}
+
+// FlipVertical mirrors the grid top to bottom.
+func (g *Grid[T]) FlipVertical() *Grid[T] {
+	return g.remap(g.width, g.height, func(x, y int) simulation.Coord {
+		return simulation.Coord{X: x, Y: g.height - 1 - y}
+	})
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// RENDERING
+/////////////////////////////////////////////////////////////////////////////////////
+
+// Render draws the grid with one rune per cell and a newline after every row.
+func (g *Grid[T]) Render(cell func(c simulation.Coord, v T) rune) string {
+	var sb strings.Builder
+	sb.Grow((g.width + 1) * g.height)
+	for c, v := range g.All() {
+		sb.WriteRune(cell(c, v))
+		if c.X == g.width-1 {
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
+
+// String draws the grid with a newline after every row. Rune and byte cells are
+// drawn as characters, anything else is formatted with fmt.Sprint.
+func (g *Grid[T]) String() string {
+	var sb strings.Builder
+	for c, v := range g.All() {
+		switch v := any(v).(type) {
+		case rune:
+			sb.WriteRune(v)
+		case byte:
+			sb.WriteByte(v)
+		default:
+			sb.WriteString(fmt.Sprint(v))
+		}
+		if c.X == g.width-1 {
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,230 @@
+package grid
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+
+	"2ajoyce/adventofcode/lib/simulation"
+)
+
+// The grid most tests run on
+//
+//	abc
+//	def
+var lines = []string{"abc", "def"}
+
+func parseTestGrid(t *testing.T) *Grid[rune] {
+	t.Helper()
+	g, err := ParseRunes(lines)
+	if err != nil {
+		t.Fatalf("Failed to parse grid: %v", err)
+	}
+	return g
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		lines   []string
+		want    string
+		wantErr bool
+	}{
+		{name: "Digits", lines: []string{"123", "456"}, want: "123\n456\n"},
+		{name: "Trailing empty line", lines: []string{"12", "34", ""}, want: "12\n34\n"},
+		{name: "Ragged rows", lines: []string{"123", "45"}, wantErr: true},
+		{name: "Not a digit", lines: []string{"1x"}, wantErr: true},
+		{name: "Empty", lines: []string{}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g, err := Parse(test.lines, func(r rune) (int, error) {
+				return strconv.Atoi(string(r))
+			})
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got grid\n%s", g)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := g.String(); got != test.want {
+				t.Errorf("Expected\n%s\ngot\n%s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestGetSet(t *testing.T) {
+	g := parseTestGrid(t)
+
+	if got := g.Get(simulation.Coord{X: 2, Y: 1}); got != 'f' {
+		t.Errorf("Expected f, got %c", got)
+	}
+	g.Set(simulation.Coord{X: 0, Y: 1}, 'x')
+	if got := g.String(); got != "abc\nxef\n" {
+		t.Errorf("Expected the set cell to change, got\n%s", got)
+	}
+
+	if _, ok := g.Lookup(simulation.Coord{X: 3, Y: 0}); ok {
+		t.Errorf("Expected (3, 0) to be out of bounds")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected Get out of bounds to panic")
+		}
+	}()
+	g.Get(simulation.Coord{X: -1, Y: 0})
+}
+
+func TestInBounds(t *testing.T) {
+	g := parseTestGrid(t)
+	tests := []struct {
+		coord    simulation.Coord
+		expected bool
+	}{
+		{simulation.Coord{X: 0, Y: 0}, true},
+		{simulation.Coord{X: 2, Y: 1}, true},
+		{simulation.Coord{X: 3, Y: 1}, false},
+		{simulation.Coord{X: 2, Y: 2}, false},
+		{simulation.Coord{X: -1, Y: 0}, false},
+		{simulation.Coord{X: 0, Y: -1}, false},
+	}
+
+	for _, test := range tests {
+		if result := g.InBounds(test.coord); result != test.expected {
+			t.Errorf("InBounds(%s): expected %v, got %v", test.coord.String(), test.expected, result)
+		}
+	}
+}
+
+func TestNeighbors(t *testing.T) {
+	g := parseTestGrid(t)
+	tests := []struct {
+		name     string
+		coord    simulation.Coord
+		diagonal bool
+		expected string
+	}{
+		{name: "Corner, orthogonal", coord: simulation.Coord{X: 0, Y: 0}, expected: "bd"},
+		{name: "Edge, orthogonal", coord: simulation.Coord{X: 1, Y: 1}, expected: "bfd"},
+		{name: "Corner, surrounding", coord: simulation.Coord{X: 0, Y: 0}, diagonal: true, expected: "bed"},
+		{name: "Edge, surrounding", coord: simulation.Coord{X: 1, Y: 0}, diagonal: true, expected: "cfeda"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			neighbors := g.Neighbors4(test.coord)
+			if test.diagonal {
+				neighbors = g.Neighbors8(test.coord)
+			}
+			result := ""
+			for n := range neighbors {
+				result += string(g.Get(n))
+			}
+			if result != test.expected {
+				t.Errorf("Expected %s, got %s", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestRowColumn(t *testing.T) {
+	g := parseTestGrid(t)
+
+	if got := string(g.Row(1)); got != "def" {
+		t.Errorf("Expected row def, got %s", got)
+	}
+	if got := string(g.Column(2)); got != "cf" {
+		t.Errorf("Expected column cf, got %s", got)
+	}
+
+	// Rows share the grid's storage
+	g.Row(0)[1] = 'x'
+	if got := g.Get(simulation.Coord{X: 1, Y: 0}); got != 'x' {
+		t.Errorf("Expected setting the row to set the grid, got %c", got)
+	}
+
+	// Columns past the edge would otherwise read from the next row
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected Column out of bounds to panic")
+		}
+	}()
+	g.Column(3)
+}
+
+func TestTransformations(t *testing.T) {
+	tests := []struct {
+		name      string
+		transform func(*Grid[rune]) *Grid[rune]
+		expected  string
+	}{
+		{name: "Transpose", transform: (*Grid[rune]).Transpose, expected: "ad\nbe\ncf\n"},
+		{name: "RotateRight", transform: (*Grid[rune]).RotateRight, expected: "da\neb\nfc\n"},
+		{name: "RotateLeft", transform: (*Grid[rune]).RotateLeft, expected: "cf\nbe\nad\n"},
+		{name: "FlipHorizontal", transform: (*Grid[rune]).FlipHorizontal, expected: "cba\nfed\n"},
+		{name: "FlipVertical", transform: (*Grid[rune]).FlipVertical, expected: "def\nabc\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := parseTestGrid(t)
+			result := test.transform(g)
+			if got := result.String(); got != test.expected {
+				t.Errorf("Expected\n%s\ngot\n%s", test.expected, got)
+			}
+			if got := g.String(); got != "abc\ndef\n" {
+				t.Errorf("Expected the original grid to be unchanged, got\n%s", got)
+			}
+		})
+	}
+
+	// Four quarter turns bring the grid back
+	g := parseTestGrid(t)
+	if got := g.RotateRight().RotateRight().RotateRight().RotateRight().String(); got != g.String() {
+		t.Errorf("Expected four rotations to give the original grid, got\n%s", got)
+	}
+}
+
+func TestFindAll(t *testing.T) {
+	g, err := ParseRunes([]string{"#.#", "..#"})
+	if err != nil {
+		t.Fatalf("Failed to parse grid: %v", err)
+	}
+
+	found := g.FindAll(func(r rune) bool { return r == '#' })
+	expected := []simulation.Coord{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 1}}
+	if !slices.Equal(found, expected) {
+		t.Errorf("Expected %v, got %v", expected, found)
+	}
+
+	if c, ok := g.Find(func(r rune) bool { return r == '.' }); !ok || c != (simulation.Coord{X: 1, Y: 0}) {
+		t.Errorf("Expected to find . at (1, 0), got %v, %v", c, ok)
+	}
+	if _, ok := g.Find(func(r rune) bool { return r == 'x' }); ok {
+		t.Errorf("Expected not to find x")
+	}
+}
+
+func TestRender(t *testing.T) {
+	g := New[int](3, 2)
+	g.Set(simulation.Coord{X: 1, Y: 1}, 7)
+
+	if got := g.String(); got != "000\n070\n" {
+		t.Errorf("Expected String to format the ints, got\n%s", got)
+	}
+
+	rendered := g.Render(func(c simulation.Coord, v int) rune {
+		if v > 0 {
+			return '#'
+		}
+		return '.'
+	})
+	if rendered != "...\n.#.\n" {
+		t.Errorf("Expected Render to use the cell function, got\n%s", rendered)
+	}
+}
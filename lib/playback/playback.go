@@ -0,0 +1,349 @@
+// Package playback plays a simulation back in the terminal, redrawing it in
+// place with ANSI escape codes instead of printing one map after the other, and
+// can save the session as an asciicast v2 file to share with asciinema.
+//
+// Solutions get the player the runner set up with FromContext. When nothing is
+// being played it returns nil, and a nil *Player ignores every frame:
+//
+//	playback.FromContext(ctx).Frame(sim, &warehouseStyle, fmt.Sprintf("Move %d", i))
+package playback
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"2ajoyce/adventofcode/lib/simulation"
+)
+
+/////////////////////////////////////////////////////////////////////////////////////
+// STYLE
+/////////////////////////////////////////////////////////////////////////////////////
+
+// Style describes how a day's simulation is played back
+type Style struct {
+	// Legend draws the simulation as runes and tells which entity type each
+	// rune stands for
+	Legend *simulation.Legend
+	// Colors maps entity types to ANSI SGR parameters, "31" for red or "1;33"
+	// for bold yellow. Other types and empty cells are drawn uncolored.
+	Colors map[string]string
+	// Terrain maps terrain values to ANSI SGR parameters, for the runes of
+	// the Legend that stand for terrain
+	Terrain map[byte]string
+	// PauseWhen pauses playback after drawing a frame it returns true for, for
+	// example when an entity enters a cell
+	PauseWhen func(sim simulation.Simulation) bool
+}
+
+// color returns the SGR parameters of a rune, empty when it is uncolored
+func (s *Style) color(r rune) string {
+	if s.Legend == nil {
+		return ""
+	}
+	if entityType, ok := s.Legend.EntityType(r); ok {
+		return s.Colors[entityType]
+	}
+	if value, ok := s.Legend.Terrain(r); ok {
+		return s.Terrain[value]
+	}
+	return ""
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// PLAYER
+/////////////////////////////////////////////////////////////////////////////////////
+
+const (
+	escape      = "\x1b["
+	home        = escape + "H"
+	clearScreen = escape + "2J"
+	clearLine   = escape + "K"
+	clearBelow  = escape + "J"
+	reset       = escape + "0m"
+	// The terminal is switched to its alternate screen while playing, so the
+	// output printed before and after is left as it was
+	enterPlayback = escape + "?1049h" + escape + "?25l"
+	exitPlayback  = escape + "?25h" + escape + "?1049l"
+)
+
+// Player draws frames to a terminal, a cast, or both
+type Player struct {
+	terminal io.Writer     // Redrawn in place, nil when only casting
+	input    *bufio.Reader // Read from to resume after a pause, nil never waits
+	cast     io.Writer     // asciicast v2 events, nil when not casting
+	interval time.Duration // Time between frames, 0 draws as fast as possible
+
+	frames  int       // Frames drawn so far
+	started time.Time // When the first frame was drawn
+	next    time.Time // When the terminal may show the next frame
+	width   int       // Widest frame, for the cast header
+	err     error     // First error writing, every later frame is skipped
+}
+
+// Option configures a Player
+type Option func(p *Player)
+
+// WithTerminal redraws every frame in place on w, at the frame rate
+func WithTerminal(w io.Writer) Option {
+	return func(p *Player) {
+		p.terminal = w
+	}
+}
+
+// WithInput waits for a line from r, usually os.Stdin, to resume playback after
+// a pause. Without it pauses are only marked in the cast.
+func WithInput(r io.Reader) Option {
+	return func(p *Player) {
+		if r != nil {
+			p.input = bufio.NewReader(r)
+		}
+	}
+}
+
+// WithFrameRate sets the number of frames shown per second. 0 draws the
+// terminal as fast as it can and times the cast by the real time between frames.
+func WithFrameRate(fps float64) Option {
+	return func(p *Player) {
+		p.interval = 0
+		if fps > 0 {
+			p.interval = time.Duration(float64(time.Second) / fps)
+		}
+	}
+}
+
+// WithCast saves every frame to w as an asciicast v2 recording. Frames are
+// timed by the frame rate rather than by how long the terminal took to draw
+// them, so the cast plays back evenly.
+func WithCast(w io.Writer) Option {
+	return func(p *Player) {
+		p.cast = w
+	}
+}
+
+// New creates a player drawing 10 frames per second
+func New(options ...Option) *Player {
+	p := &Player{interval: 100 * time.Millisecond}
+	for _, option := range options {
+		option(p)
+	}
+	return p
+}
+
+// Frames returns the number of frames drawn
+func (p *Player) Frames() int {
+	if p == nil {
+		return 0
+	}
+	return p.frames
+}
+
+// Frame draws the simulation with a status line underneath, then pauses if the
+// style asks for it
+func (p *Player) Frame(sim simulation.Simulation, style *Style, status string) error {
+	if p == nil {
+		return nil
+	}
+	if err := p.Lines(style.Legend.RenderLines(sim), style, status); err != nil {
+		return err
+	}
+	if style.PauseWhen != nil && style.PauseWhen(sim) {
+		return p.Pause(status)
+	}
+	return nil
+}
+
+// Lines draws lines of runes that are not a Simulation, coloring them through
+// the style's legend, with a status line underneath
+func (p *Player) Lines(lines []string, style *Style, status string) error {
+	if p == nil || p.err != nil {
+		return p.Err()
+	}
+
+	var sb strings.Builder
+	if p.frames == 0 {
+		sb.WriteString(clearScreen)
+	}
+	sb.WriteString(home)
+	for _, line := range lines {
+		p.width = max(p.width, len([]rune(line)))
+		writeColored(&sb, line, style)
+		sb.WriteString(clearLine + "\r\n")
+	}
+	sb.WriteString(status + clearLine + clearBelow)
+	frame := sb.String()
+	p.width = max(p.width, len([]rune(status)))
+
+	if p.terminal != nil {
+		p.wait()
+		if p.frames == 0 {
+			frame = enterPlayback + frame
+		}
+		if _, err := io.WriteString(p.terminal, frame); err != nil {
+			p.err = fmt.Errorf("error drawing frame %d: %v", p.frames, err)
+			return p.err
+		}
+	}
+	if p.frames == 0 {
+		p.started = time.Now()
+	}
+	if p.cast != nil {
+		if p.frames == 0 {
+			p.castHeader(len(lines) + 1)
+		}
+		p.castEvent(p.frames, "o", frame)
+	}
+	p.frames++
+	return p.err
+}
+
+// Pause stops playback until a line is read from the input, and marks the
+// pause in the cast so it can be jumped to. A player without input does not
+// wait, and stops waiting once its input runs out.
+func (p *Player) Pause(message string) error {
+	if p == nil || p.err != nil {
+		return p.Err()
+	}
+	if p.cast != nil {
+		p.castEvent(max(p.frames-1, 0), "m", message)
+	}
+	if p.terminal == nil || p.input == nil {
+		return p.err
+	}
+	if _, err := fmt.Fprintf(p.terminal, "\r\nPaused: %s, press Enter to continue%s", message, clearLine); err != nil {
+		p.err = fmt.Errorf("error drawing pause: %v", err)
+		return p.err
+	}
+	if _, err := p.input.ReadString('\n'); err != nil {
+		p.input = nil // Nobody left to resume, play the rest without stopping
+	}
+	p.next = time.Now()
+	return nil
+}
+
+// Close leaves the terminal the way it was found. It returns the first error
+// met while playing.
+func (p *Player) Close() error {
+	if p == nil {
+		return nil
+	}
+	if p.terminal != nil && p.frames > 0 {
+		if _, err := io.WriteString(p.terminal, exitPlayback); err != nil && p.err == nil {
+			p.err = fmt.Errorf("error restoring the terminal: %v", err)
+		}
+	}
+	return p.err
+}
+
+// Err returns the first error met while playing
+func (p *Player) Err() error {
+	if p == nil {
+		return nil
+	}
+	return p.err
+}
+
+// wait holds the terminal back until the next frame is due
+func (p *Player) wait() {
+	if p.interval == 0 {
+		return
+	}
+	now := time.Now()
+	if p.next.After(now) {
+		time.Sleep(p.next.Sub(now))
+		now = p.next
+	}
+	p.next = now.Add(p.interval)
+}
+
+// writeColored writes a line, wrapping runs of runes of the same color in SGR
+// codes
+func writeColored(sb *strings.Builder, line string, style *Style) {
+	current := ""
+	for _, r := range line {
+		if c := style.color(r); c != current {
+			if current != "" {
+				sb.WriteString(reset)
+			}
+			if c != "" {
+				sb.WriteString(escape + c + "m")
+			}
+			current = c
+		}
+		sb.WriteRune(r)
+	}
+	if current != "" {
+		sb.WriteString(reset)
+	}
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// ASCIICAST
+/////////////////////////////////////////////////////////////////////////////////////
+
+const minCastWidth = 80
+
+// castHeader writes the first line of an asciicast v2 file. The terminal is as
+// wide as the first frame and at least 80 columns, so longer status lines fit.
+// Frames that grow wider than that get cut off by players.
+func (p *Player) castHeader(height int) {
+	header := struct {
+		Version   int               `json:"version"`
+		Width     int               `json:"width"`
+		Height    int               `json:"height"`
+		Timestamp int64             `json:"timestamp"`
+		Env       map[string]string `json:"env"`
+	}{
+		Version:   2,
+		Width:     max(p.width, minCastWidth),
+		Height:    height,
+		Timestamp: time.Now().Unix(),
+		Env:       map[string]string{"TERM": "xterm-256color"},
+	}
+	p.castLine(header)
+}
+
+// castEvent writes an event at the time of a frame
+func (p *Player) castEvent(frame int, kind, data string) {
+	seconds := float64(frame) * p.interval.Seconds()
+	if p.interval == 0 {
+		seconds = time.Since(p.started).Seconds()
+	}
+	p.castLine([]any{seconds, kind, data})
+}
+
+// castLine writes a line of JSON to the cast
+func (p *Player) castLine(v any) {
+	if p.err != nil {
+		return
+	}
+	line, err := json.Marshal(v)
+	if err == nil {
+		_, err = p.cast.Write(append(line, '\n'))
+	}
+	if err != nil {
+		p.err = fmt.Errorf("error writing cast: %v", err)
+	}
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// CONTEXT
+/////////////////////////////////////////////////////////////////////////////////////
+
+type contextKey struct{}
+
+// NewContext returns a context carrying the player, for the runner to hand to a
+// part
+func NewContext(ctx context.Context, p *Player) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext returns the player of the part, nil when it is not played back
+func FromContext(ctx context.Context) *Player {
+	p, _ := ctx.Value(contextKey{}).(*Player)
+	return p
+}
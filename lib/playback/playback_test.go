@@ -0,0 +1,179 @@
+package playback
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"2ajoyce/adventofcode/lib/simulation"
+)
+
+var testLegend = simulation.MustNewLegend('.', map[rune]string{
+	'#': "wall",
+	'@': "robot",
+}, simulation.WithShape("box", "[]"))
+
+var testStyle = Style{
+	Legend: testLegend,
+	Colors: map[string]string{"wall": "90", "box": "33"},
+}
+
+func parseTestSim(t *testing.T, lines ...string) simulation.Simulation {
+	t.Helper()
+	sim, err := testLegend.Parse(lines)
+	if err != nil {
+		t.Fatalf("Failed to parse map: %v", err)
+	}
+	return sim
+}
+
+func TestFrame(t *testing.T) {
+	var terminal strings.Builder
+	p := New(WithTerminal(&terminal), WithFrameRate(0))
+	if err := p.Frame(parseTestSim(t, "#[]@"), &testStyle, "Tick 1"); err != nil {
+		t.Fatalf("Failed to draw frame: %v", err)
+	}
+	first := terminal.String()
+	if !strings.HasPrefix(first, enterPlayback+clearScreen+home) {
+		t.Errorf("Expected the first frame to switch screens and clear it, got %q", first)
+	}
+	want := "\x1b[90m#\x1b[0m\x1b[33m[]\x1b[0m@" + clearLine + "\r\nTick 1" + clearLine + clearBelow
+	if !strings.HasSuffix(first, want) {
+		t.Errorf("Expected colored runs of runes\n%q\ngot\n%q", want, first)
+	}
+
+	terminal.Reset()
+	if err := p.Frame(parseTestSim(t, "#.[]"), &testStyle, "Tick 2"); err != nil {
+		t.Fatalf("Failed to draw frame: %v", err)
+	}
+	if !strings.HasPrefix(terminal.String(), home+"\x1b[90m#") {
+		t.Errorf("Expected later frames to redraw in place, got %q", terminal.String())
+	}
+
+	terminal.Reset()
+	if err := p.Close(); err != nil {
+		t.Fatalf("Failed to close: %v", err)
+	}
+	if terminal.String() != exitPlayback {
+		t.Errorf("Expected the terminal to be restored, got %q", terminal.String())
+	}
+	if p.Frames() != 2 {
+		t.Errorf("Expected 2 frames, got %d", p.Frames())
+	}
+}
+
+func TestFrameTerrain(t *testing.T) {
+	legend := simulation.MustNewLegend('.', map[rune]string{'@': "robot"}, simulation.WithTerrainRune('#', 1, true))
+	sim, err := legend.Parse([]string{"#@"})
+	if err != nil {
+		t.Fatalf("Failed to parse map: %v", err)
+	}
+	var terminal strings.Builder
+	p := New(WithTerminal(&terminal), WithFrameRate(0))
+	style := Style{Legend: legend, Terrain: map[byte]string{1: "90"}}
+	if err := p.Frame(sim, &style, ""); err != nil {
+		t.Fatalf("Failed to draw frame: %v", err)
+	}
+	if want := "\x1b[90m#\x1b[0m@" + clearLine; !strings.Contains(terminal.String(), want) {
+		t.Errorf("Expected the terrain colored\n%q\ngot\n%q", want, terminal.String())
+	}
+}
+
+func TestPauseWhen(t *testing.T) {
+	style := testStyle
+	style.PauseWhen = func(sim simulation.Simulation) bool {
+		cell, err := sim.GetMap().GetCell(simulation.Coord{X: 0, Y: 0})
+		return err == nil && !cell.IsEmpty()
+	}
+
+	tests := []struct {
+		name       string
+		input      string
+		frames     [][]string
+		wantPauses int
+	}{
+		{name: "No pause", input: "\n", frames: [][]string{{".."}, {".."}}, wantPauses: 0},
+		{name: "Pause once", input: "\n", frames: [][]string{{".."}, {"@."}, {".."}}, wantPauses: 1},
+		{name: "Input runs out", input: "", frames: [][]string{{"@."}, {"@."}}, wantPauses: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var terminal strings.Builder
+			p := New(WithTerminal(&terminal), WithInput(strings.NewReader(test.input)), WithFrameRate(0))
+			for _, lines := range test.frames {
+				if err := p.Frame(parseTestSim(t, lines...), &style, "status"); err != nil {
+					t.Fatalf("Failed to draw frame: %v", err)
+				}
+			}
+			if got := strings.Count(terminal.String(), "Paused: status"); got != test.wantPauses {
+				t.Errorf("Expected %d pauses, got %d", test.wantPauses, got)
+			}
+		})
+	}
+}
+
+func TestCast(t *testing.T) {
+	var cast strings.Builder
+	p := New(WithCast(&cast), WithFrameRate(4))
+	for _, lines := range [][]string{{"@..", "..."}, {".@.", "..."}, {"..@", "..."}} {
+		if err := p.Frame(parseTestSim(t, lines...), &testStyle, "status"); err != nil {
+			t.Fatalf("Failed to draw frame: %v", err)
+		}
+	}
+	if err := p.Pause("done"); err != nil {
+		t.Fatalf("Failed to pause: %v", err)
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(cast.String()))
+	if !scanner.Scan() {
+		t.Fatalf("Expected a header")
+	}
+	var header struct {
+		Version int `json:"version"`
+		Width   int `json:"width"`
+		Height  int `json:"height"`
+	}
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		t.Fatalf("Failed to decode header: %v", err)
+	}
+	if header.Version != 2 || header.Width != minCastWidth || header.Height != 3 {
+		t.Errorf("Expected a version 2 header %d wide and 3 high, got %+v", minCastWidth, header)
+	}
+
+	var times []float64
+	var kinds []string
+	for scanner.Scan() {
+		var event []any
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("Failed to decode event %s: %v", scanner.Text(), err)
+		}
+		times = append(times, event[0].(float64))
+		kinds = append(kinds, event[1].(string))
+	}
+	wantTimes := []float64{0, 0.25, 0.5, 0.5}
+	wantKinds := []string{"o", "o", "o", "m"}
+	if len(times) != len(wantTimes) {
+		t.Fatalf("Expected %d events, got %d", len(wantTimes), len(times))
+	}
+	for i := range wantTimes {
+		if times[i] != wantTimes[i] || kinds[i] != wantKinds[i] {
+			t.Errorf("Expected event %d at %v of kind %s, got %v %s", i, wantTimes[i], wantKinds[i], times[i], kinds[i])
+		}
+	}
+}
+
+func TestNilPlayer(t *testing.T) {
+	var p *Player
+	if err := p.Frame(parseTestSim(t, "@"), &testStyle, ""); err != nil {
+		t.Errorf("Expected a nil player to ignore frames, got %v", err)
+	}
+	if err := p.Close(); err != nil || p.Frames() != 0 {
+		t.Errorf("Expected a nil player to do nothing")
+	}
+	if FromContext(context.Background()) != nil {
+		t.Errorf("Expected no player in an empty context")
+	}
+}
@@ -0,0 +1,400 @@
+// Package record captures frames of a simulation or grid while a solution runs
+// and writes them out as an animated GIF or a series of PNGs, without needing a
+// display.
+//
+// Solutions get the recorder the runner set up with FromContext. When nothing
+// is being recorded it returns nil, and a nil *Recorder ignores every frame, so
+// solutions can record unconditionally:
+//
+//	record.FromContext(ctx).Simulation(sim, &warehousePalette)
+//
+// Frames are kept at one pixel per cell and only scaled when they are written,
+// so recording thousands of ticks stays cheap.
+package record
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"2ajoyce/adventofcode/lib/grid"
+	"2ajoyce/adventofcode/lib/simulation"
+)
+
+/////////////////////////////////////////////////////////////////////////////////////
+// PALETTE
+/////////////////////////////////////////////////////////////////////////////////////
+
+// Palette colors the cells of a frame, by entity type and terrain for
+// simulations and by rune for grids. A GIF frame holds at most 256 colors,
+// Background and Unknown included. Declare palettes once and pass them by pointer, the colors are
+// worked out on first use.
+type Palette struct {
+	Background color.Color            // Cells without entities, black when nil
+	Unknown    color.Color            // Entity types and runes missing below, white when nil
+	Types      map[string]color.Color // Entity type -> color
+	Runes      map[rune]color.Color   // Rune -> color
+	Terrain    map[byte]color.Color   // Terrain value -> color, for cells without entities
+
+	once    sync.Once
+	colors  color.Palette
+	types   map[string]uint8 // Entity type -> index in colors
+	runes   map[rune]uint8   // Rune -> index in colors
+	terrain map[byte]uint8   // Terrain value -> index in colors
+	err     error
+}
+
+const (
+	backgroundIndex = 0
+	unknownIndex    = 1
+)
+
+// compile works out the color table of the palette once
+func (p *Palette) compile() error {
+	p.once.Do(func() {
+		if count := len(p.Types) + len(p.Runes) + len(p.Terrain) + 2; count > 256 {
+			p.err = fmt.Errorf("palette has %d colors, a frame holds at most 256", count)
+			return
+		}
+		background, unknown := p.Background, p.Unknown
+		if background == nil {
+			background = color.Black
+		}
+		if unknown == nil {
+			unknown = color.White
+		}
+		p.colors = color.Palette{background, unknown}
+		p.types = make(map[string]uint8, len(p.Types))
+		for entityType, c := range p.Types {
+			p.types[entityType] = uint8(len(p.colors))
+			p.colors = append(p.colors, c)
+		}
+		p.runes = make(map[rune]uint8, len(p.Runes))
+		for r, c := range p.Runes {
+			p.runes[r] = uint8(len(p.colors))
+			p.colors = append(p.colors, c)
+		}
+		p.terrain = make(map[byte]uint8, len(p.Terrain))
+		for value, c := range p.Terrain {
+			p.terrain[value] = uint8(len(p.colors))
+			p.colors = append(p.colors, c)
+		}
+	})
+	return p.err
+}
+
+// runeIndex returns the color index of a rune
+func (p *Palette) runeIndex(r rune) uint8 {
+	if i, ok := p.runes[r]; ok {
+		return i
+	}
+	return unknownIndex
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// RECORDER
+/////////////////////////////////////////////////////////////////////////////////////
+
+// Recorder collects frames in memory until they are written out. It is safe to
+// capture frames from several goroutines, they are kept in the order they arrive.
+type Recorder struct {
+	skip  int           // Keep one frame out of every skip
+	scale int           // Pixels per cell when writing
+	delay time.Duration // Time each frame is shown in a GIF
+
+	mu      sync.Mutex
+	offered int // Frames captured, including the skipped ones
+	frames  []*image.Paletted
+	err     error // First error while capturing, reported when writing
+}
+
+// Option configures a Recorder
+type Option func(r *Recorder)
+
+// WithFrameSkip keeps one frame out of every n, starting with the first
+func WithFrameSkip(n int) Option {
+	return func(r *Recorder) {
+		if n > 0 {
+			r.skip = n
+		}
+	}
+}
+
+// WithScale draws every cell as a square of pixels this wide
+func WithScale(pixels int) Option {
+	return func(r *Recorder) {
+		if pixels > 0 {
+			r.scale = pixels
+		}
+	}
+}
+
+// WithDelay sets how long each frame of a GIF is shown, GIFs count in
+// hundredths of a second
+func WithDelay(d time.Duration) Option {
+	return func(r *Recorder) {
+		if d > 0 {
+			r.delay = d
+		}
+	}
+}
+
+// New creates a recorder that keeps every frame, draws cells 4 pixels wide and
+// shows each frame of a GIF for 100ms
+func New(options ...Option) *Recorder {
+	r := &Recorder{skip: 1, scale: 4, delay: 100 * time.Millisecond}
+	for _, option := range options {
+		option(r)
+	}
+	return r
+}
+
+// Len returns the number of frames kept
+func (r *Recorder) Len() int {
+	if r == nil {
+		return 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.frames)
+}
+
+// Err returns the first error while capturing, which may have kept any frames
+// from being kept at all
+func (r *Recorder) Err() error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// CAPTURING FRAMES
+/////////////////////////////////////////////////////////////////////////////////////
+
+// Simulation captures a frame of the simulation, coloring each cell by the
+// first entity in it that has a color in the palette, or by its terrain when it
+// has no entities. The unbounded axes of a sparse map are drawn as far as its
+// entities reach.
+func (r *Recorder) Simulation(sim simulation.Simulation, p *Palette) {
+	if r == nil {
+		return
+	}
+	m := sim.GetMap()
+	min, max := m.GetBounds()
+	if m.GetWidth() > 0 {
+		min.X, max.X = 0, m.GetWidth()-1
+	}
+	if m.GetHeight() > 0 {
+		min.Y, max.Y = 0, m.GetHeight()-1
+	}
+	terrain := m.GetTerrain()
+	r.capture(max.X-min.X+1, max.Y-min.Y+1, p, func(x, y int) uint8 {
+		coord := simulation.Coord{X: min.X + x, Y: min.Y + y}
+		cell, err := m.GetCell(coord)
+		if err != nil {
+			return unknownIndex
+		}
+		entityIds := cell.GetEntityIds()
+		for _, entityId := range entityIds {
+			e, err := sim.GetEntity(entityId)
+			if err != nil {
+				continue
+			}
+			if i, ok := p.types[e.GetEntityType()]; ok {
+				return i
+			}
+		}
+		if len(entityIds) > 0 {
+			return unknownIndex
+		}
+		if i, ok := p.terrain[terrain.Get(coord)]; ok {
+			return i
+		}
+		return backgroundIndex
+	})
+}
+
+// Grid captures a frame of a grid of runes, colored by rune
+func (r *Recorder) Grid(g *grid.Grid[rune], p *Palette) {
+	GridOf(r, g, p, func(v rune) rune { return v })
+}
+
+// GridOf captures a frame of any grid, turning each value into a rune to look
+// its color up with
+func GridOf[T any](r *Recorder, g *grid.Grid[T], p *Palette, runeOf func(T) rune) {
+	if r == nil {
+		return
+	}
+	r.capture(g.Width(), g.Height(), p, func(x, y int) uint8 {
+		return p.runeIndex(runeOf(g.Get(simulation.Coord{X: x, Y: y})))
+	})
+}
+
+// Cells captures a frame of width by height cells, looking up the rune of each
+// cell, for state that is neither a Simulation nor a Grid
+func (r *Recorder) Cells(width, height int, p *Palette, runeAt func(x, y int) rune) {
+	if r == nil {
+		return
+	}
+	r.capture(width, height, p, func(x, y int) uint8 {
+		return p.runeIndex(runeAt(x, y))
+	})
+}
+
+// capture draws a frame unless it is skipped
+func (r *Recorder) capture(width, height int, p *Palette, indexAt func(x, y int) uint8) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.offered++
+	if (r.offered-1)%r.skip != 0 || r.err != nil {
+		return
+	}
+	if err := p.compile(); err != nil {
+		r.err = err
+		return
+	}
+	frame := image.NewPaletted(image.Rect(0, 0, width, height), p.colors)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			frame.Pix[y*frame.Stride+x] = indexAt(x, y)
+		}
+	}
+	r.frames = append(r.frames, frame)
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// WRITING FRAMES
+/////////////////////////////////////////////////////////////////////////////////////
+
+// WriteGIF writes the frames as an animated GIF that loops forever
+func (r *Recorder) WriteGIF(w io.Writer) error {
+	frames, err := r.scaledFrames()
+	if err != nil {
+		return err
+	}
+
+	anim := &gif.GIF{}
+	delay := int(r.delay / (10 * time.Millisecond))
+	for _, frame := range frames {
+		anim.Image = append(anim.Image, frame)
+		anim.Delay = append(anim.Delay, delay)
+		// Frames can differ in size on a sparse map, the GIF fits the largest
+		anim.Config.Width = max(anim.Config.Width, frame.Rect.Dx())
+		anim.Config.Height = max(anim.Config.Height, frame.Rect.Dy())
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// Save writes the frames to path. A .gif path gets an animated GIF, a .png path
+// gets one numbered PNG per frame next to it, out.png becomes out-0000.png,
+// out-0001.png and so on. It returns the files written.
+func (r *Recorder) Save(path string) ([]string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gif":
+		f, err := os.Create(path)
+		if err != nil {
+			return nil, fmt.Errorf("error creating %s: %v", path, err)
+		}
+		defer f.Close()
+		if err := r.WriteGIF(f); err != nil {
+			return nil, fmt.Errorf("error writing %s: %v", path, err)
+		}
+		return []string{path}, f.Close()
+	case ".png":
+		return r.savePNGs(strings.TrimSuffix(path, filepath.Ext(path)))
+	default:
+		return nil, fmt.Errorf("can not record to %s, use a .gif or .png file", path)
+	}
+}
+
+// savePNGs writes each frame to prefix-NNNN.png
+func (r *Recorder) savePNGs(prefix string) ([]string, error) {
+	frames, err := r.scaledFrames()
+	if err != nil {
+		return nil, err
+	}
+	var written []string
+	for i, frame := range frames {
+		path := fmt.Sprintf("%s-%04d.png", prefix, i)
+		f, err := os.Create(path)
+		if err != nil {
+			return written, fmt.Errorf("error creating %s: %v", path, err)
+		}
+		err = png.Encode(f, frame)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return written, fmt.Errorf("error writing %s: %v", path, err)
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
+
+// scaledFrames returns the frames scaled up for writing
+func (r *Recorder) scaledFrames() ([]*image.Paletted, error) {
+	if r == nil {
+		return nil, fmt.Errorf("nothing was recorded")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return nil, r.err
+	}
+	if len(r.frames) == 0 {
+		return nil, fmt.Errorf("nothing was recorded")
+	}
+
+	scaled := make([]*image.Paletted, len(r.frames))
+	for i, frame := range r.frames {
+		scaled[i] = scale(frame, r.scale)
+	}
+	return scaled, nil
+}
+
+// scale blows every pixel of a frame up into a square
+func scale(frame *image.Paletted, pixels int) *image.Paletted {
+	if pixels == 1 {
+		return frame
+	}
+	width, height := frame.Rect.Dx(), frame.Rect.Dy()
+	scaled := image.NewPaletted(image.Rect(0, 0, width*pixels, height*pixels), frame.Palette)
+	for y := 0; y < height*pixels; y++ {
+		for x := 0; x < width*pixels; x++ {
+			scaled.Pix[y*scaled.Stride+x] = frame.Pix[(y/pixels)*frame.Stride+x/pixels]
+		}
+	}
+	return scaled
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// CONTEXT
+/////////////////////////////////////////////////////////////////////////////////////
+
+type contextKey struct{}
+
+// NewContext returns a context carrying the recorder, for the runner to hand to
+// a part
+func NewContext(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, contextKey{}, r)
+}
+
+// FromContext returns the recorder of the part, nil when it is not recorded
+func FromContext(ctx context.Context) *Recorder {
+	r, _ := ctx.Value(contextKey{}).(*Recorder)
+	return r
+}
@@ -0,0 +1,232 @@
+package record
+
+import (
+	"bytes"
+	"context"
+	"image/color"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"2ajoyce/adventofcode/lib/grid"
+	"2ajoyce/adventofcode/lib/simulation"
+)
+
+var (
+	red   = color.RGBA{R: 255, A: 255}
+	green = color.RGBA{G: 255, A: 255}
+)
+
+var testPalette = Palette{
+	Types: map[string]color.Color{"wall": red, "robot": green},
+	Runes: map[rune]color.Color{'#': red, '@': green},
+}
+
+var testLegend = simulation.MustNewLegend('.', map[rune]string{'#': "wall", '@': "robot", '?': "ghost"})
+
+// pixel returns the color of a cell of a frame
+func pixel(r *Recorder, frame, x, y int) color.Color {
+	f := r.frames[frame]
+	return f.Palette[f.ColorIndexAt(x, y)]
+}
+
+func TestSimulationFrame(t *testing.T) {
+	sim, err := testLegend.Parse([]string{"#.@", ".?."})
+	if err != nil {
+		t.Fatalf("Failed to parse map: %v", err)
+	}
+	r := New()
+	r.Simulation(sim, &testPalette)
+	if r.Len() != 1 {
+		t.Fatalf("Expected 1 frame, got %d", r.Len())
+	}
+
+	tests := []struct {
+		name string
+		x, y int
+		want color.Color
+	}{
+		{name: "Wall", x: 0, y: 0, want: red},
+		{name: "Robot", x: 2, y: 0, want: green},
+		{name: "Empty", x: 1, y: 0, want: color.Black},
+		{name: "Type without a color", x: 1, y: 1, want: color.White},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := pixel(r, 0, test.x, test.y); got != test.want {
+				t.Errorf("Expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestTerrainFrame(t *testing.T) {
+	legend := simulation.MustNewLegend('.', map[rune]string{'@': "robot"}, simulation.WithTerrainRune('#', 1, true), simulation.WithTerrainRune('~', 2, false))
+	sim, err := legend.Parse([]string{"#@~"})
+	if err != nil {
+		t.Fatalf("Failed to parse map: %v", err)
+	}
+	r := New()
+	r.Simulation(sim, &Palette{Types: map[string]color.Color{"robot": green}, Terrain: map[byte]color.Color{1: red}})
+
+	tests := []struct {
+		name string
+		x    int
+		want color.Color
+	}{
+		{name: "Terrain", x: 0, want: red},
+		{name: "Entity", x: 1, want: green},
+		{name: "Terrain without a color", x: 2, want: color.Black},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := pixel(r, 0, test.x, 0); got != test.want {
+				t.Errorf("Expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestGridFrame(t *testing.T) {
+	g, err := grid.ParseRunes([]string{"#.", ".@"})
+	if err != nil {
+		t.Fatalf("Failed to parse grid: %v", err)
+	}
+	r := New()
+	r.Grid(g, &testPalette)
+	if got := pixel(r, 0, 0, 0); got != red {
+		t.Errorf("Expected the wall red, got %v", got)
+	}
+	if got := pixel(r, 0, 1, 1); got != green {
+		t.Errorf("Expected the robot green, got %v", got)
+	}
+	if got := pixel(r, 0, 1, 0); got != color.White {
+		t.Errorf("Expected a rune without a color white, got %v", got)
+	}
+}
+
+func TestFrameSkip(t *testing.T) {
+	tests := []struct {
+		name    string
+		skip    int
+		offered int
+		want    int
+	}{
+		{name: "Every frame", skip: 1, offered: 5, want: 5},
+		{name: "Every other frame", skip: 2, offered: 5, want: 3},
+		{name: "Fewer frames than the skip", skip: 10, offered: 5, want: 1},
+		{name: "Ignored when not positive", skip: 0, offered: 5, want: 5},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := New(WithFrameSkip(test.skip))
+			for i := 0; i < test.offered; i++ {
+				r.Cells(2, 2, &testPalette, func(x, y int) rune { return '#' })
+			}
+			if r.Len() != test.want {
+				t.Errorf("Expected %d frames, got %d", test.want, r.Len())
+			}
+		})
+	}
+}
+
+func TestNilRecorder(t *testing.T) {
+	var r *Recorder
+	r.Cells(2, 2, &testPalette, func(x, y int) rune { return '#' })
+	if r.Len() != 0 {
+		t.Errorf("Expected a nil recorder to keep no frames")
+	}
+	if FromContext(context.Background()) != nil {
+		t.Errorf("Expected no recorder in an empty context")
+	}
+	if err := r.WriteGIF(&bytes.Buffer{}); err == nil {
+		t.Errorf("Expected an error writing a nil recorder")
+	}
+}
+
+func TestWriteGIF(t *testing.T) {
+	r := New(WithScale(3))
+	r.Cells(2, 1, &testPalette, func(x, y int) rune { return '#' })
+	r.Cells(4, 2, &testPalette, func(x, y int) rune { return '@' })
+
+	var buf bytes.Buffer
+	if err := r.WriteGIF(&buf); err != nil {
+		t.Fatalf("Failed to write GIF: %v", err)
+	}
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatalf("Failed to decode GIF: %v", err)
+	}
+	if len(anim.Image) != 2 {
+		t.Errorf("Expected 2 frames, got %d", len(anim.Image))
+	}
+	if anim.Config.Width != 12 || anim.Config.Height != 6 {
+		t.Errorf("Expected the GIF to fit the largest scaled frame, got %dx%d", anim.Config.Width, anim.Config.Height)
+	}
+	if anim.Delay[0] != 10 {
+		t.Errorf("Expected a delay of 10, got %d", anim.Delay[0])
+	}
+}
+
+func TestSave(t *testing.T) {
+	dir := t.TempDir()
+	r := New()
+	r.Cells(2, 2, &testPalette, func(x, y int) rune { return '#' })
+	r.Cells(2, 2, &testPalette, func(x, y int) rune { return '@' })
+
+	tests := []struct {
+		name    string
+		path    string
+		want    []string
+		wantErr bool
+	}{
+		{name: "GIF", path: "out.gif", want: []string{"out.gif"}},
+		{name: "PNGs", path: "out.png", want: []string{"out-0000.png", "out-0001.png"}},
+		{name: "Unknown format", path: "out.txt", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			written, err := r.Save(filepath.Join(dir, test.path))
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to save: %v", err)
+			}
+			if len(written) != len(test.want) {
+				t.Fatalf("Expected %v, got %v", test.want, written)
+			}
+			for i, want := range test.want {
+				if written[i] != filepath.Join(dir, want) {
+					t.Errorf("Expected %s, got %s", want, written[i])
+				}
+				if _, err := os.Stat(written[i]); err != nil {
+					t.Errorf("Expected %s to exist: %v", written[i], err)
+				}
+			}
+		})
+	}
+}
+
+func TestPaletteTooLarge(t *testing.T) {
+	p := &Palette{Runes: map[rune]color.Color{}}
+	for i := 0; i < 255; i++ {
+		p.Runes[rune(i)] = red
+	}
+	r := New()
+	r.Cells(1, 1, p, func(x, y int) rune { return 0 })
+	if r.Len() != 0 || r.Err() == nil {
+		t.Errorf("Expected no frames and an error, got %d frames (%v)", r.Len(), r.Err())
+	}
+	if _, err := r.Save(filepath.Join(t.TempDir(), "out.gif")); err == nil {
+		t.Errorf("Expected an error for a palette of more than 256 colors")
+	}
+}
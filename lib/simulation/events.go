@@ -0,0 +1,180 @@
+package simulation
+
+import (
+	"fmt"
+	"slices"
+)
+
+/////////////////////////////////////////////////////////////////////////////////////
+// EVENTS
+/////////////////////////////////////////////////////////////////////////////////////
+
+// EventKind is what happened to an entity
+type EventKind int
+
+const (
+	Spawned   EventKind = iota // Added by AddEntity
+	Despawned                  // Removed by RemoveEntity
+	Moved                      // Moved from one set of cells to another
+	Blocked                    // Tried to move and was stopped by an entity or the edge of the map
+	Wrapped                    // Moved off the edge of the map or through a portal and came out elsewhere, after its Moved event
+	Turned                     // Given a new direction by SetEntityDirection, or by the topology on a move
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case Spawned:
+		return "Spawned"
+	case Despawned:
+		return "Despawned"
+	case Moved:
+		return "Moved"
+	case Blocked:
+		return "Blocked"
+	case Wrapped:
+		return "Wrapped"
+	case Turned:
+		return "Turned"
+	default:
+		return fmt.Sprintf("EventKind(%d)", int(k))
+	}
+}
+
+// MarshalText writes the kind by name, so logs of events stay readable
+func (k EventKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText reads a kind written by MarshalText
+func (k *EventKind) UnmarshalText(text []byte) error {
+	for kind := Spawned; kind <= Turned; kind++ {
+		if kind.String() == string(text) {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown event kind %q", text)
+}
+
+// Event describes a single change to the simulation. Which fields are set
+// depends on the kind:
+//
+//	Spawned    To, Direction
+//	Despawned  From, Direction
+//	Moved      From, To, Direction of the move
+//	Blocked    From, the To it was headed for, Direction and the Blocker
+//	Wrapped    From, To, Direction of the move
+//	Turned     From, Direction it now faces and the Previous one
+type Event struct {
+	Kind       EventKind `json:"kind"`
+	EntityId   EntityId  `json:"id"`
+	EntityType string    `json:"type"`
+	From       []Coord   `json:"from,omitempty"`
+	To         []Coord   `json:"to,omitempty"`
+	Direction  Direction `json:"direction"`
+	Previous   Direction `json:"previous"`
+	Blocker    EntityId  `json:"blocker,omitempty"` // NilEntityId when the edge of the map is in the way
+}
+
+// Observer is called with every event it subscribed to, after the change has
+// been made and the simulation unlocked, so it may look at or change the
+// simulation itself. Changes it makes are observed in turn, once every
+// observer has seen the current event.
+type Observer func(e Event)
+
+type subscription struct {
+	observer Observer
+	kinds    []EventKind // Every kind when empty
+}
+
+// Subscribe calls the observer with every event of the given kinds, or with
+// every event when no kinds are given, and returns a function that ends the
+// subscription. Observers are called in the order they subscribed. Clones start
+// without any observers.
+func (s *simulation) Subscribe(observer Observer, kinds ...EventKind) (unsubscribe func()) {
+	s.updateMutex.Lock()
+	defer s.updateMutex.Unlock()
+
+	sub := &subscription{observer: observer, kinds: slices.Clone(kinds)}
+	s.subscriptions = append(s.subscriptions, sub)
+	return func() {
+		s.updateMutex.Lock()
+		defer s.updateMutex.Unlock()
+		s.subscriptions = slices.DeleteFunc(s.subscriptions, func(other *subscription) bool { return other == sub })
+	}
+}
+
+// observed returns whether any observer is subscribed, so the changes can skip
+// building events nobody will see. The caller holds the update lock.
+func (s *simulation) observed() bool {
+	return len(s.subscriptions) > 0
+}
+
+// emit queues an event to be sent once the change is done. The caller holds
+// the update lock and has checked observed.
+func (s *simulation) emit(e Event) {
+	s.pending = append(s.pending, e)
+}
+
+// dispatch sends the queued events to the observers. Every change defers it
+// before taking the update lock, so it runs after the lock is released. Events
+// caused by an observer are queued and sent once every observer has seen the
+// event that caused them, so observers see events in the order they happened.
+func (s *simulation) dispatch() {
+	s.updateMutex.Lock()
+	defer s.updateMutex.Unlock()
+	if s.dispatching {
+		return
+	}
+	s.dispatching = true
+	for len(s.pending) > 0 {
+		events := s.pending
+		s.pending = nil
+		subscriptions := slices.Clone(s.subscriptions)
+
+		s.updateMutex.Unlock()
+		for _, e := range events {
+			for _, sub := range subscriptions {
+				if len(sub.kinds) == 0 || slices.Contains(sub.kinds, e.Kind) {
+					sub.observer(e)
+				}
+			}
+		}
+		s.updateMutex.Lock()
+	}
+	s.dispatching = false
+}
+
+// emitMoves queues a Moved event for each entity moved from its old to its new
+// coordinates in a direction. The caller holds the update lock.
+func (s *simulation) emitMoves(slots []int, oldCoords, newCoords [][]Coord, direction Direction) {
+	for i, slot := range slots {
+		s.emit(Event{
+			Kind:       Moved,
+			EntityId:   newEntityId(slot, s.generations[slot]),
+			EntityType: s.types[slot],
+			From:       oldCoords[i],
+			To:         newCoords[i],
+			Direction:  direction,
+		})
+	}
+}
+
+// emitBlocked queues a Blocked event for an entity that could not move in a
+// direction. The caller holds the update lock.
+func (s *simulation) emitBlocked(slot int, direction Direction, blocker EntityId) {
+	from := s.positions[slot]
+	to := make([]Coord, len(from))
+	for i, coord := range from {
+		to[i] = coord.Move(direction)
+	}
+	s.emit(Event{
+		Kind:       Blocked,
+		EntityId:   newEntityId(slot, s.generations[slot]),
+		EntityType: s.types[slot],
+		From:       from,
+		To:         to,
+		Direction:  direction,
+		Blocker:    blocker,
+	})
+}
@@ -0,0 +1,179 @@
+package simulation
+
+import (
+	"slices"
+	"testing"
+)
+
+// recordEvents subscribes to the simulation and returns the events it has seen so far
+func recordEvents(sim Simulation, kinds ...EventKind) *[]Event {
+	events := &[]Event{}
+	sim.Subscribe(func(e Event) { *events = append(*events, e) }, kinds...)
+	return events
+}
+
+func kinds(events []Event) []EventKind {
+	got := make([]EventKind, len(events))
+	for i, e := range events {
+		got[i] = e.Kind
+	}
+	return got
+}
+
+func TestEvents(t *testing.T) {
+	forEachBackend(t, testEvents)
+}
+
+func testEvents(t *testing.T, b backend) {
+	sim := parseSchedulerSim(t, b)
+	events := recordEvents(sim)
+	robots := sim.GetEntitiesOfType("robot")
+
+	tests := []struct {
+		name   string
+		change func() error
+		want   Event
+	}{
+		{name: "Move", change: func() error { return sim.MoveEntity(robots[0].GetId(), false) }, want: Event{
+			Kind: Moved, EntityId: robots[0].GetId(), EntityType: "robot", From: []Coord{{X: 0, Y: 1}}, To: []Coord{{X: 1, Y: 1}}, Direction: East,
+		}},
+		{name: "Turn", change: func() error { return sim.SetEntityDirection(robots[1].GetId(), South) }, want: Event{
+			Kind: Turned, EntityId: robots[1].GetId(), EntityType: "robot", From: []Coord{{X: 3, Y: 1}}, Direction: South,
+		}},
+		{name: "Despawn", change: func() error { return sim.RemoveEntity(robots[0].GetId()) }, want: Event{
+			Kind: Despawned, EntityId: robots[0].GetId(), EntityType: "robot", From: []Coord{{X: 1, Y: 1}}, Direction: East,
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			*events = nil
+			if err := test.change(); err != nil {
+				t.Fatalf("Failed to change the simulation: %v", err)
+			}
+			if len(*events) != 1 {
+				t.Fatalf("Expected a single event, got %v", *events)
+			}
+			got := (*events)[0]
+			if got.Kind != test.want.Kind || got.EntityId != test.want.EntityId || got.EntityType != test.want.EntityType ||
+				!slices.Equal(got.From, test.want.From) || !slices.Equal(got.To, test.want.To) || got.Direction != test.want.Direction {
+				t.Errorf("Expected %+v, got %+v", test.want, got)
+			}
+		})
+	}
+
+	// Spawning gives the event the new id
+	*events = nil
+	e, _ := NewEntity("robot")
+	added, err := sim.AddEntity(e, []Coord{{X: 0, Y: 0}}, North)
+	if err != nil {
+		t.Fatalf("Failed to add entity: %v", err)
+	}
+	if len(*events) != 1 || (*events)[0].Kind != Spawned || (*events)[0].EntityId != added.GetId() {
+		t.Errorf("Expected a Spawned event for %s, got %v", added.GetId(), *events)
+	}
+}
+
+func TestEventsWrapAndBlock(t *testing.T) {
+	forEachBackend(t, testEventsWrapAndBlock)
+}
+
+func testEventsWrapAndBlock(t *testing.T, b backend) {
+	sim := parseSchedulerSim(t, b)
+	events := recordEvents(sim, Wrapped, Blocked)
+	right := sim.GetEntitiesOfType("robot")[1]
+
+	// Without wrapping the edge of the map blocks the move
+	if err := sim.MoveEntity(right.GetId(), false); err == nil {
+		t.Fatalf("Expected moving off the map to fail")
+	}
+	if len(*events) != 1 || (*events)[0].Kind != Blocked || (*events)[0].Blocker != NilEntityId {
+		t.Errorf("Expected a Blocked event with no blocker, got %v", *events)
+	}
+
+	*events = nil
+	if err := sim.MoveEntity(right.GetId(), true); err != nil {
+		t.Fatalf("Failed to move: %v", err)
+	}
+	if len(*events) != 1 || (*events)[0].Kind != Wrapped || !slices.Equal((*events)[0].To, []Coord{{X: 0, Y: 1}}) {
+		t.Errorf("Expected a Wrapped event onto (0, 1), got %v", *events)
+	}
+
+	// Pushing into the wall names the wall
+	*events = nil
+	wall := sim.GetEntitiesOfType("wall")[0]
+	robot := sim.GetEntitiesOfType("robot")[0]
+	for i := 0; i < 2; i++ {
+		if err := sim.MoveEntities([]EntityId{robot.GetId()}, East); err != nil {
+			t.Fatalf("Failed to move: %v", err)
+		}
+	}
+	moved, err := sim.Push(robot.GetId(), South, PushRules{"wall": Wall})
+	if err != nil || moved {
+		t.Fatalf("Expected the push to be blocked, got %v (%v)", moved, err)
+	}
+	if len(*events) != 1 || (*events)[0].Blocker != wall.GetId() || !slices.Equal((*events)[0].To, []Coord{{X: 2, Y: 2}}) {
+		t.Errorf("Expected a Blocked event naming the wall, got %v", *events)
+	}
+}
+
+func TestObservers(t *testing.T) {
+	sim := parseSchedulerSim(t, backends[0])
+	robots := sim.GetEntitiesOfType("robot")
+
+	// A heatmap of the cells the robots move onto
+	visits := make(map[Coord]int)
+	unsubscribe := sim.Subscribe(func(e Event) {
+		for _, coord := range e.To {
+			visits[coord]++
+		}
+	}, Moved)
+
+	// An observer may change the simulation, here stopping robots that wrap
+	sim.Subscribe(func(e Event) {
+		if err := sim.SetEntityDirection(e.EntityId, Direction{}); err != nil {
+			t.Errorf("Failed to stop robot: %v", err)
+		}
+	}, Wrapped)
+	events := recordEvents(sim)
+
+	for i := 0; i < 3; i++ {
+		for _, robot := range robots {
+			if err := sim.MoveEntity(robot.GetId(), true); err != nil {
+				t.Fatalf("Failed to move: %v", err)
+			}
+		}
+	}
+	// The left robot moves 3 times, the right one wraps onto (0, 1) and then moves
+	// in place
+	want := map[Coord]int{{X: 1, Y: 1}: 1, {X: 2, Y: 1}: 1, {X: 3, Y: 1}: 1, {X: 0, Y: 1}: 3}
+	if len(visits) != len(want) {
+		t.Errorf("Expected visits %v, got %v", want, visits)
+	}
+	for coord, n := range want {
+		if visits[coord] != n {
+			t.Errorf("Expected %d visits to %s, got %d", n, coord.String(), visits[coord])
+		}
+	}
+	if got := kinds((*events)[:4]); !slices.Equal(got, []EventKind{Moved, Moved, Wrapped, Turned}) {
+		t.Errorf("Expected the turn to follow the wrap, got %v", got)
+	}
+
+	unsubscribe()
+	if err := sim.MoveEntity(robots[0].GetId(), true); err != nil {
+		t.Fatalf("Failed to move: %v", err)
+	}
+	if visits[Coord{X: 0, Y: 1}] != 3 {
+		t.Errorf("Expected no visits after unsubscribing")
+	}
+
+	// Clones start without observers
+	*events = nil
+	clone := sim.Clone()
+	if err := clone.MoveEntity(robots[0].GetId(), true); err != nil {
+		t.Fatalf("Failed to move clone: %v", err)
+	}
+	if len(*events) != 0 {
+		t.Errorf("Expected no events from the clone, got %v", *events)
+	}
+}
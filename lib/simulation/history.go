@@ -0,0 +1,294 @@
+package simulation
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+)
+
+/////////////////////////////////////////////////////////////////////////////////////
+// COMMANDS
+/////////////////////////////////////////////////////////////////////////////////////
+
+// Command is a single change to a simulation, recorded with the tick it was
+// made in. It is written to the log as a line of JSON.
+type Command struct {
+	Tick int `json:"tick"`
+	Event
+}
+
+// The kinds of event that change the simulation, Blocked and Wrapped only
+// describe a change
+var commandKinds = []EventKind{Spawned, Despawned, Moved, Turned}
+
+// ReadCommands reads a log of commands written by History.WriteTo
+func ReadCommands(r io.Reader) ([]Command, error) {
+	var commands []Command
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var command Command
+		if err := json.Unmarshal(scanner.Bytes(), &command); err != nil {
+			return nil, fmt.Errorf("error reading command on line %d: %v", line, err)
+		}
+		commands = append(commands, command)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading commands: %v", err)
+	}
+	return commands, nil
+}
+
+// Replay makes the commands on a simulation in order. Starting from the state
+// the log was recorded from, it rebuilds the state the log ended in, entity ids
+// included. Replaying makes no events.
+func Replay(sim Simulation, commands []Command) error {
+	s, err := asSimulation(sim)
+	if err != nil {
+		return err
+	}
+	for i, command := range commands {
+		if err := s.redo(command); err != nil {
+			return fmt.Errorf("error replaying command %d (%s of entity %s during tick %d): %v", i, command.Kind, command.EntityId, command.Tick, err)
+		}
+	}
+	return nil
+}
+
+// asSimulation returns the simulation behind the interface, the history works
+// on its slots directly
+func asSimulation(sim Simulation) (*simulation, error) {
+	s, ok := sim.(*simulation)
+	if !ok {
+		return nil, fmt.Errorf("history needs a simulation created by NewSimulation, got %T", sim)
+	}
+	return s, nil
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// HISTORY
+/////////////////////////////////////////////////////////////////////////////////////
+
+// History records every change to a simulation as a command, so changes can be
+// undone and redone a tick at a time, and written out as a log to Replay later.
+// It observes the simulation, so the code making the changes does not need to
+// know about it. UUIDs are not recorded, so an entity brought back by Undo or
+// Replay has none. A History is not safe for concurrent use.
+type History struct {
+	sim         *simulation
+	unsubscribe func()
+	tick        int
+	done        []Command // Changes made, oldest first
+	undone      []Command // Changes undone, the next to redo last
+}
+
+// NewHistory starts recording the changes to a simulation. Changes are
+// recorded as tick 0 until SetTick is called.
+func NewHistory(sim Simulation) (*History, error) {
+	s, err := asSimulation(sim)
+	if err != nil {
+		return nil, err
+	}
+	h := &History{sim: s}
+	h.unsubscribe = sim.Subscribe(h.record, commandKinds...)
+	return h, nil
+}
+
+// record adds a change to the history. A new change can not be redone over, so
+// anything undone is forgotten.
+func (h *History) record(e Event) {
+	h.done = append(h.done, Command{Tick: h.tick, Event: e})
+	h.undone = nil
+}
+
+// SetTick sets the tick the changes that follow are recorded with. A scheduler
+// can call it from its first system.
+func (h *History) SetTick(tick int) {
+	h.tick = tick
+}
+
+// Close stops recording, what has been recorded can still be undone
+func (h *History) Close() {
+	h.unsubscribe()
+}
+
+// Commands returns the changes made and not undone, oldest first
+func (h *History) Commands() []Command {
+	return slices.Clone(h.done)
+}
+
+// Undo takes back the changes of the last n ticks that changed anything, newest
+// first, and returns the number of ticks undone. Undoing makes no events.
+func (h *History) Undo(n int) (int, error) {
+	for i := 0; i < n; i++ {
+		if len(h.done) == 0 {
+			return i, nil
+		}
+		tick := h.done[len(h.done)-1].Tick
+		for len(h.done) > 0 && h.done[len(h.done)-1].Tick == tick {
+			command := h.done[len(h.done)-1]
+			if err := h.sim.undo(command); err != nil {
+				return i, fmt.Errorf("error undoing %s of entity %s during tick %d: %v", command.Kind, command.EntityId, tick, err)
+			}
+			h.done = h.done[:len(h.done)-1]
+			h.undone = append(h.undone, command)
+		}
+	}
+	return n, nil
+}
+
+// Redo makes the changes of the next n undone ticks again and returns the
+// number of ticks redone. Redoing makes no events.
+func (h *History) Redo(n int) (int, error) {
+	for i := 0; i < n; i++ {
+		if len(h.undone) == 0 {
+			return i, nil
+		}
+		tick := h.undone[len(h.undone)-1].Tick
+		for len(h.undone) > 0 && h.undone[len(h.undone)-1].Tick == tick {
+			command := h.undone[len(h.undone)-1]
+			if err := h.sim.redo(command); err != nil {
+				return i, fmt.Errorf("error redoing %s of entity %s during tick %d: %v", command.Kind, command.EntityId, tick, err)
+			}
+			h.undone = h.undone[:len(h.undone)-1]
+			h.done = append(h.done, command)
+		}
+	}
+	return n, nil
+}
+
+// WriteTo writes the changes made and not undone as JSON lines, oldest first
+func (h *History) WriteTo(w io.Writer) (int64, error) {
+	var written int64
+	for _, command := range h.done {
+		line, err := json.Marshal(command)
+		if err != nil {
+			return written, fmt.Errorf("error writing command: %v", err)
+		}
+		n, err := w.Write(append(line, '\n'))
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// APPLYING COMMANDS
+/////////////////////////////////////////////////////////////////////////////////////
+
+// redo makes the change a command recorded
+func (s *simulation) redo(command Command) error {
+	s.updateMutex.Lock()
+	defer s.updateMutex.Unlock()
+
+	switch command.Kind {
+	case Spawned:
+		return s.restore(command.EntityId, command.EntityType, command.To, command.Direction)
+	case Despawned:
+		return s.discard(command.EntityId, command.EntityId.generation()+1)
+	case Moved:
+		return s.place(command.EntityId, command.To)
+	case Turned:
+		return s.turn(command.EntityId, command.Direction)
+	}
+	return fmt.Errorf("can not apply a %s event", command.Kind)
+}
+
+// undo takes back the change a command recorded
+func (s *simulation) undo(command Command) error {
+	s.updateMutex.Lock()
+	defer s.updateMutex.Unlock()
+
+	switch command.Kind {
+	case Spawned:
+		// Keep the generation, so the next entity added gets the same id again
+		return s.discard(command.EntityId, command.EntityId.generation())
+	case Despawned:
+		return s.restore(command.EntityId, command.EntityType, command.From, command.Direction)
+	case Moved:
+		return s.place(command.EntityId, command.From)
+	case Turned:
+		return s.turn(command.EntityId, command.Previous)
+	}
+	return fmt.Errorf("can not undo a %s event", command.Kind)
+}
+
+// restore puts an entity back in the slot its id points to. The slot must be
+// free or the next one to be added. The caller holds the update lock.
+func (s *simulation) restore(entityId EntityId, entityType string, coords []Coord, direction Direction) error {
+	slot := entityId.slot()
+	if entityId == NilEntityId || slot > len(s.live) || (slot < len(s.live) && s.live[slot]) {
+		return fmt.Errorf("slot of entity %s is not free", entityId)
+	}
+	if err := s.spatialMap.addEntity(entityId, coords...); err != nil {
+		return fmt.Errorf("error adding entity to spatial map: %v", err)
+	}
+	if slot == len(s.live) {
+		s.generations = append(s.generations, 0)
+		s.live = append(s.live, false)
+		s.types = append(s.types, "")
+		s.positions = append(s.positions, nil)
+		s.directions = append(s.directions, Direction{})
+	} else {
+		s.free = slices.DeleteFunc(s.free, func(free int) bool { return free == slot })
+	}
+	s.generations[slot] = entityId.generation()
+	s.live[slot] = true
+	s.types[slot] = entityType
+	s.positions[slot] = slices.Clone(coords)
+	s.directions[slot] = direction
+	s.indexType(entityType, slot)
+	return nil
+}
+
+// discard takes an entity off the map and frees its slot with the generation
+// given. The caller holds the update lock.
+func (s *simulation) discard(entityId EntityId, generation uint32) error {
+	slot, err := s.slotOf(entityId)
+	if err != nil {
+		return err
+	}
+	if err := s.spatialMap.removeEntity(entityId, s.positions[slot]...); err != nil {
+		return fmt.Errorf("error removing entity from spatial map: %v", err)
+	}
+	s.freeSlot(slot, generation)
+	return nil
+}
+
+// place moves an entity straight to the coordinates. The caller holds the
+// update lock.
+func (s *simulation) place(entityId EntityId, coords []Coord) error {
+	slot, err := s.slotOf(entityId)
+	if err != nil {
+		return err
+	}
+	if err := s.spatialMap.removeEntity(entityId, s.positions[slot]...); err != nil {
+		return fmt.Errorf("error removing entity from spatial map: %v", err)
+	}
+	if err := s.spatialMap.addEntity(entityId, coords...); err != nil {
+		rollbackErr := s.spatialMap.addEntity(entityId, s.positions[slot]...)
+		if rollbackErr != nil {
+			return fmt.Errorf("error adding entity to spatial map: %v, and failed to roll back: %v", err, rollbackErr)
+		}
+		return fmt.Errorf("error adding entity to spatial map: %v", err)
+	}
+	s.positions[slot] = slices.Clone(coords)
+	return nil
+}
+
+// turn sets the direction of an entity. The caller holds the update lock.
+func (s *simulation) turn(entityId EntityId, direction Direction) error {
+	slot, err := s.slotOf(entityId)
+	if err != nil {
+		return err
+	}
+	s.directions[slot] = direction
+	return nil
+}
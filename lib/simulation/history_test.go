@@ -0,0 +1,192 @@
+package simulation
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// recordHistory runs the robots of the scheduler map for some ticks with a
+// history, spawning, turning and removing robots along the way, and returns the
+// state hash before the first tick and after each of them
+func recordHistory(t *testing.T, sim Simulation, ticks int) (*History, []uint64) {
+	t.Helper()
+	history, err := NewHistory(sim)
+	if err != nil {
+		t.Fatalf("Failed to create history: %v", err)
+	}
+	hashes := []uint64{sim.StateHash()}
+	s := NewScheduler(sim,
+		System[Simulation]{Name: "history", Run: func(sim Simulation, tick int) error {
+			history.SetTick(tick)
+			return nil
+		}},
+		Movement(true, "robot"),
+		System[Simulation]{Name: "changes", Run: func(sim Simulation, tick int) error {
+			robots := sim.GetEntitiesOfType("robot")
+			switch tick {
+			case 2:
+				return sim.SetEntityDirection(robots[0].GetId(), South)
+			case 3:
+				return sim.RemoveEntity(robots[1].GetId())
+			}
+			return nil
+		}},
+		Spawn("robot", Coord{X: 0, Y: 0}, East),
+		System[Simulation]{Name: "hash", Run: func(sim Simulation, tick int) error {
+			hashes = append(hashes, sim.StateHash())
+			return nil
+		}},
+	)
+	if err := s.RunN(context.Background(), ticks); err != nil {
+		t.Fatalf("Failed to run: %v", err)
+	}
+	return history, hashes
+}
+
+func ids(entities []Entity) []EntityId {
+	got := make([]EntityId, len(entities))
+	for i, e := range entities {
+		got[i] = e.GetId()
+	}
+	return got
+}
+
+func TestHistoryUndoRedo(t *testing.T) {
+	forEachBackend(t, testHistoryUndoRedo)
+}
+
+func testHistoryUndoRedo(t *testing.T, b backend) {
+	sim := parseSchedulerSim(t, b)
+	history, hashes := recordHistory(t, sim, 4)
+	final := ids(sim.GetEntities())
+
+	tests := []struct {
+		name   string
+		undo   int
+		redo   int
+		want   int // Ticks undone or redone
+		atTick int
+	}{
+		{name: "Undo a tick", undo: 1, want: 1, atTick: 3},
+		{name: "Undo past a removal and a turn", undo: 2, want: 2, atTick: 1},
+		{name: "Undo more than there is", undo: 5, want: 1, atTick: 0},
+		{name: "Redo a tick", redo: 1, want: 1, atTick: 1},
+		{name: "Redo more than there is", redo: 5, want: 3, atTick: 4},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got int
+			var err error
+			if test.undo > 0 {
+				got, err = history.Undo(test.undo)
+			} else {
+				got, err = history.Redo(test.redo)
+			}
+			if err != nil {
+				t.Fatalf("Failed: %v", err)
+			}
+			if got != test.want {
+				t.Errorf("Expected %d ticks, got %d", test.want, got)
+			}
+			if sim.StateHash() != hashes[test.atTick] {
+				t.Errorf("Expected the state after tick %d", test.atTick)
+			}
+		})
+	}
+
+	// Every entity is back under its own id
+	if got := ids(sim.GetEntities()); !slices.Equal(got, final) {
+		t.Errorf("Expected ids %v, got %v", final, got)
+	}
+
+	// A new change after an undo can not be redone over
+	if _, err := history.Undo(1); err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+	if err := sim.SetEntityDirection(final[0], North); err != nil {
+		t.Fatalf("Failed to turn: %v", err)
+	}
+	if n, err := history.Redo(1); n != 0 || err != nil {
+		t.Errorf("Expected nothing to redo, got %d (%v)", n, err)
+	}
+}
+
+func TestHistoryUndoSpawn(t *testing.T) {
+	sim := parseSchedulerSim(t, backends[0])
+	history, err := NewHistory(sim)
+	if err != nil {
+		t.Fatalf("Failed to create history: %v", err)
+	}
+
+	// Undoing a spawn hands its id out again to the next entity added
+	e, _ := NewEntity("robot")
+	spawned, err := sim.AddEntity(e, []Coord{{X: 1, Y: 0}}, North)
+	if err != nil {
+		t.Fatalf("Failed to add entity: %v", err)
+	}
+	id := spawned.GetId()
+	if _, err := history.Undo(1); err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+	if _, err := sim.GetEntity(id); err == nil {
+		t.Errorf("Expected the spawned entity to be gone")
+	}
+	if cell, _ := sim.GetMap().GetCell(Coord{X: 1, Y: 0}); !cell.IsEmpty() {
+		t.Errorf("Expected the cell to be empty again")
+	}
+	e, _ = NewEntity("robot")
+	again, err := sim.AddEntity(e, []Coord{{X: 2, Y: 0}}, North)
+	if err != nil {
+		t.Fatalf("Failed to add entity: %v", err)
+	}
+	if again.GetId() != id {
+		t.Errorf("Expected id %s again, got %s", id, again.GetId())
+	}
+}
+
+func TestHistoryReplay(t *testing.T) {
+	forEachBackend(t, testHistoryReplay)
+}
+
+func testHistoryReplay(t *testing.T, b backend) {
+	sim := parseSchedulerSim(t, b)
+	history, hashes := recordHistory(t, sim, 4)
+
+	var log bytes.Buffer
+	if _, err := history.WriteTo(&log); err != nil {
+		t.Fatalf("Failed to write log: %v", err)
+	}
+	if lines := strings.Count(log.String(), "\n"); lines != len(history.Commands()) {
+		t.Errorf("Expected a line per command, got %d lines for %d commands", lines, len(history.Commands()))
+	}
+	if !strings.Contains(log.String(), `"kind":"Turned"`) {
+		t.Errorf("Expected kinds written by name, got %s", log.String())
+	}
+
+	commands, err := ReadCommands(&log)
+	if err != nil {
+		t.Fatalf("Failed to read log: %v", err)
+	}
+	replayed := parseSchedulerSim(t, b)
+	if err := Replay(replayed, commands); err != nil {
+		t.Fatalf("Failed to replay: %v", err)
+	}
+	if replayed.StateHash() != hashes[len(hashes)-1] {
+		t.Errorf("Expected the replay to end in the same state")
+	}
+	if got, want := ids(replayed.GetEntities()), ids(sim.GetEntities()); !slices.Equal(got, want) {
+		t.Errorf("Expected ids %v, got %v", want, got)
+	}
+
+	// Replaying onto a state the log was not recorded from fails
+	if err := Replay(replayed, commands); err == nil {
+		t.Errorf("Expected replaying twice to fail")
+	}
+	if _, err := ReadCommands(strings.NewReader("{\"kind\":\"Exploded\"}\n")); err == nil {
+		t.Errorf("Expected an unknown kind to fail")
+	}
+}
@@ -7,7 +7,13 @@ import (
 )
 
 func CostManhattan(prior Coord, current Coord, next Coord) float64 {
-	return math.Abs(float64(current.X-next.X)) + math.Abs(float64(current.Y-next.Y))
+	return float64(current.Vec().Manhattan(next.Vec()))
+}
+
+// CostStep costs the same for every step. Unlike CostManhattan it holds for
+// steps a Topology sends around the edge of the map or through a portal.
+func CostStep(prior Coord, current Coord, next Coord) float64 {
+	return 1
 }
 
 // PathStep represents a step in a path, including the node and the cost to reach that node.
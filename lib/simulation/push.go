@@ -0,0 +1,204 @@
+package simulation
+
+import (
+	"errors"
+	"fmt"
+)
+
+/////////////////////////////////////////////////////////////////////////////////////
+// PUSH RULES
+/////////////////////////////////////////////////////////////////////////////////////
+
+// PushRule is how an entity reacts when another entity is pushed into it
+type PushRule int
+
+const (
+	Passable PushRule = iota // Stays where it is and lets the pushed entity onto its cell
+	Pushable                 // Moves along, pushing whatever is in its way in turn
+	Wall                     // Stays where it is and stops the whole push
+)
+
+// PushRules maps entity types to how they react to a push. Types that are not
+// listed are Passable.
+type PushRules map[string]PushRule
+
+/////////////////////////////////////////////////////////////////////////////////////
+// ATOMIC MOVES
+/////////////////////////////////////////////////////////////////////////////////////
+
+// Push moves an entity one step in a direction, along with every entity it
+// pushes. Pushable entities in the way are pushed in turn, so a single push can
+// move a whole chain of multi-cell entities. Steps follow the topology of the
+// map, so a push can carry on through a portal or over the edge of a cube,
+// pushing what it meets there in the direction it comes out. If a Wall, blocked
+// terrain or a step the topology does not allow is anywhere in the way nothing
+// moves and Push returns false. Otherwise every entity in the chain moves one
+// step and Push returns true. Pushing never turns the entities.
+func (s *simulation) Push(entityId EntityId, direction Direction, rules PushRules) (bool, error) {
+	defer s.dispatch()
+	s.updateMutex.Lock()
+	defer s.updateMutex.Unlock()
+
+	chain, directions, blocker, blocked, err := s.resolvePush(entityId, direction, rules)
+	if err != nil {
+		return false, err
+	}
+	if blocked {
+		if s.observed() {
+			s.emitBlocked(entityId.slot(), direction, blocker)
+		}
+		return false, nil
+	}
+	if err := s.moveEntities(chain, directions); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// MoveEntities moves every entity one step in a direction, or none of them if
+// any step is not allowed by the topology of the map or ends on blocked
+// terrain. Unlike MoveEntity it ignores the direction the entities are facing,
+// and leaves it alone even where the topology would turn them.
+func (s *simulation) MoveEntities(entityIds []EntityId, direction Direction) error {
+	defer s.dispatch()
+	s.updateMutex.Lock()
+	defer s.updateMutex.Unlock()
+
+	directions := make([]Direction, len(entityIds))
+	for i, entityId := range entityIds {
+		if _, err := s.slotOf(entityId); err != nil {
+			return err
+		}
+		directions[i] = direction
+	}
+	return s.moveEntities(entityIds, directions)
+}
+
+// resolvePush works out which entities a push moves, starting with the pusher,
+// and the direction each of them moves in. blocked is true when a Wall, blocked
+// terrain or the topology stops the push, blocker is the Wall or NilEntityId
+// for the others.
+func (s *simulation) resolvePush(entityId EntityId, direction Direction, rules PushRules) (chain []EntityId, directions []Direction, blocker EntityId, blocked bool, err error) {
+	topology := s.spatialMap.GetTopology()
+	width, height := s.spatialMap.GetWidth(), s.spatialMap.GetHeight()
+	terrain := s.spatialMap.GetTerrain()
+	headings := map[EntityId]Direction{entityId: direction} // Entities in the chain -> direction they move
+	queue := []EntityId{entityId}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		heading := headings[current]
+		chain = append(chain, current)
+		directions = append(directions, heading)
+
+		slot, err := s.slotOf(current)
+		if err != nil {
+			return nil, nil, NilEntityId, false, err
+		}
+		for _, coord := range s.positions[slot] {
+			next, facing, ok := topology.Step(coord, heading, width, height)
+			if !ok || !s.spatialMap.ValidateCoord(next) || terrain.Blocked(next) {
+				return nil, nil, NilEntityId, true, nil
+			}
+			cell, err := s.spatialMap.GetCell(next)
+			if err != nil {
+				return nil, nil, NilEntityId, false, fmt.Errorf("error checking cell %s: %v", next.String(), err)
+			}
+			for _, otherId := range cell.GetEntityIds() {
+				if _, ok := headings[otherId]; ok {
+					continue // Already moving, including the other cells of the entity itself
+				}
+				otherSlot, err := s.slotOf(otherId)
+				if err != nil {
+					return nil, nil, NilEntityId, false, err
+				}
+				switch rules[s.types[otherSlot]] {
+				case Wall:
+					return nil, nil, otherId, true, nil
+				case Pushable:
+					headings[otherId] = facing // Pushed on the way the step came out
+					queue = append(queue, otherId)
+				}
+			}
+		}
+	}
+	return chain, directions, NilEntityId, false, nil
+}
+
+// moveEntities moves each entity one step in its direction, following the
+// topology of the map, as a single change: either all of them end up on their
+// new cells or the map is left as it was. The caller holds the update lock.
+func (s *simulation) moveEntities(entityIds []EntityId, directions []Direction) error {
+	topology := s.spatialMap.GetTopology()
+	width, height := s.spatialMap.GetWidth(), s.spatialMap.GetHeight()
+	terrain := s.spatialMap.GetTerrain()
+	slots := make([]int, len(entityIds))
+	oldCoords := make([][]Coord, len(entityIds))
+	newCoords := make([][]Coord, len(entityIds))
+	wrapped := make([]bool, len(entityIds))
+	for i, entityId := range entityIds {
+		slot, err := s.slotOf(entityId)
+		if err != nil {
+			return err
+		}
+		slots[i] = slot
+		oldCoords[i] = s.positions[slot]
+		for _, coord := range oldCoords[i] {
+			next, _, ok := topology.Step(coord, directions[i], width, height)
+			if !ok || !s.spatialMap.ValidateCoord(next) {
+				return fmt.Errorf("can not move entity %s to invalid coordinates %s", entityId, next.String())
+			}
+			if terrain.Blocked(next) {
+				return fmt.Errorf("can not move entity %s onto blocked terrain at %s", entityId, next.String())
+			}
+			wrapped[i] = wrapped[i] || next != coord.Move(directions[i])
+			newCoords[i] = append(newCoords[i], next)
+		}
+	}
+
+	// Take every entity off the map before putting any back, so entities that
+	// move into each other's cells never meet
+	for i, entityId := range entityIds {
+		if err := s.spatialMap.removeEntity(entityId, oldCoords[i]...); err != nil {
+			rollbackErr := s.placeEntities(entityIds[:i], oldCoords[:i])
+			return errors.Join(fmt.Errorf("failed to remove entity %s from its current location: %v", entityId, err), rollbackErr)
+		}
+	}
+	for i, entityId := range entityIds {
+		if err := s.spatialMap.addEntity(entityId, newCoords[i]...); err != nil {
+			var rollbackErrs []error
+			for j := 0; j < i; j++ {
+				if err := s.spatialMap.removeEntity(entityIds[j], newCoords[j]...); err != nil {
+					rollbackErrs = append(rollbackErrs, err)
+				}
+			}
+			rollbackErrs = append(rollbackErrs, s.placeEntities(entityIds, oldCoords))
+			return errors.Join(fmt.Errorf("failed to add entity %s to its new location: %v", entityId, err), errors.Join(rollbackErrs...))
+		}
+	}
+
+	for i, slot := range slots {
+		s.positions[slot] = newCoords[i]
+	}
+	if s.observed() {
+		for i, slot := range slots {
+			s.emitMoves([]int{slot}, oldCoords[i:i+1], newCoords[i:i+1], directions[i])
+			if wrapped[i] {
+				s.emit(Event{Kind: Wrapped, EntityId: entityIds[i], EntityType: s.types[slot], From: oldCoords[i], To: newCoords[i], Direction: directions[i]})
+			}
+		}
+	}
+	return nil
+}
+
+// placeEntities puts entities back on the map at the given coordinates, as part
+// of a rollback.
+func (s *simulation) placeEntities(entityIds []EntityId, coords [][]Coord) error {
+	var errs []error
+	for i, entityId := range entityIds {
+		if err := s.spatialMap.addEntity(entityId, coords[i]...); err != nil {
+			errs = append(errs, fmt.Errorf("failed to roll back entity %s: %v", entityId, err))
+		}
+	}
+	return errors.Join(errs...)
+}
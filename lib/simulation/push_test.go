@@ -0,0 +1,171 @@
+package simulation
+
+import (
+	"strings"
+	"testing"
+)
+
+// Robots push two cell boxes and stop at walls, anything else can be walked over
+var testPushRules = PushRules{
+	"box":  Pushable,
+	"wall": Wall,
+}
+
+// pushLegend draws robots as @, walls as #, two cell boxes as [] and passable
+// markers as ~
+var pushLegend = MustNewLegend('.', map[rune]string{
+	'@': "robot",
+	'#': "wall",
+	'~': "marker",
+}, WithShape("box", "[]"), WithPriority("robot", "box", "wall"))
+
+// buildPushSim lays out a simulation from a picture drawn with pushLegend
+func buildPushSim(t *testing.T, b backend, layout []string) (Simulation, EntityId) {
+	t.Helper()
+	sim, err := pushLegend.Parse(layout, b.options...)
+	if err != nil {
+		t.Fatalf("Failed to parse layout: %v", err)
+	}
+	for _, e := range sim.GetEntities() {
+		if e.GetEntityType() == "robot" {
+			return sim, e.GetId()
+		}
+	}
+	t.Fatalf("Layout has no robot")
+	return nil, NilEntityId
+}
+
+func renderPushSim(sim Simulation) []string {
+	return pushLegend.RenderLines(sim)
+}
+
+func TestPush(t *testing.T) {
+	forEachBackend(t, testPush)
+}
+
+func testPush(t *testing.T, b backend) {
+	tests := []struct {
+		name      string
+		layout    []string
+		direction Direction
+		moved     bool
+		expected  []string
+	}{
+		{
+			name:      "Free move",
+			layout:    []string{"......", "..@...", "......"},
+			direction: East,
+			moved:     true,
+			expected:  []string{"......", "...@..", "......"},
+		},
+		{
+			name:      "Row of boxes",
+			layout:    []string{"@[][].."},
+			direction: East,
+			moved:     true,
+			expected:  []string{".@[][]."},
+		},
+		{
+			name:      "Row of boxes against a wall",
+			layout:    []string{"@[][]#."},
+			direction: East,
+			moved:     false,
+			expected:  []string{"@[][]#."},
+		},
+		{
+			name:      "Row of boxes against the edge",
+			layout:    []string{".@[][]"},
+			direction: East,
+			moved:     false,
+			expected:  []string{".@[][]"},
+		},
+		{
+			name:      "Boxes fanning out",
+			layout:    []string{"......", "......", ".[][].", "..[]..", "..@..."},
+			direction: North,
+			moved:     true,
+			expected:  []string{"......", ".[][].", "..[]..", "..@...", "......"},
+		},
+		{
+			name:      "Boxes fanning out into a wall",
+			layout:    []string{"......", "....#.", ".[][].", "..[]..", "..@..."},
+			direction: North,
+			moved:     false,
+			expected:  []string{"......", "....#.", ".[][].", "..[]..", "..@..."},
+		},
+		{
+			name:      "Over a passable marker",
+			layout:    []string{"@[]~."},
+			direction: East,
+			moved:     true,
+			expected:  []string{".@[]."},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sim, robotId := buildPushSim(t, b, test.layout)
+			moved, err := sim.Push(robotId, test.direction, testPushRules)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if moved != test.moved {
+				t.Errorf("Expected moved to be %v, got %v", test.moved, moved)
+			}
+			got := renderPushSim(sim)
+			if strings.Join(got, "\n") != strings.Join(test.expected, "\n") {
+				t.Errorf("Expected\n%s\ngot\n%s", strings.Join(test.expected, "\n"), strings.Join(got, "\n"))
+			}
+		})
+	}
+}
+
+func TestPushLeavesMarkersBehind(t *testing.T) {
+	forEachBackend(t, testPushLeavesMarkersBehind)
+}
+
+func testPushLeavesMarkersBehind(t *testing.T, b backend) {
+	sim, robotId := buildPushSim(t, b, []string{"@~."})
+	for i := 0; i < 2; i++ {
+		if _, err := sim.Push(robotId, East, testPushRules); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if got := strings.Join(renderPushSim(sim), ""); got != ".~@" {
+		t.Errorf("Expected the marker to stay put, got %s", got)
+	}
+}
+
+func TestMoveEntities(t *testing.T) {
+	forEachBackend(t, testMoveEntities)
+}
+
+func testMoveEntities(t *testing.T, b backend) {
+	sim, robotId := buildPushSim(t, b, []string{"....", "@[].", "...."})
+	var boxId EntityId
+	for _, e := range sim.GetEntities() {
+		if e.GetEntityType() == "box" {
+			boxId = e.GetId()
+		}
+	}
+
+	// Entities moving into each other's cells do not get in each other's way
+	if err := sim.MoveEntities([]EntityId{robotId, boxId}, East); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := strings.Join(renderPushSim(sim), "|"); got != "....|.@[]|...." {
+		t.Errorf("Expected both entities to move, got %s", got)
+	}
+
+	// The box would leave the map, so the robot stays put as well
+	if err := sim.MoveEntities([]EntityId{robotId, boxId}, East); err == nil {
+		t.Errorf("Expected an error moving the box off the map")
+	}
+	if got := strings.Join(renderPushSim(sim), "|"); got != "....|.@[]|...." {
+		t.Errorf("Expected nothing to move, got %s", got)
+	}
+
+	if err := sim.MoveEntities([]EntityId{robotId, newEntityId(99, 1)}, East); err == nil {
+		t.Errorf("Expected an error for an unknown entity")
+	}
+}
@@ -0,0 +1,253 @@
+package simulation
+
+import (
+	"fmt"
+	"slices"
+)
+
+/////////////////////////////////////////////////////////////////////////////////////
+// REGIONS
+/////////////////////////////////////////////////////////////////////////////////////
+
+// Rect is a rectangle of cells, Min and Max included
+type Rect struct {
+	Min, Max Coord
+}
+
+// Contains returns whether the coordinates are inside the rectangle
+func (r Rect) Contains(c Coord) bool {
+	return c.X >= r.Min.X && c.X <= r.Max.X && c.Y >= r.Min.Y && c.Y <= r.Max.Y
+}
+
+// Empty returns whether the rectangle holds no cells
+func (r Rect) Empty() bool {
+	return r.Min.X > r.Max.X || r.Min.Y > r.Max.Y
+}
+
+// Area returns the number of cells in the rectangle
+func (r Rect) Area() int {
+	if r.Empty() {
+		return 0
+	}
+	return (r.Max.X - r.Min.X + 1) * (r.Max.Y - r.Min.Y + 1)
+}
+
+// smallerThan returns whether the rectangle holds fewer than n cells, without
+// overflowing on huge rectangles
+func (r Rect) smallerThan(n int) bool {
+	width, height := r.Max.X-r.Min.X+1, r.Max.Y-r.Min.Y+1
+	if width >= n || height >= n {
+		return false
+	}
+	return r.Area() < n
+}
+
+// Intersect returns the cells in both rectangles, which may be Empty
+func (r Rect) Intersect(other Rect) Rect {
+	return Rect{
+		Min: Coord{X: max(r.Min.X, other.Min.X), Y: max(r.Min.Y, other.Min.Y)},
+		Max: Coord{X: min(r.Max.X, other.Max.X), Y: min(r.Max.Y, other.Max.Y)},
+	}
+}
+
+// Quadrants splits a width by height map into its NW, NE, SW and SE quadrants.
+// When a side is odd the middle row or column is in none of them, the way 2024
+// day 14 counts robots.
+func Quadrants(width, height int) []Rect {
+	w, h := width/2, height/2
+	right, bottom := width-w, height-h
+	return []Rect{
+		{Min: Coord{X: 0, Y: 0}, Max: Coord{X: w - 1, Y: h - 1}},
+		{Min: Coord{X: right, Y: 0}, Max: Coord{X: width - 1, Y: h - 1}},
+		{Min: Coord{X: 0, Y: bottom}, Max: Coord{X: w - 1, Y: height - 1}},
+		{Min: Coord{X: right, Y: bottom}, Max: Coord{X: width - 1, Y: height - 1}},
+	}
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// TYPE INDEX
+/////////////////////////////////////////////////////////////////////////////////////
+
+// The type index maps each entity type to the slots holding entities of that
+// type, in ascending order so queries return entities in the same order as
+// GetEntities. It is updated by AddEntity and RemoveEntity, moving an entity
+// never changes its type.
+
+// indexType adds a slot to the type index
+func (s *simulation) indexType(entityType string, slot int) {
+	if s.byType == nil {
+		s.byType = make(map[string][]int)
+	}
+	slots := s.byType[entityType]
+	i, _ := slices.BinarySearch(slots, slot)
+	s.byType[entityType] = slices.Insert(slots, i, slot)
+}
+
+// unindexType removes a slot from the type index
+func (s *simulation) unindexType(entityType string, slot int) {
+	slots := s.byType[entityType]
+	if i, found := slices.BinarySearch(slots, slot); found {
+		slots = slices.Delete(slots, i, i+1)
+	}
+	if len(slots) == 0 {
+		delete(s.byType, entityType)
+		return
+	}
+	s.byType[entityType] = slots
+}
+
+// cloneTypeIndex copies the type index for a clone, the slot slices are changed
+// in place so they can not be shared
+func (s *simulation) cloneTypeIndex() map[string][]int {
+	byType := make(map[string][]int, len(s.byType))
+	for entityType, slots := range s.byType {
+		byType[entityType] = slices.Clone(slots)
+	}
+	return byType
+}
+
+// candidates returns the slots of the entities of the given types in ascending
+// order, or of every live entity when no types are given
+func (s *simulation) candidates(entityTypes []string) []int {
+	if len(entityTypes) == 0 {
+		slots := make([]int, 0, len(s.live)-len(s.free))
+		for slot, live := range s.live {
+			if live {
+				slots = append(slots, slot)
+			}
+		}
+		return slots
+	}
+	if len(entityTypes) == 1 {
+		return s.byType[entityTypes[0]]
+	}
+	var slots []int
+	for _, entityType := range entityTypes {
+		slots = append(slots, s.byType[entityType]...)
+	}
+	slices.Sort(slots)
+	return slices.Compact(slots)
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// QUERIES
+/////////////////////////////////////////////////////////////////////////////////////
+
+// GetEntitiesOfType returns a snapshot of every entity of a type, in slot order
+func (s *simulation) GetEntitiesOfType(entityType string) []Entity {
+	s.updateMutex.Lock()
+	defer s.updateMutex.Unlock()
+
+	slots := s.byType[entityType]
+	entities := make([]Entity, len(slots))
+	for i, slot := range slots {
+		entities[i] = s.entityAt(slot)
+	}
+	return entities
+}
+
+// GetEntitiesIn returns a snapshot of every entity with at least one cell
+// inside the rectangle, in slot order. When entity types are given only
+// entities of those types are returned.
+func (s *simulation) GetEntitiesIn(rect Rect, entityTypes ...string) []Entity {
+	s.updateMutex.Lock()
+	defer s.updateMutex.Unlock()
+
+	slots := s.slotsIn(rect, entityTypes)
+	entities := make([]Entity, len(slots))
+	for i, slot := range slots {
+		entities[i] = s.entityAt(slot)
+	}
+	return entities
+}
+
+// CountPerRegion counts the entities with at least one cell inside each
+// region. When entity types are given only entities of those types are counted.
+func (s *simulation) CountPerRegion(regions []Rect, entityTypes ...string) []int {
+	s.updateMutex.Lock()
+	defer s.updateMutex.Unlock()
+
+	counts := make([]int, len(regions))
+	for i, region := range regions {
+		counts[i] = len(s.slotsIn(region, entityTypes))
+	}
+	return counts
+}
+
+// Nearest returns the entity of a type with a cell closest to the coordinates,
+// going by Manhattan distance. Ties go to the entity added first. It returns an
+// error when there is no entity of the type.
+func (s *simulation) Nearest(from Coord, entityType string) (Entity, error) {
+	s.updateMutex.Lock()
+	defer s.updateMutex.Unlock()
+
+	nearest, best := -1, 0
+	for _, slot := range s.byType[entityType] {
+		for _, coord := range s.positions[slot] {
+			distance := from.Vec().Manhattan(coord.Vec())
+			if nearest == -1 || distance < best {
+				nearest, best = slot, distance
+			}
+		}
+	}
+	if nearest == -1 {
+		return nil, fmt.Errorf("no entity of type %s", entityType)
+	}
+	return s.entityAt(nearest), nil
+}
+
+// slotsIn returns the slots of the entities inside a rectangle in ascending
+// order. It walks the cells of the rectangle or the candidate entities,
+// whichever there are fewer of. The caller holds the update lock.
+func (s *simulation) slotsIn(rect Rect, entityTypes []string) []int {
+	if rect.Empty() {
+		return nil
+	}
+	count := len(s.live) - len(s.free)
+	if len(entityTypes) > 0 {
+		count = 0
+		for _, entityType := range entityTypes {
+			count += len(s.byType[entityType])
+		}
+	}
+	if rect.smallerThan(count) {
+		// Only the part of the rectangle on the map has cells to look at
+		min, max := s.spatialMap.GetBounds()
+		return s.slotsInCells(rect.Intersect(Rect{Min: min, Max: max}), entityTypes)
+	}
+
+	var slots []int
+	for _, slot := range s.candidates(entityTypes) {
+		if slices.ContainsFunc(s.positions[slot], rect.Contains) {
+			slots = append(slots, slot)
+		}
+	}
+	return slots
+}
+
+// slotsInCells returns the slots of the entities inside a rectangle by looking
+// at each of its cells
+func (s *simulation) slotsInCells(rect Rect, entityTypes []string) []int {
+	var slots []int
+	seen := make(map[int]bool)
+	for y := rect.Min.Y; y <= rect.Max.Y; y++ {
+		for x := rect.Min.X; x <= rect.Max.X; x++ {
+			cell, err := s.spatialMap.GetCell(Coord{X: x, Y: y})
+			if err != nil {
+				continue
+			}
+			for _, entityId := range cell.GetEntityIds() {
+				slot := entityId.slot()
+				if seen[slot] {
+					continue
+				}
+				seen[slot] = true
+				if len(entityTypes) == 0 || slices.Contains(entityTypes, s.types[slot]) {
+					slots = append(slots, slot)
+				}
+			}
+		}
+	}
+	slices.Sort(slots)
+	return slots
+}
@@ -0,0 +1,200 @@
+package simulation
+
+import (
+	"slices"
+	"testing"
+)
+
+var queryLegend = MustNewLegend('.', map[rune]string{
+	'#': "wall",
+	'r': "robot",
+	'g': "guard",
+}, WithShape("box", "[]"))
+
+// The map the query tests run on, with a box across the middle column
+//
+//	r...#
+//	..[]g
+//	.....
+//	#..r.
+func parseQuerySim(t *testing.T, b backend) Simulation {
+	t.Helper()
+	sim, err := queryLegend.Parse([]string{
+		"r...#",
+		"..[]g",
+		".....",
+		"#..r.",
+	}, b.options...)
+	if err != nil {
+		t.Fatalf("Failed to parse map: %v", err)
+	}
+	return sim
+}
+
+// positions returns the first cell of each entity
+func positions(entities []Entity) []Coord {
+	coords := make([]Coord, len(entities))
+	for i, e := range entities {
+		coords[i] = e.GetPosition()[0]
+	}
+	return coords
+}
+
+func TestGetEntitiesOfType(t *testing.T) {
+	forEachBackend(t, testGetEntitiesOfType)
+}
+
+func testGetEntitiesOfType(t *testing.T, b backend) {
+	sim := parseQuerySim(t, b)
+
+	robots := sim.GetEntitiesOfType("robot")
+	if want := []Coord{{X: 0, Y: 0}, {X: 3, Y: 3}}; !slices.Equal(positions(robots), want) {
+		t.Errorf("Expected robots at %v, got %v", want, positions(robots))
+	}
+	if got := sim.GetEntitiesOfType("ghost"); len(got) != 0 {
+		t.Errorf("Expected no ghosts, got %d", len(got))
+	}
+
+	// The index follows entities being removed and added
+	if err := sim.RemoveEntity(robots[0].GetId()); err != nil {
+		t.Fatalf("Failed to remove robot: %v", err)
+	}
+	e, err := NewEntity("robot")
+	if err != nil {
+		t.Fatalf("Failed to create entity: %v", err)
+	}
+	if _, err := sim.AddEntity(e, []Coord{{X: 1, Y: 2}}, North); err != nil {
+		t.Fatalf("Failed to add entity: %v", err)
+	}
+	robots = sim.GetEntitiesOfType("robot")
+	if want := []Coord{{X: 1, Y: 2}, {X: 3, Y: 3}}; !slices.Equal(positions(robots), want) {
+		t.Errorf("Expected robots at %v after reusing a slot, got %v", want, positions(robots))
+	}
+
+	// A clone keeps its own index
+	clone := sim.Clone()
+	if err := clone.RemoveEntity(robots[0].GetId()); err != nil {
+		t.Fatalf("Failed to remove robot from clone: %v", err)
+	}
+	if len(clone.GetEntitiesOfType("robot")) != 1 || len(sim.GetEntitiesOfType("robot")) != 2 {
+		t.Errorf("Expected removing from the clone to leave the original alone")
+	}
+}
+
+func TestGetEntitiesIn(t *testing.T) {
+	forEachBackend(t, testGetEntitiesIn)
+}
+
+func testGetEntitiesIn(t *testing.T, b backend) {
+	sim := parseQuerySim(t, b)
+
+	tests := []struct {
+		name        string
+		rect        Rect
+		entityTypes []string
+		want        []Coord
+	}{
+		{name: "Whole map", rect: Rect{Max: Coord{X: 4, Y: 3}}, entityTypes: []string{"robot", "guard"}, want: []Coord{{X: 0, Y: 0}, {X: 4, Y: 1}, {X: 3, Y: 3}}},
+		{name: "Single cell", rect: Rect{Min: Coord{X: 4, Y: 0}, Max: Coord{X: 4, Y: 0}}, want: []Coord{{X: 4, Y: 0}}},
+		{name: "Part of a box", rect: Rect{Min: Coord{X: 3, Y: 0}, Max: Coord{X: 4, Y: 2}}, entityTypes: []string{"box"}, want: []Coord{{X: 2, Y: 1}}},
+		{name: "Every type", rect: Rect{Min: Coord{X: 2, Y: 1}, Max: Coord{X: 4, Y: 1}}, want: []Coord{{X: 2, Y: 1}, {X: 4, Y: 1}}},
+		{name: "Off the map", rect: Rect{Min: Coord{X: -5, Y: -5}, Max: Coord{X: -1, Y: -1}}, want: []Coord{}},
+		{name: "Partly off the map", rect: Rect{Min: Coord{X: -5, Y: 2}, Max: Coord{X: 0, Y: 100}}, want: []Coord{{X: 0, Y: 3}}},
+		{name: "Empty", rect: Rect{Min: Coord{X: 2, Y: 2}, Max: Coord{X: 1, Y: 2}}, want: []Coord{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := positions(sim.GetEntitiesIn(test.rect, test.entityTypes...))
+			if !slices.Equal(got, test.want) {
+				t.Errorf("Expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestCountPerRegion(t *testing.T) {
+	forEachBackend(t, testCountPerRegion)
+}
+
+func testCountPerRegion(t *testing.T, b backend) {
+	sim := parseQuerySim(t, b)
+
+	// The middle column of the 5 wide map is in no quadrant
+	quadrants := Quadrants(5, 4)
+	if got := sim.CountPerRegion(quadrants, "robot", "guard"); !slices.Equal(got, []int{1, 1, 0, 1}) {
+		t.Errorf("Expected a robot in NW and SE and the guard in NE, got %v", got)
+	}
+	// The box reaches into NE from the middle column
+	if got := sim.CountPerRegion(quadrants); !slices.Equal(got, []int{1, 3, 1, 1}) {
+		t.Errorf("Expected every entity counted in each quadrant it reaches, got %v", got)
+	}
+}
+
+func TestQuadrants(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		want          []Rect
+	}{
+		{name: "Odd", width: 11, height: 7, want: []Rect{
+			{Min: Coord{X: 0, Y: 0}, Max: Coord{X: 4, Y: 2}},
+			{Min: Coord{X: 6, Y: 0}, Max: Coord{X: 10, Y: 2}},
+			{Min: Coord{X: 0, Y: 4}, Max: Coord{X: 4, Y: 6}},
+			{Min: Coord{X: 6, Y: 4}, Max: Coord{X: 10, Y: 6}},
+		}},
+		{name: "Even", width: 4, height: 2, want: []Rect{
+			{Min: Coord{X: 0, Y: 0}, Max: Coord{X: 1, Y: 0}},
+			{Min: Coord{X: 2, Y: 0}, Max: Coord{X: 3, Y: 0}},
+			{Min: Coord{X: 0, Y: 1}, Max: Coord{X: 1, Y: 1}},
+			{Min: Coord{X: 2, Y: 1}, Max: Coord{X: 3, Y: 1}},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Quadrants(test.width, test.height); !slices.Equal(got, test.want) {
+				t.Errorf("Expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestNearest(t *testing.T) {
+	forEachBackend(t, testNearest)
+}
+
+func testNearest(t *testing.T, b backend) {
+	sim := parseQuerySim(t, b)
+
+	tests := []struct {
+		name       string
+		from       Coord
+		entityType string
+		want       Coord
+		wantErr    bool
+	}{
+		{name: "Closest robot", from: Coord{X: 2, Y: 3}, entityType: "robot", want: Coord{X: 3, Y: 3}},
+		{name: "Tie goes to the first added", from: Coord{X: 2, Y: 1}, entityType: "robot", want: Coord{X: 0, Y: 0}},
+		{name: "Any cell of a box", from: Coord{X: 3, Y: 3}, entityType: "box", want: Coord{X: 2, Y: 1}},
+		{name: "No entity of the type", from: Coord{X: 0, Y: 0}, entityType: "ghost", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e, err := sim.Nearest(test.from, test.entityType)
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to find nearest: %v", err)
+			}
+			if got := e.GetPosition()[0]; got != test.want {
+				t.Errorf("Expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package simulation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+/////////////////////////////////////////////////////////////////////////////////////
+// SYSTEMS
+/////////////////////////////////////////////////////////////////////////////////////
+
+// System is a rule applied to the simulation once per tick, such as moving the
+// entities, resolving collisions or spawning new entities. S is the simulation
+// the rules work on, usually Simulation, but any wrapper around one works too.
+type System[S any] struct {
+	Name string
+	Run  func(sim S, tick int) error
+}
+
+// SystemTiming is the time spent in a system over every tick it ran
+type SystemTiming struct {
+	Name  string
+	Calls int
+	Total time.Duration
+}
+
+// Mean returns the average time spent in the system per tick
+func (t SystemTiming) Mean() time.Duration {
+	if t.Calls == 0 {
+		return 0
+	}
+	return t.Total / time.Duration(t.Calls)
+}
+
+func (t SystemTiming) String() string {
+	return fmt.Sprintf("%s: %s over %d ticks, %s per tick", t.Name, t.Total, t.Calls, t.Mean())
+}
+
+// Every runs a system only on every nth tick, starting with tick n
+func Every[S any](n int, system System[S]) System[S] {
+	return System[S]{
+		Name: system.Name,
+		Run: func(sim S, tick int) error {
+			if n > 1 && tick%n != 0 {
+				return nil
+			}
+			return system.Run(sim, tick)
+		},
+	}
+}
+
+// Movement moves every entity one step in the direction it is facing, or only
+// the entities of the given types. Entities blocked by the edge of the map or by
+// terrain stay where they are, observers hear about them as Blocked events.
+func Movement(wrapping bool, entityTypes ...string) System[Simulation] {
+	return System[Simulation]{
+		Name: "movement",
+		Run: func(sim Simulation, tick int) error {
+			entities := sim.GetEntities()
+			if len(entityTypes) > 0 {
+				entities = slices.DeleteFunc(entities, func(e Entity) bool {
+					return !slices.Contains(entityTypes, e.GetEntityType())
+				})
+			}
+			for _, e := range entities {
+				err := sim.MoveEntity(e.GetId(), wrapping)
+				var blocked BlockedError
+				if err != nil && !errors.As(err, &blocked) {
+					return fmt.Errorf("error moving entity %s: %v", e.GetId(), err)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// Collisions calls resolve for every cell holding more than one entity, with
+// the ids of the entities in the cell. Cells are visited top to bottom and left
+// to right. The resolver may move or remove the entities.
+func Collisions(resolve func(sim Simulation, coord Coord, entityIds []EntityId) error) System[Simulation] {
+	return System[Simulation]{
+		Name: "collisions",
+		Run: func(sim Simulation, tick int) error {
+			m := sim.GetMap()
+			cells := make(map[Coord][]EntityId)
+			for _, e := range sim.GetEntities() {
+				for _, coord := range e.GetPosition() {
+					if _, seen := cells[coord]; seen {
+						continue
+					}
+					cell, err := m.GetCell(coord)
+					if err != nil {
+						return fmt.Errorf("error checking cell %s: %v", coord.String(), err)
+					}
+					cells[coord] = cell.GetEntityIds()
+				}
+			}
+			crowded := make([]Coord, 0)
+			for coord, entityIds := range cells {
+				if len(entityIds) > 1 {
+					crowded = append(crowded, coord)
+				}
+			}
+			slices.SortFunc(crowded, func(a, b Coord) int {
+				if a.Y != b.Y {
+					return a.Y - b.Y
+				}
+				return a.X - b.X
+			})
+			for _, coord := range crowded {
+				if err := resolve(sim, coord, cells[coord]); err != nil {
+					return fmt.Errorf("error resolving collision at %s: %v", coord.String(), err)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// Spawn adds an entity of a type at the coordinates facing the direction, on
+// ticks when the cell is empty
+func Spawn(entityType string, at Coord, direction Direction) System[Simulation] {
+	return System[Simulation]{
+		Name: "spawn " + entityType,
+		Run: func(sim Simulation, tick int) error {
+			cell, err := sim.GetMap().GetCell(at)
+			if err != nil {
+				return fmt.Errorf("error checking spawn cell %s: %v", at.String(), err)
+			}
+			if !cell.IsEmpty() {
+				return nil
+			}
+			e, err := NewEntity(entityType)
+			if err != nil {
+				return fmt.Errorf("error creating entity of type %s: %v", entityType, err)
+			}
+			if _, err := sim.AddEntity(e, []Coord{at}, direction); err != nil {
+				return fmt.Errorf("error spawning entity of type %s: %v", entityType, err)
+			}
+			return nil
+		},
+	}
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// SCHEDULER
+/////////////////////////////////////////////////////////////////////////////////////
+
+// Scheduler runs its systems in the order they were added, once per tick, and
+// keeps count of the ticks and the time spent in each system.
+type Scheduler[S any] struct {
+	sim     S
+	systems []System[S]
+	timings []SystemTiming
+	tick    int // Ticks completed
+}
+
+// NewScheduler creates a scheduler running the systems on the simulation
+func NewScheduler[S any](sim S, systems ...System[S]) *Scheduler[S] {
+	s := &Scheduler[S]{sim: sim}
+	for _, system := range systems {
+		s.AddSystem(system)
+	}
+	return s
+}
+
+// AddSystem adds a system to run after the ones already added
+func (s *Scheduler[S]) AddSystem(system System[S]) {
+	s.systems = append(s.systems, system)
+	s.timings = append(s.timings, SystemTiming{Name: system.Name})
+}
+
+// Tick returns the number of ticks completed
+func (s *Scheduler[S]) Tick() int {
+	return s.tick
+}
+
+// Timings returns the time spent in each system, in the order they run
+func (s *Scheduler[S]) Timings() []SystemTiming {
+	return slices.Clone(s.timings)
+}
+
+// Step runs every system once as the next tick. Ticks are numbered from 1. If a
+// system fails the tick is not counted and the systems after it do not run.
+func (s *Scheduler[S]) Step() error {
+	tick := s.tick + 1
+	for i, system := range s.systems {
+		start := time.Now()
+		err := system.Run(s.sim, tick)
+		s.timings[i].Calls++
+		s.timings[i].Total += time.Since(start)
+		if err != nil {
+			return fmt.Errorf("error in system %s during tick %d: %v", system.Name, tick, err)
+		}
+	}
+	s.tick = tick
+	return nil
+}
+
+// RunN runs n ticks, stopping early when ctx is done
+func (s *Scheduler[S]) RunN(ctx context.Context, n int) error {
+	for i := 0; i < n; i++ {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("simulated %d of %d ticks: %w", i, n, err)
+		}
+		if err := s.Step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunUntil runs ticks until done returns true after one of them, and returns
+// true. It gives up and returns false after maxTicks ticks, 0 runs for as long
+// as it takes. It stops early when ctx is done.
+func (s *Scheduler[S]) RunUntil(ctx context.Context, done func(sim S, tick int) bool, maxTicks int) (bool, error) {
+	for i := 0; maxTicks == 0 || i < maxTicks; i++ {
+		if err := ctx.Err(); err != nil {
+			return false, fmt.Errorf("simulated %d ticks: %w", i, err)
+		}
+		if err := s.Step(); err != nil {
+			return false, err
+		}
+		if done(s.sim, s.tick) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
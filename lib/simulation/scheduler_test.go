@@ -0,0 +1,178 @@
+package simulation
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// The map the scheduler tests run on, with the robots turned East
+//
+//	....
+//	r..r
+//	..#.
+func parseSchedulerSim(t *testing.T, b backend) Simulation {
+	t.Helper()
+	sim, err := queryLegend.Parse([]string{"....", "r..r", "..#."}, b.options...)
+	if err != nil {
+		t.Fatalf("Failed to parse map: %v", err)
+	}
+	for _, robot := range sim.GetEntitiesOfType("robot") {
+		if err := sim.SetEntityDirection(robot.GetId(), East); err != nil {
+			t.Fatalf("Failed to turn robot: %v", err)
+		}
+	}
+	return sim
+}
+
+func TestSchedulerSystemsRunInOrder(t *testing.T) {
+	var calls []string
+	system := func(name string) System[string] {
+		return System[string]{Name: name, Run: func(sim string, tick int) error {
+			calls = append(calls, sim+name+string(rune('0'+tick)))
+			return nil
+		}}
+	}
+	s := NewScheduler("sim.", system("a"), system("b"))
+	s.AddSystem(Every(2, system("c")))
+
+	if err := s.RunN(context.Background(), 3); err != nil {
+		t.Fatalf("Failed to run: %v", err)
+	}
+	want := []string{"sim.a1", "sim.b1", "sim.a2", "sim.b2", "sim.c2", "sim.a3", "sim.b3"}
+	if !slices.Equal(calls, want) {
+		t.Errorf("Expected %v, got %v", want, calls)
+	}
+	if s.Tick() != 3 {
+		t.Errorf("Expected 3 ticks, got %d", s.Tick())
+	}
+
+	timings := s.Timings()
+	if len(timings) != 3 || timings[0].Name != "a" || timings[0].Calls != 3 || timings[2].Calls != 3 {
+		t.Errorf("Expected a timing per system counting every tick, got %v", timings)
+	}
+}
+
+func TestSchedulerErrors(t *testing.T) {
+	s := NewScheduler(0, System[int]{Name: "fails on 2", Run: func(sim int, tick int) error {
+		if tick == 2 {
+			return errors.New("boom")
+		}
+		return nil
+	}})
+	err := s.RunN(context.Background(), 5)
+	if err == nil || !strings.Contains(err.Error(), "fails on 2") || !strings.Contains(err.Error(), "tick 2") {
+		t.Errorf("Expected the error to name the system and tick, got %v", err)
+	}
+	if s.Tick() != 1 {
+		t.Errorf("Expected the failed tick not to count, got %d", s.Tick())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := NewScheduler(0).RunN(ctx, 5); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled context to stop the run, got %v", err)
+	}
+}
+
+func TestSchedulerRunUntil(t *testing.T) {
+	s := NewScheduler(0)
+	done, err := s.RunUntil(context.Background(), func(sim int, tick int) bool { return tick == 4 }, 10)
+	if err != nil || !done || s.Tick() != 4 {
+		t.Errorf("Expected to be done after 4 ticks, got %v after %d (%v)", done, s.Tick(), err)
+	}
+
+	done, err = s.RunUntil(context.Background(), func(sim int, tick int) bool { return false }, 3)
+	if err != nil || done || s.Tick() != 7 {
+		t.Errorf("Expected to give up after 3 more ticks, got %v after %d (%v)", done, s.Tick(), err)
+	}
+}
+
+func TestMovementAndCollisions(t *testing.T) {
+	forEachBackend(t, testMovementAndCollisions)
+}
+
+func testMovementAndCollisions(t *testing.T, b backend) {
+	sim := parseSchedulerSim(t, b)
+
+	// Robots that meet are removed
+	var collisions []Coord
+	resolve := func(sim Simulation, coord Coord, entityIds []EntityId) error {
+		collisions = append(collisions, coord)
+		for _, entityId := range entityIds {
+			if err := sim.RemoveEntity(entityId); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	s := NewScheduler(sim, Movement(true, "robot"), Collisions(resolve))
+
+	if err := s.RunN(context.Background(), 1); err != nil {
+		t.Fatalf("Failed to run: %v", err)
+	}
+	// The robot on the right wrapped around onto the left edge
+	if got := positions(sim.GetEntitiesOfType("robot")); !slices.Equal(got, []Coord{{X: 1, Y: 1}, {X: 0, Y: 1}}) {
+		t.Errorf("Expected the robots at (1, 1) and (0, 1), got %v", got)
+	}
+	if len(sim.GetEntitiesOfType("wall")) != 1 {
+		t.Errorf("Expected only robots to move")
+	}
+
+	// Robots at 1 and 0 never meet, make one wait so they do
+	s = NewScheduler(sim, Movement(true, "robot"), Collisions(resolve))
+	first := sim.GetEntitiesOfType("robot")[0]
+	if err := sim.SetEntityDirection(first.GetId(), Direction{}); err != nil {
+		t.Fatalf("Failed to stop robot: %v", err)
+	}
+	if err := s.RunN(context.Background(), 1); err != nil {
+		t.Fatalf("Failed to run: %v", err)
+	}
+	if !slices.Equal(collisions, []Coord{{X: 1, Y: 1}}) {
+		t.Errorf("Expected a collision at (1, 1), got %v", collisions)
+	}
+	if len(sim.GetEntitiesOfType("robot")) != 0 {
+		t.Errorf("Expected the colliding robots to be removed")
+	}
+}
+
+func TestMovementSkipsBlocked(t *testing.T) {
+	forEachBackend(t, testMovementSkipsBlocked)
+}
+
+func testMovementSkipsBlocked(t *testing.T, b backend) {
+	sim := parseSchedulerSim(t, b)
+	events := recordEvents(sim, Blocked)
+	s := NewScheduler(sim, Movement(false, "robot"))
+
+	// The robot on the right is stopped by the edge, the other one still moves
+	if err := s.RunN(context.Background(), 1); err != nil {
+		t.Fatalf("Expected a blocked robot not to fail the tick: %v", err)
+	}
+	if got := positions(sim.GetEntitiesOfType("robot")); !slices.Equal(got, []Coord{{X: 1, Y: 1}, {X: 3, Y: 1}}) {
+		t.Errorf("Expected the robots at (1, 1) and (3, 1), got %v", got)
+	}
+	if len(*events) != 1 {
+		t.Errorf("Expected a Blocked event, got %v", *events)
+	}
+}
+
+func TestSpawn(t *testing.T) {
+	forEachBackend(t, testSpawn)
+}
+
+func testSpawn(t *testing.T, b backend) {
+	sim := parseSchedulerSim(t, b)
+	s := NewScheduler(sim, Movement(false, "spawned"), Spawn("spawned", Coord{X: 0, Y: 2}, North))
+
+	if err := s.RunN(context.Background(), 3); err != nil {
+		t.Fatalf("Failed to run: %v", err)
+	}
+	// One is spawned every tick, as the one before has moved out of the way
+	spawned := positions(sim.GetEntitiesOfType("spawned"))
+	if !slices.Equal(spawned, []Coord{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 2}}) {
+		t.Errorf("Expected a column of spawned entities, got %v", spawned)
+	}
+}
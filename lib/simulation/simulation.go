@@ -0,0 +1,1047 @@
+package simulation
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"maps"
+	"slices"
+	"sync"
+
+	"2ajoyce/adventofcode/lib/vec"
+
+	"github.com/google/uuid"
+)
+
+/////////////////////////////////////////////////////////////////////////////////////
+// COORD & DIRECTIONS
+/////////////////////////////////////////////////////////////////////////////////////
+
+type Coord struct {
+	X, Y int
+}
+
+func (c *Coord) String() string {
+	return fmt.Sprintf("(%d, %d)", c.X, c.Y)
+}
+
+func (c *Coord) Move(d Direction) Coord {
+	return Coord{X: c.X + d.VX, Y: c.Y + d.VY}
+}
+
+// Vec returns the coordinate as a vector, CostManhattan and Nearest measure with it
+func (c Coord) Vec() vec.Vec2 {
+	return vec.Vec2{X: c.X, Y: c.Y}
+}
+
+func CoordFromVec(v vec.Vec2) Coord {
+	return Coord{X: v.X, Y: v.Y}
+}
+
+// GetNeighbors returns the neighbors of a coordinate
+// Since this operation happens outside the context of a map, no validation
+// is done to check if the neighbors are within expected bounds
+func (c *Coord) GetNeighbors() []Coord {
+	neighbors := []Coord{
+		c.Move(North),
+		c.Move(East),
+		c.Move(South),
+		c.Move(West),
+	}
+
+	return neighbors
+}
+
+// DirectionTo returns the direction from one coordinate to another
+func (c *Coord) DirectionTo(coord Coord) Direction {
+	dx := coord.X - c.X
+	dy := coord.Y - c.Y
+	if dx > 0 {
+		return East
+	} else if dx < 0 {
+		return West
+	} else if dy > 0 {
+		return South
+	} else if dy < 0 {
+		return North
+	}
+	return Direction{VX: 0, VY: 0}
+}
+
+type Direction struct {
+	VX, VY int
+}
+
+func (d Direction) String() string {
+	// Turn the direction into a string
+	switch d {
+	case North:
+		return "North"
+	case East:
+		return "East"
+	case South:
+		return "South"
+	case West:
+		return "West"
+	}
+	return "Unknown Direction" // Default case should not be reached if directions are correct
+}
+
+// Vec returns the direction as a vector, which Rotate turns
+func (d Direction) Vec() vec.Vec2 {
+	return vec.Vec2{X: d.VX, Y: d.VY}
+}
+
+func DirectionFromVec(v vec.Vec2) Direction {
+	return Direction{VX: v.X, VY: v.Y}
+}
+
+// TurnLeft rotates the direction a quarter turn counter-clockwise.
+// Any direction can be turned, not just the four cardinal ones.
+func (d Direction) TurnLeft() Direction {
+	return d.Rotate(-1)
+}
+
+// TurnRight rotates the direction a quarter turn clockwise.
+func (d Direction) TurnRight() Direction {
+	return d.Rotate(1)
+}
+
+// Rotate rotates the direction by a number of quarter turns, clockwise when
+// positive and counter-clockwise when negative.
+func (d Direction) Rotate(quarterTurns int) Direction {
+	return DirectionFromVec(d.Vec().Rotate(quarterTurns))
+}
+
+// Define the four cardinal directions
+var (
+	North = Direction{VX: 0, VY: -1}
+	East  = Direction{VX: 1, VY: 0}
+	South = Direction{VX: 0, VY: 1}
+	West  = Direction{VX: -1, VY: 0}
+)
+
+/////////////////////////////////////////////////////////////////////////////////////
+// ERRORS
+/////////////////////////////////////////////////////////////////////////////////////
+
+type CellEmptyError struct{}
+
+func (e CellEmptyError) Error() string {
+	return "cell is empty"
+}
+
+// BlockedError is returned by MoveEntity when the edge of the map or blocked
+// terrain is in the way. Nothing moved, so callers moving many entities can
+// carry on with the rest.
+type BlockedError struct {
+	At      Coord // Where the entity would have gone
+	Terrain bool  // Whether blocked terrain was in the way, rather than the edge
+}
+
+func (e BlockedError) Error() string {
+	if e.Terrain {
+		return fmt.Sprintf("can not move entity onto blocked terrain at %s", e.At.String())
+	}
+	return fmt.Sprintf("can not move entity to invalid coordinates %s", e.At.String())
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// SPATIAL MAP CELLS
+/////////////////////////////////////////////////////////////////////////////////////
+
+type SpatialMapCell interface {
+	GetEntityIds() []EntityId
+	IsEmpty() bool
+	addEntityId(entityId EntityId) error
+	removeEntityId(entityId EntityId) error
+	Clone() SpatialMapCell
+}
+
+// spatialMapCell holds the ids of the entities in a cell. Most cells hold at
+// most one entity, which is stored inline so a whole map of cells can be copied
+// in one go. Any further entities go in a slice that is never changed in place,
+// so copies of a cell share it until one of them changes.
+// Cells are guarded by the map that holds them.
+type spatialMapCell struct {
+	first EntityId   // NilEntityId when the cell is empty
+	more  []EntityId // Entities added after the first, copied on write
+}
+
+func NewSpatialMapCell() SpatialMapCell {
+	return &spatialMapCell{}
+}
+
+func (c *spatialMapCell) IsEmpty() bool {
+	return c.first == NilEntityId
+}
+
+// GetEntityIds returns the ids in the order the entities entered the cell
+func (c *spatialMapCell) GetEntityIds() []EntityId {
+	if c.first == NilEntityId {
+		return []EntityId{}
+	}
+	ids := make([]EntityId, 0, 1+len(c.more))
+	ids = append(ids, c.first)
+	return append(ids, c.more...)
+}
+
+func (c *spatialMapCell) addEntityId(entityId EntityId) error {
+	if entityId == NilEntityId {
+		return fmt.Errorf("cannot add the nil entity id to a cell")
+	}
+	if c.first == entityId || slices.Contains(c.more, entityId) {
+		return fmt.Errorf("entity %s already exists in the cell", entityId)
+	}
+
+	if c.first == NilEntityId {
+		c.first = entityId
+		return nil
+	}
+	more := make([]EntityId, len(c.more), len(c.more)+1)
+	copy(more, c.more)
+	c.more = append(more, entityId)
+	return nil
+}
+
+func (c *spatialMapCell) removeEntityId(entityId EntityId) error {
+	if entityId != NilEntityId && c.first == entityId {
+		// Move the next entity up, re-slicing leaves the shared slice as it is
+		c.first = NilEntityId
+		if len(c.more) > 0 {
+			c.first = c.more[0]
+			c.more = c.more[1:]
+		}
+		if len(c.more) == 0 {
+			c.more = nil
+		}
+		return nil
+	}
+	if i := slices.Index(c.more, entityId); i >= 0 {
+		more := make([]EntityId, 0, len(c.more)-1)
+		more = append(more, c.more[:i]...)
+		c.more = append(more, c.more[i+1:]...)
+		if len(c.more) == 0 {
+			c.more = nil
+		}
+		return nil
+	}
+	return fmt.Errorf("entity %s not found in the cell", entityId)
+}
+
+func (c *spatialMapCell) Clone() SpatialMapCell {
+	clone := *c
+	return &clone
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// SPATIAL MAP
+/////////////////////////////////////////////////////////////////////////////////////
+
+type SpatialMap interface {
+	GetCell(coord Coord) (SpatialMapCell, error)
+	GetHeight() int
+	GetWidth() int
+	GetIndex(coord Coord) int
+	GetNeighbors(coord Coord) []Coord
+	GetBounds() (min, max Coord)
+	GetTopology() Topology
+	GetTerrain() *Terrain
+	removeEntity(entityId EntityId, coords ...Coord) error // Mutation functions are private
+	addEntity(entityId EntityId, coords ...Coord) error    // Renamed from setEntity
+	setTopology(topology Topology)
+	setTerrain(terrain *Terrain)
+	ValidateCoord(coord Coord) bool
+	Clone() SpatialMap
+}
+
+type spatialMap struct {
+	width    int
+	height   int
+	cells    []spatialMapCell // Stored by value so the map is cloned with a single copy
+	topology Topology         // Where steps lead, Bounded when nil
+	terrain  *Terrain         // Static ground under the entities, open when nil
+	mu       sync.RWMutex
+}
+
+func NewSpatialMap(width, height int) SpatialMap {
+	m := new(spatialMap)
+	m.width = width
+	m.height = height
+	m.cells = make([]spatialMapCell, width*height)
+	return m
+}
+
+func (m *spatialMap) GetWidth() int {
+	return m.width
+}
+
+func (m *spatialMap) GetHeight() int {
+	return m.height
+}
+
+// GetBounds returns the top left and bottom right corners of the map
+func (m *spatialMap) GetBounds() (min, max Coord) {
+	return Coord{X: 0, Y: 0}, Coord{X: m.width - 1, Y: m.height - 1}
+}
+
+func (m *spatialMap) GetIndex(coord Coord) int {
+	return coord.Y*m.width + coord.X
+}
+
+func (m *spatialMap) GetCell(coord Coord) (SpatialMapCell, error) {
+	if valid := m.ValidateCoord(coord); !valid {
+		return nil, fmt.Errorf("coordinates %s are out of bounds", coord.String())
+	}
+	index := m.GetIndex(coord)
+	return &m.cells[index], nil
+}
+
+func (m *spatialMap) ValidateCoord(coord Coord) bool {
+	// Check if the position is within bounds
+	if coord.X < 0 || coord.X >= m.GetWidth() || coord.Y < 0 || coord.Y >= m.GetHeight() {
+		return false
+	}
+	return true
+}
+
+func (m *spatialMap) addEntity(entityId EntityId, coords ...Coord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return addToCells(entityId, coords, m.GetCell)
+}
+
+func (m *spatialMap) removeEntity(entityId EntityId, coords ...Coord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return removeFromCells(entityId, coords, m.GetCell)
+}
+
+// addToCells adds the entity to the cells at coords, looking them up with
+// cellAt. Either every cell gets the entity or, on an error, none of them do.
+func addToCells(entityId EntityId, coords []Coord, cellAt func(Coord) (SpatialMapCell, error)) error {
+	var modifiedCells []SpatialMapCell
+	var cellErrs []error = nil
+	var rollbackErrs []error = nil
+	for _, coord := range coords {
+		cell, cellErr := cellAt(coord)
+		if cellErr != nil {
+			cellErrs = append(cellErrs, fmt.Errorf("error accessing cell at coordinates %s: %w", coord.String(), cellErr))
+			break
+		}
+		cellErr = cell.addEntityId(entityId)
+		if cellErr != nil {
+			cellErrs = append(cellErrs, fmt.Errorf("error adding entity %s to cell %s: %w", entityId, coord.String(), cellErr))
+			break
+		}
+		modifiedCells = append(modifiedCells, cell)
+	}
+	if cellErrs != nil {
+		for _, cell := range modifiedCells {
+			rollbackErr := cell.removeEntityId(entityId)
+			if rollbackErr != nil {
+				rollbackErrs = append(rollbackErrs, rollbackErr)
+			}
+		}
+	}
+	cellErr := errors.Join(cellErrs...)
+	rollbackErr := errors.Join(rollbackErrs...)
+	return errors.Join(cellErr, rollbackErr)
+}
+
+// removeFromCells removes the entity from the cells at coords, looking them up
+// with cellAt. Either every cell loses the entity or, on an error, none of them do.
+func removeFromCells(entityId EntityId, coords []Coord, cellAt func(Coord) (SpatialMapCell, error)) error {
+	var modifiedCells []SpatialMapCell
+	var cellErrs []error = nil
+	var rollbackErrs []error = nil
+	for _, coord := range coords {
+		cell, cellErr := cellAt(coord)
+		if cellErr != nil {
+			cellErrs = append(cellErrs, fmt.Errorf("error accessing cell at coordinates %s: %w", coord.String(), cellErr))
+			break
+		}
+		cellErr = cell.removeEntityId(entityId)
+		if cellErr != nil {
+			cellErrs = append(cellErrs, fmt.Errorf("error removing entity %s from cell %s: %w", entityId, coord.String(), cellErr))
+			break
+		}
+		modifiedCells = append(modifiedCells, cell)
+	}
+	if cellErrs != nil { // Rollback
+		for _, cell := range modifiedCells {
+			rollbackErr := cell.addEntityId(entityId)
+			if rollbackErr != nil {
+				rollbackErrs = append(rollbackErrs, rollbackErr)
+			}
+		}
+	}
+	cellErr := errors.Join(cellErrs...)
+	rollbackErr := errors.Join(rollbackErrs...)
+	return errors.Join(cellErr, rollbackErr)
+}
+
+// GetNeighbors returns the cells one step North, East, South and West of the
+// coordinates, following the topology of the map
+func (m *spatialMap) GetNeighbors(coord Coord) []Coord {
+	return neighbors(m, coord)
+}
+
+// GetTopology returns the topology the map follows, Bounded unless set
+func (m *spatialMap) GetTopology() Topology {
+	if m.topology == nil {
+		return Bounded{}
+	}
+	return m.topology
+}
+
+func (m *spatialMap) setTopology(topology Topology) {
+	m.topology = topology
+}
+
+// GetTerrain returns the static ground under the entities, nil unless set
+func (m *spatialMap) GetTerrain() *Terrain {
+	return m.terrain
+}
+
+func (m *spatialMap) setTerrain(terrain *Terrain) {
+	m.terrain = terrain
+}
+
+// neighbors returns the cells the four straight steps from the coordinates lead
+// to on a map, following its topology and leaving out blocked terrain
+func neighbors(m SpatialMap, coord Coord) []Coord {
+	topology := m.GetTopology()
+	terrain := m.GetTerrain()
+	validNeighbors := []Coord{}
+	for _, direction := range []Direction{North, East, South, West} {
+		neighbor, _, ok := topology.Step(coord, direction, m.GetWidth(), m.GetHeight())
+		if ok && m.ValidateCoord(neighbor) && !terrain.Blocked(neighbor) {
+			validNeighbors = append(validNeighbors, neighbor)
+		}
+	}
+	return validNeighbors
+}
+
+func (m *spatialMap) clone() *spatialMap {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return &spatialMap{
+		width:    m.width,
+		height:   m.height,
+		cells:    slices.Clone(m.cells), // Cells share their copy on write overflow
+		topology: m.topology,            // Topologies are never changed, so clones share them
+		terrain:  m.terrain,             // Neither is terrain once it is on a map
+	}
+}
+
+// Implementing Clone() for spatialMap to satisfy SpatialMap interface
+func (m *spatialMap) Clone() SpatialMap {
+	return m.clone()
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// ENTITY IDS
+/////////////////////////////////////////////////////////////////////////////////////
+
+// EntityId is a handle to an entity in a simulation. The low 32 bits are the
+// slot the entity is stored in and the high 32 bits are the generation of that
+// slot, which goes up every time the slot is freed. The id of a removed entity
+// keeps the old generation, so it never finds the entity that reuses the slot.
+// Ids are handed out by AddEntity and mean the same entity in every clone of
+// the simulation.
+type EntityId uint64
+
+// NilEntityId is the id of an entity that has not been added to a simulation
+const NilEntityId EntityId = 0
+
+func newEntityId(slot int, generation uint32) EntityId {
+	return EntityId(uint64(generation)<<32 | uint64(uint32(slot)))
+}
+
+func (id EntityId) slot() int {
+	return int(uint32(id))
+}
+
+func (id EntityId) generation() uint32 {
+	return uint32(id >> 32)
+}
+
+// String returns the id as slot.generation
+func (id EntityId) String() string {
+	return fmt.Sprintf("%d.%d", id.slot(), id.generation())
+}
+
+// MarshalText writes the id the same way as String
+func (id EntityId) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText reads an id written by MarshalText
+func (id *EntityId) UnmarshalText(text []byte) error {
+	var slot int
+	var generation uint32
+	if _, err := fmt.Sscanf(string(text), "%d.%d", &slot, &generation); err != nil {
+		return fmt.Errorf("invalid entity id %q: %v", text, err)
+	}
+	*id = newEntityId(slot, generation)
+	return nil
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// ENTITY
+/////////////////////////////////////////////////////////////////////////////////////
+
+// Entity is a snapshot of an entity. The simulation keeps the entities itself,
+// so an Entity does not follow the entity as it moves, get it again for that.
+type Entity interface {
+	GetId() EntityId
+	GetUUID() uuid.UUID
+	GetEntityType() string
+	GetPosition() (coords []Coord)
+	GetDirection() (direction Direction)
+	setId(id EntityId)                // Mutation functions are private
+	setPosition(coords ...Coord)      // Mutation functions are private
+	setDirection(direction Direction) // Mutation functions are private
+	Clone() Entity                    // Added Clone() method
+}
+
+type entity struct {
+	id         EntityId
+	uuid       uuid.UUID // uuid.Nil unless created with NewEntityWithUUID
+	entityType string
+	coords     []Coord // Never changed in place, setPosition replaces it
+	direction  Direction
+}
+
+// NewEntity creates an entity of a type. It gets its id when it is added to a
+// simulation.
+func NewEntity(entityType string) (Entity, error) {
+	e := new(entity)
+	e.entityType = entityType
+	e.setPosition(Coord{X: 0, Y: 0})        // Default position is the origin
+	e.setDirection(Direction{VX: 0, VY: 0}) // Default vector is zero
+	return e, nil
+}
+
+// NewEntityWithUUID creates an entity that also carries a UUID, for entities
+// that need an identity outside of their simulation. The simulation itself only
+// uses the id it hands out.
+func NewEntityWithUUID(entityType string) (Entity, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+	e, err := NewEntity(entityType)
+	if err != nil {
+		return nil, err
+	}
+	e.(*entity).uuid = id
+	return e, nil
+}
+
+func (e *entity) GetId() EntityId {
+	return e.id
+}
+
+func (e *entity) setId(id EntityId) {
+	e.id = id
+}
+
+// GetUUID returns the entity's UUID, uuid.Nil if it was created without one
+func (e *entity) GetUUID() uuid.UUID {
+	return e.uuid
+}
+
+func (e *entity) GetPosition() (coords []Coord) {
+	return slices.Clone(e.coords)
+}
+
+func (e *entity) setPosition(coords ...Coord) {
+	e.coords = slices.Clone(coords)
+}
+
+func (e *entity) GetDirection() (direction Direction) {
+	direction.VX = e.direction.VX
+	direction.VY = e.direction.VY
+	return
+}
+
+func (e *entity) setDirection(direction Direction) {
+	e.direction.VX = direction.VX
+	e.direction.VY = direction.VY
+}
+
+func (e *entity) GetEntityType() string {
+	return e.entityType
+}
+
+// Clone method for entity
+func (e *entity) clone() Entity {
+	clone := *e // The coordinates are never changed in place, so they can be shared
+	return &clone
+}
+
+// Implementing Clone() for entity to satisfy Entity interface
+func (e *entity) Clone() Entity {
+	return e.clone()
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// SIMULATION
+/////////////////////////////////////////////////////////////////////////////////////
+
+type Simulation interface {
+	AddEntity(e Entity, coords []Coord, direction Direction) (Entity, error)
+	GetEntity(entityId EntityId) (Entity, error)
+	GetEntities() []Entity
+	GetEntitiesOfType(entityType string) []Entity
+	GetEntitiesIn(rect Rect, entityTypes ...string) []Entity
+	CountPerRegion(regions []Rect, entityTypes ...string) []int
+	Nearest(from Coord, entityType string) (Entity, error)
+	MoveEntity(entityId EntityId, wrapping bool) error
+	MoveEntities(entityIds []EntityId, direction Direction) error
+	PlaceEntity(entityId EntityId, coords []Coord) error
+	Push(entityId EntityId, direction Direction, rules PushRules) (bool, error)
+	SetEntityDirection(entityId EntityId, newDirection Direction) error
+	RemoveEntity(entityId EntityId) error
+	Subscribe(observer Observer, kinds ...EventKind) (unsubscribe func())
+	GetMap() SpatialMap
+	Clone() Simulation
+	StateHash() uint64
+}
+
+// simulation stores its entities as a struct of arrays indexed by slot, so a
+// clone copies a handful of slices rather than every entity. Position slices
+// are replaced when an entity moves and never changed in place, which lets
+// clones share them.
+type simulation struct {
+	updateMutex sync.Mutex
+	spatialMap  SpatialMap
+	topology    Topology // Set by WithTopology until the map is created
+	terrain     *Terrain // Set by WithTerrain until the map is created
+
+	generations []uint32          // Generation of each slot, see EntityId
+	live        []bool            // Whether each slot holds an entity
+	types       []string          // Entity type of each slot
+	positions   [][]Coord         // Coordinates of each slot
+	directions  []Direction       // Direction of each slot
+	uuids       map[int]uuid.UUID // Slot -> UUID, only for entities that have one
+	free        []int             // Slots freed by RemoveEntity, reused by AddEntity
+	byType      map[string][]int  // Entity type -> slots, see the type index in query.go
+
+	subscriptions []*subscription // Observers of the changes, see events.go
+	pending       []Event         // Events waiting to be dispatched
+	dispatching   bool            // Whether the pending events are being dispatched
+}
+
+// Option changes how NewSimulation sets up a simulation
+type Option func(s *simulation, width, height int)
+
+// WithSparseMap backs the simulation with a sparse map, which only stores the
+// cells holding entities. A width or height of 0 leaves that axis unbounded,
+// so entities can move anywhere along it and never wrap around.
+func WithSparseMap() Option {
+	return func(s *simulation, width, height int) {
+		s.spatialMap = NewSparseSpatialMap(width, height)
+	}
+}
+
+// WithTopology sets where steps off the edge of the map lead, for MoveEntity and
+// the neighbors of cells. Maps are Bounded by default.
+func WithTopology(topology Topology) Option {
+	return func(s *simulation, width, height int) {
+		s.topology = topology
+	}
+}
+
+// NewSimulation creates a simulation on a width by height map. By default every
+// cell of the map is allocated up front, see WithSparseMap for the alternative.
+func NewSimulation(width, height int, options ...Option) Simulation {
+	s := new(simulation)
+	for _, option := range options {
+		option(s, width, height)
+	}
+	if s.spatialMap == nil {
+		s.spatialMap = NewSpatialMap(width, height)
+	}
+	if s.topology != nil {
+		s.spatialMap.setTopology(s.topology)
+		s.topology = nil // The map keeps it from here on
+	}
+	if s.terrain != nil {
+		s.spatialMap.setTerrain(s.terrain)
+		s.terrain = nil
+	}
+	return s
+}
+
+func (s *simulation) GetMap() SpatialMap {
+	return s.spatialMap
+}
+
+// slotOf returns the slot of the entity, or an error if the id does not belong
+// to a live entity
+func (s *simulation) slotOf(entityId EntityId) (int, error) {
+	slot := entityId.slot()
+	if entityId == NilEntityId || slot >= len(s.live) || !s.live[slot] || s.generations[slot] != entityId.generation() {
+		return 0, fmt.Errorf("entity %s not found", entityId)
+	}
+	return slot, nil
+}
+
+// entityAt returns a snapshot of the entity in a slot
+func (s *simulation) entityAt(slot int) Entity {
+	return &entity{
+		id:         newEntityId(slot, s.generations[slot]),
+		uuid:       s.uuids[slot],
+		entityType: s.types[slot],
+		coords:     s.positions[slot],
+		direction:  s.directions[slot],
+	}
+}
+
+func (s *simulation) GetEntity(entityId EntityId) (Entity, error) {
+	slot, err := s.slotOf(entityId)
+	if err != nil {
+		return nil, err
+	}
+	return s.entityAt(slot), nil
+}
+
+// GetEntities returns a snapshot of every entity, in slot order
+func (s *simulation) GetEntities() []Entity {
+	s.updateMutex.Lock()
+	defer s.updateMutex.Unlock()
+	entities := make([]Entity, 0, len(s.live)-len(s.free))
+	for slot, live := range s.live {
+		if live {
+			entities = append(entities, s.entityAt(slot))
+		}
+	}
+	return entities
+}
+
+// AddEntity adds the entity to the simulation and gives it its id. The entity
+// passed in is updated with its id, position and direction and returned.
+func (s *simulation) AddEntity(e Entity, coords []Coord, direction Direction) (Entity, error) {
+	defer s.dispatch()
+	// Lock the mutex to ensure thread safety when adding entities
+	s.updateMutex.Lock()
+	defer s.updateMutex.Unlock()
+
+	// Validate the coordinates before adding the entity
+	terrain := s.spatialMap.GetTerrain()
+	for _, coord := range coords {
+		if valid := s.spatialMap.ValidateCoord(coord); !valid {
+			return nil, fmt.Errorf("invalid coordinates %s for new entity", coord.String())
+		}
+		if terrain.Blocked(coord) {
+			return nil, fmt.Errorf("can not add entity onto blocked terrain at %s", coord.String())
+		}
+	}
+
+	// Reuse a freed slot if there is one, the generation was bumped when it was freed
+	slot, generation := len(s.live), uint32(1)
+	if n := len(s.free); n > 0 {
+		slot = s.free[n-1]
+		generation = s.generations[slot]
+	}
+	id := newEntityId(slot, generation)
+
+	// Add the entity to the spatial map at the specified coordinates
+	err := s.spatialMap.addEntity(id, coords...)
+	if err != nil {
+		var coordStr string
+		for _, coord := range coords {
+			coordStr += coord.String()
+		}
+		return nil, fmt.Errorf("failed to add entity at coordinates %s", coordStr)
+	}
+
+	// Store the entity in its slot
+	if slot == len(s.live) {
+		s.generations = append(s.generations, generation)
+		s.live = append(s.live, true)
+		s.types = append(s.types, e.GetEntityType())
+		s.positions = append(s.positions, slices.Clone(coords))
+		s.directions = append(s.directions, direction)
+	} else {
+		s.free = s.free[:len(s.free)-1]
+		s.live[slot] = true
+		s.types[slot] = e.GetEntityType()
+		s.positions[slot] = slices.Clone(coords)
+		s.directions[slot] = direction
+	}
+	s.indexType(e.GetEntityType(), slot)
+	if u := e.GetUUID(); u != uuid.Nil {
+		if s.uuids == nil {
+			s.uuids = make(map[int]uuid.UUID)
+		}
+		s.uuids[slot] = u
+	}
+
+	if s.observed() {
+		s.emit(Event{Kind: Spawned, EntityId: id, EntityType: s.types[slot], To: s.positions[slot], Direction: direction})
+	}
+
+	e.setId(id)
+	e.setPosition(coords...)
+	e.setDirection(direction)
+	return e, nil
+}
+
+func (s *simulation) RemoveEntity(entityId EntityId) error {
+	defer s.dispatch()
+	// Lock the mutex to ensure thread safety when removing entities
+	s.updateMutex.Lock()
+	defer s.updateMutex.Unlock()
+
+	slot, err := s.slotOf(entityId)
+	if err != nil {
+		return err
+	}
+
+	// Remove the entity from the spatial map
+	err = s.spatialMap.removeEntity(entityId, s.positions[slot]...)
+	if err != nil {
+		return fmt.Errorf("error removing entity from spatial map: %v", err)
+	}
+
+	if s.observed() {
+		s.emit(Event{Kind: Despawned, EntityId: entityId, EntityType: s.types[slot], From: s.positions[slot], Direction: s.directions[slot]})
+	}
+
+	// Free the slot, the new generation stops the old id from finding whatever reuses it
+	s.freeSlot(slot, s.generations[slot]+1)
+
+	return nil
+}
+
+// freeSlot empties a slot for AddEntity to reuse with the given generation. The
+// caller holds the update lock and has taken the entity off the map.
+func (s *simulation) freeSlot(slot int, generation uint32) {
+	s.unindexType(s.types[slot], slot)
+	s.live[slot] = false
+	s.generations[slot] = generation
+	s.types[slot] = ""
+	s.positions[slot] = nil
+	s.directions[slot] = Direction{}
+	delete(s.uuids, slot)
+	s.free = append(s.free, slot)
+}
+
+// Move the entity based on the entity location and direction. Where it ends up
+// is up to the topology of the map, see WithTopology.
+func (s *simulation) MoveEntity(entityId EntityId, wrapping bool) error {
+	defer s.dispatch()
+	// Lock the mutex to ensure thread safety when moving entities
+	s.updateMutex.Lock()
+	defer s.updateMutex.Unlock()
+
+	// Get the map's dimensions
+	width := s.spatialMap.GetWidth()
+	height := s.spatialMap.GetHeight()
+
+	slot, err := s.slotOf(entityId)
+	if err != nil {
+		return fmt.Errorf("error accessing entity %s", entityId.String())
+	}
+
+	// Get the current position of the entity
+	currentCoords := s.positions[slot]
+	direction := s.directions[slot]
+
+	// The topology of the map decides where each cell ends up and which way the
+	// entity faces afterwards, going by its first cell. Wrapping makes entities
+	// that leave the map re-enter on the other side whatever the topology.
+	// Unbounded axes of a sparse map have no other side to wrap to.
+	topology := s.spatialMap.GetTopology()
+	if wrapping {
+		topology = Torus{}
+	}
+	terrain := s.spatialMap.GetTerrain()
+	newCoords := make([]Coord, len(currentCoords))
+	facing := direction
+	wrapped := false
+	for i, coord := range currentCoords {
+		newCoord, newFacing, ok := topology.Step(coord, direction, width, height)
+
+		// Validate the new coordinates
+		if !ok || !s.spatialMap.ValidateCoord(newCoord) {
+			if s.observed() {
+				s.emitBlocked(slot, direction, NilEntityId)
+			}
+			return BlockedError{At: newCoord}
+		}
+		if terrain.Blocked(newCoord) {
+			if s.observed() {
+				s.emitBlocked(slot, direction, NilEntityId)
+			}
+			return BlockedError{At: newCoord, Terrain: true}
+		}
+		if i == 0 {
+			facing = newFacing
+		}
+		wrapped = wrapped || newCoord != coord.Move(direction)
+		newCoords[i] = newCoord
+	}
+
+	// Remove the entity from its current cell
+	err = s.spatialMap.removeEntity(entityId, currentCoords...)
+	if err != nil {
+		return fmt.Errorf("failed to remove entity from current location: %v", err)
+	}
+
+	// Add the entity to the new cell
+	err = s.spatialMap.addEntity(entityId, newCoords...)
+	if err != nil {
+		// Attempt to re-add the entity to its original cell in case of failure
+		rollbackErr := s.spatialMap.addEntity(entityId, currentCoords...)
+		if rollbackErr != nil {
+			return fmt.Errorf("failed to move entity and failed to rollback: %v", rollbackErr)
+		}
+		return fmt.Errorf("failed to move entity and successfully rolled back")
+	}
+
+	// Update the entity's position, replacing the slice as clones may share it
+	s.positions[slot] = newCoords
+	s.directions[slot] = facing
+
+	if s.observed() {
+		s.emitMoves([]int{slot}, [][]Coord{currentCoords}, [][]Coord{newCoords}, direction)
+		if wrapped {
+			s.emit(Event{Kind: Wrapped, EntityId: entityId, EntityType: s.types[slot], From: currentCoords, To: newCoords, Direction: direction})
+		}
+		if facing != direction {
+			s.emit(Event{Kind: Turned, EntityId: entityId, EntityType: s.types[slot], From: newCoords, Direction: facing, Previous: direction})
+		}
+	}
+
+	return nil
+}
+
+// PlaceEntity moves an entity straight to the coordinates, one for each of its
+// cells, whatever its direction and the topology of the map. It makes a single
+// Moved event and leaves the direction alone, for callers that work out
+// destinations themselves.
+func (s *simulation) PlaceEntity(entityId EntityId, coords []Coord) error {
+	defer s.dispatch()
+	s.updateMutex.Lock()
+	defer s.updateMutex.Unlock()
+
+	slot, err := s.slotOf(entityId)
+	if err != nil {
+		return err
+	}
+	if len(coords) != len(s.positions[slot]) {
+		return fmt.Errorf("entity %s has %d cells, got %d coordinates", entityId, len(s.positions[slot]), len(coords))
+	}
+	terrain := s.spatialMap.GetTerrain()
+	for _, coord := range coords {
+		if terrain.Blocked(coord) {
+			return fmt.Errorf("can not place entity %s onto blocked terrain at %s", entityId, coord.String())
+		}
+	}
+
+	oldCoords := s.positions[slot]
+	if err := s.place(entityId, coords); err != nil {
+		return err
+	}
+	if s.observed() {
+		s.emitMoves([]int{slot}, [][]Coord{oldCoords}, [][]Coord{s.positions[slot]}, s.directions[slot])
+	}
+	return nil
+}
+
+func (s *simulation) SetEntityDirection(entityId EntityId, newDirection Direction) error {
+	defer s.dispatch()
+	// Lock the mutex to ensure thread safety when moving entities
+	s.updateMutex.Lock()
+	defer s.updateMutex.Unlock()
+
+	slot, err := s.slotOf(entityId)
+	if err != nil {
+		return err
+	}
+
+	// Update the entity's vector
+	previous := s.directions[slot]
+	s.directions[slot] = newDirection
+
+	if s.observed() {
+		s.emit(Event{Kind: Turned, EntityId: entityId, EntityType: s.types[slot], From: s.positions[slot], Direction: newDirection, Previous: previous})
+	}
+
+	return nil
+}
+
+// Clone method for simulation
+func (s *simulation) clone() Simulation {
+	s.updateMutex.Lock()
+	defer s.updateMutex.Unlock()
+
+	// Copy the slot arrays, the position slices inside are shared
+	return &simulation{
+		spatialMap:  s.spatialMap.Clone(),
+		generations: slices.Clone(s.generations),
+		live:        slices.Clone(s.live),
+		types:       slices.Clone(s.types),
+		positions:   slices.Clone(s.positions),
+		directions:  slices.Clone(s.directions),
+		uuids:       maps.Clone(s.uuids),
+		free:        slices.Clone(s.free),
+		byType:      s.cloneTypeIndex(),
+	}
+}
+
+// Implementing Clone() for simulation to satisfy Simulation interface
+func (s *simulation) Clone() Simulation {
+	return s.clone()
+}
+
+// StateHash returns a hash of everything that determines how the simulation
+// plays out: the size of the map and the type, position and direction of every
+// entity. Entity IDs and the order entities were added in are left out, so two
+// simulations holding the same kinds of entities in the same places hash the
+// same. That makes it usable as the key when looking for cycles.
+func (s *simulation) StateHash() uint64 {
+	s.updateMutex.Lock()
+	defer s.updateMutex.Unlock()
+
+	// Hash every entity on its own, then combine the hashes in sorted order
+	entityHashes := make([]uint64, 0, len(s.live)-len(s.free))
+	for slot, live := range s.live {
+		if live {
+			entityHashes = append(entityHashes, hashEntity(s.types[slot], s.directions[slot], s.positions[slot]))
+		}
+	}
+	slices.Sort(entityHashes)
+
+	h := fnv.New64a()
+	writeInts(h, s.spatialMap.GetWidth(), s.spatialMap.GetHeight())
+	for _, eh := range entityHashes {
+		binary.Write(h, binary.LittleEndian, eh)
+	}
+	return h.Sum64()
+}
+
+func hashEntity(entityType string, direction Direction, coords []Coord) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(entityType))
+	h.Write([]byte{0}) // Keeps the type apart from the numbers that follow
+	writeInts(h, direction.VX, direction.VY)
+	for _, coord := range coords {
+		writeInts(h, coord.X, coord.Y)
+	}
+	return h.Sum64()
+}
+
+func writeInts(w io.Writer, values ...int) {
+	for _, v := range values {
+		binary.Write(w, binary.LittleEndian, int64(v))
+	}
+}
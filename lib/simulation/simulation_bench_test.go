@@ -0,0 +1,60 @@
+package simulation
+
+import (
+	"testing"
+)
+
+// benchSimulation fills a size by size map with an entity on every fourth cell,
+// about as crowded as the maps of 2024 days 14 to 20
+func benchSimulation(b *testing.B, size int) Simulation {
+	b.Helper()
+	sim := NewSimulation(size, size)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x += 4 {
+			e, err := NewEntity("robot")
+			if err != nil {
+				b.Fatalf("Failed to create entity: %v", err)
+			}
+			if _, err := sim.AddEntity(e, []Coord{{X: x, Y: y}}, East); err != nil {
+				b.Fatalf("Failed to add entity: %v", err)
+			}
+		}
+	}
+	return sim
+}
+
+func BenchmarkClone(b *testing.B) {
+	sim := benchSimulation(b, 100)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sim.Clone()
+	}
+}
+
+func BenchmarkMoveEntity(b *testing.B) {
+	sim := benchSimulation(b, 100)
+	entities := sim.GetEntities()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := sim.MoveEntity(entities[i%len(entities)].GetId(), true); err != nil {
+			b.Fatalf("Failed to move entity: %v", err)
+		}
+	}
+}
+
+func BenchmarkCloneAndTick(b *testing.B) {
+	sim := benchSimulation(b, 100)
+	entities := sim.GetEntities()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		next := sim.Clone()
+		for _, e := range entities {
+			if err := next.MoveEntity(e.GetId(), true); err != nil {
+				b.Fatalf("Failed to move entity: %v", err)
+			}
+		}
+	}
+}
@@ -8,6 +8,36 @@ import (
 	"github.com/google/uuid"
 )
 
+// backend is a SpatialMap implementation the tests run against
+type backend struct {
+	name          string
+	newSpatialMap func(width, height int) SpatialMap
+	newSimulation func(width, height int) Simulation
+	options       []Option // Passed to NewSimulation to pick the backend
+}
+
+// Every map and simulation test runs on the dense map and on a sparse map
+// bounded to the same size
+var backends = []backend{
+	{
+		name:          "dense",
+		newSpatialMap: NewSpatialMap,
+		newSimulation: func(width, height int) Simulation { return NewSimulation(width, height) },
+	},
+	{
+		name:          "sparse",
+		newSpatialMap: NewSparseSpatialMap,
+		newSimulation: func(width, height int) Simulation { return NewSimulation(width, height, WithSparseMap()) },
+		options:       []Option{WithSparseMap()},
+	},
+}
+
+func forEachBackend(t *testing.T, test func(t *testing.T, b backend)) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) { test(t, b) })
+	}
+}
+
 func TestDirectionString(t *testing.T) {
 	tests := []struct {
 		direction Direction
@@ -66,18 +96,55 @@ func TestDirectionTurnRight(t *testing.T) {
 	}
 }
 
+func TestDirectionRotate(t *testing.T) {
+	northEast := Direction{VX: 1, VY: -1}
+	tests := []struct {
+		direction    Direction
+		quarterTurns int
+		expected     Direction
+	}{
+		{North, 2, South},
+		{East, -1, North},
+		{West, 5, North},
+		{northEast, 1, Direction{VX: 1, VY: 1}},
+		{northEast, -1, Direction{VX: -1, VY: -1}},
+	}
+
+	for _, test := range tests {
+		result := test.direction.Rotate(test.quarterTurns)
+		if result != test.expected {
+			t.Errorf("Expected %v, but got %v", test.expected, result)
+		}
+	}
+
+	// Diagonals can be turned too
+	if result := northEast.TurnRight().TurnRight(); result != (Direction{VX: -1, VY: 1}) {
+		t.Errorf("Expected a diagonal to turn, but got %v", result)
+	}
+}
+
+func TestVecConversions(t *testing.T) {
+	coord := Coord{X: 3, Y: -4}
+	if result := CoordFromVec(coord.Vec()); result != coord {
+		t.Errorf("Expected %v, but got %v", coord, result)
+	}
+	if result := DirectionFromVec(West.Vec()); result != West {
+		t.Errorf("Expected %v, but got %v", West, result)
+	}
+
+	// Moving by a direction is adding its vector
+	moved := coord.Move(South)
+	if result := CoordFromVec(coord.Vec().Add(South.Vec())); result != moved {
+		t.Errorf("Expected %v, but got %v", moved, result)
+	}
+}
+
 func TestSpatialMapCellClone(t *testing.T) {
 	originalCell := NewSpatialMapCell()
 
 	// Add valid entities
-	entity1, err := uuid.NewV7()
-	if err != nil {
-		t.Error("failed to create new uuid v7")
-	}
-	entity2, err := uuid.NewV7()
-	if err != nil {
-		t.Error("failed to create new uuid v7")
-	}
+	entity1 := newEntityId(1, 1)
+	entity2 := newEntityId(2, 1)
 
 	if err := originalCell.addEntityId(entity1); err != nil {
 		t.Fatalf("Failed to add entity1: %v", err)
@@ -90,10 +157,7 @@ func TestSpatialMapCellClone(t *testing.T) {
 	clonedCell := originalCell.Clone()
 
 	// Add a new entity to the cloned cell
-	entity3, err := uuid.NewV7()
-	if err != nil {
-		t.Error("failed to create new uuid v7")
-	}
+	entity3 := newEntityId(3, 1)
 	if err := clonedCell.addEntityId(entity3); err != nil {
 		t.Fatalf("Failed to add entity3 to cloned cell: %v", err)
 	}
@@ -121,15 +185,13 @@ func TestSpatialMapCellClone(t *testing.T) {
 }
 
 func TestSpatialMapCloneIntegrity(t *testing.T) {
-	sm := NewSpatialMap(10, 10)
-	entityId1, err := uuid.NewV7()
-	if err != nil {
-		t.Error("failed to create new uuid v7")
-	}
-	entityId2, err := uuid.NewV7()
-	if err != nil {
-		t.Error("failed to create new uuid v7")
-	}
+	forEachBackend(t, testSpatialMapCloneIntegrity)
+}
+
+func testSpatialMapCloneIntegrity(t *testing.T, b backend) {
+	sm := b.newSpatialMap(10, 10)
+	entityId1 := newEntityId(4, 1)
+	entityId2 := newEntityId(5, 1)
 	firstCoord := Coord{X: 5, Y: 5}
 
 	if err := sm.addEntity(entityId1, firstCoord); err != nil {
@@ -141,10 +203,7 @@ func TestSpatialMapCloneIntegrity(t *testing.T) {
 
 	smClone := sm.Clone()
 
-	newEntityId, err := uuid.NewV7()
-	if err != nil {
-		t.Error("failed to create new uuid v7")
-	}
+	newEntityId := newEntityId(6, 1)
 	secondCoord := Coord{X: 6, Y: 6}
 	if err := smClone.addEntity(newEntityId, secondCoord); err != nil {
 		t.Fatalf("Failed to add newEntityId to cloned map: %v", err)
@@ -170,32 +229,28 @@ func TestSpatialMapCloneIntegrity(t *testing.T) {
 func TestAddAndRemoveEntityId(t *testing.T) {
 	cell := NewSpatialMapCell()
 
-	// Test adding a zero-value UUID
-	zeroUUID := uuid.UUID{}
-	err := cell.addEntityId(zeroUUID)
+	// Test adding the nil entity id
+	err := cell.addEntityId(NilEntityId)
 	if err == nil {
-		t.Fatalf("Expected an error when adding a zero-value UUID, got none")
+		t.Fatalf("Expected an error when adding the nil entity id, got none")
 	}
 
-	// Test adding and removing a valid UUID
-	entityId, err := uuid.NewV7()
-	if err != nil {
-		t.Error("failed to create new uuid v7")
-	}
+	// Test adding and removing a valid entity id
+	entityId := newEntityId(7, 1)
 	err = cell.addEntityId(entityId)
 	if err != nil {
-		t.Fatalf("Failed to add a valid UUID: %v", err)
+		t.Fatalf("Failed to add a valid entity id: %v", err)
 	}
 
 	err = cell.removeEntityId(entityId)
 	if err != nil {
-		t.Fatalf("Failed to remove a valid UUID: %v", err)
+		t.Fatalf("Failed to remove a valid entity id: %v", err)
 	}
 
-	// Test removing a UUID not in the cell
+	// Test removing an entity id not in the cell
 	err = cell.removeEntityId(entityId)
 	if err == nil {
-		t.Fatalf("Expected an error when removing a non-existent UUID, got none")
+		t.Fatalf("Expected an error when removing a non-existent entity id, got none")
 	}
 }
 
@@ -206,11 +261,8 @@ func TestIsEmptyAndGetEntityIds(t *testing.T) {
 		t.Fatalf("Expected cell to be empty, but it was not")
 	}
 
-	entityId, err := uuid.NewV7()
-	if err != nil {
-		t.Error("failed to create new uuid v7")
-	}
-	err = cell.addEntityId(entityId)
+	entityId := newEntityId(8, 1)
+	err := cell.addEntityId(entityId)
 	if err != nil {
 		t.Fatalf("Failed to add entityId: %v", err)
 	}
@@ -226,7 +278,11 @@ func TestIsEmptyAndGetEntityIds(t *testing.T) {
 }
 
 func TestSpatialMapValidateCoord(t *testing.T) {
-	sm := NewSpatialMap(10, 10)
+	forEachBackend(t, testSpatialMapValidateCoord)
+}
+
+func testSpatialMapValidateCoord(t *testing.T, b backend) {
+	sm := b.newSpatialMap(10, 10)
 
 	tests := []struct {
 		coord   Coord
@@ -248,7 +304,11 @@ func TestSpatialMapValidateCoord(t *testing.T) {
 }
 
 func TestSimulationAddAndRemoveEntity(t *testing.T) {
-	sim := NewSimulation(10, 10)
+	forEachBackend(t, testSimulationAddAndRemoveEntity)
+}
+
+func testSimulationAddAndRemoveEntity(t *testing.T, b backend) {
+	sim := b.newSimulation(10, 10)
 
 	entity, err := NewEntity("test_entity")
 	if err != nil {
@@ -282,7 +342,11 @@ func TestSimulationAddAndRemoveEntity(t *testing.T) {
 }
 
 func TestSimulationMoveEntityWithWrapping(t *testing.T) {
-	sim := NewSimulation(10, 10)
+	forEachBackend(t, testSimulationMoveEntityWithWrapping)
+}
+
+func testSimulationMoveEntityWithWrapping(t *testing.T, b backend) {
+	sim := b.newSimulation(10, 10)
 
 	entity, err := NewEntity("test_entity")
 	if err != nil {
@@ -298,7 +362,12 @@ func TestSimulationMoveEntityWithWrapping(t *testing.T) {
 		t.Fatalf("Failed to move entity with wrapping: %v", err)
 	}
 
-	coords := entity.GetPosition()
+	// Entities are snapshots, get it again to see where it moved to
+	moved, err := sim.GetEntity(entity.GetId())
+	if err != nil {
+		t.Fatalf("Failed to get entity after moving: %v", err)
+	}
+	coords := moved.GetPosition()
 	if len(coords) == 0 {
 		t.Fatalf("Entity has no position after moving")
 	}
@@ -315,7 +384,11 @@ func TestSimulationMoveEntityWithWrapping(t *testing.T) {
 		}
 	}
 
-	coords = entity.GetPosition()
+	moved, err = sim.GetEntity(entity.GetId())
+	if err != nil {
+		t.Fatalf("Failed to get entity after moving: %v", err)
+	}
+	coords = moved.GetPosition()
 	if len(coords) == 0 {
 		t.Fatalf("Entity has no position after moving multiple times")
 	}
@@ -328,7 +401,11 @@ func TestSimulationMoveEntityWithWrapping(t *testing.T) {
 }
 
 func TestSimulationClone(t *testing.T) {
-	sim := NewSimulation(10, 10)
+	forEachBackend(t, testSimulationClone)
+}
+
+func testSimulationClone(t *testing.T, b backend) {
+	sim := b.newSimulation(10, 10)
 
 	entity, err := NewEntity("test_entity")
 	if err != nil {
@@ -368,7 +445,11 @@ func TestSimulationClone(t *testing.T) {
 }
 
 func TestAddMultiCellEntity(t *testing.T) {
-	sim := NewSimulation(10, 10)
+	forEachBackend(t, testAddMultiCellEntity)
+}
+
+func testAddMultiCellEntity(t *testing.T, b backend) {
+	sim := b.newSimulation(10, 10)
 
 	entity, err := NewEntity("multi_cell_entity")
 	if err != nil {
@@ -400,7 +481,11 @@ func TestAddMultiCellEntity(t *testing.T) {
 }
 
 func TestMoveMultiCellEntity(t *testing.T) {
-	sim := NewSimulation(10, 10)
+	forEachBackend(t, testMoveMultiCellEntity)
+}
+
+func testMoveMultiCellEntity(t *testing.T, b backend) {
+	sim := b.newSimulation(10, 10)
 
 	entity, err := NewEntity("multi_cell_entity")
 	if err != nil {
@@ -458,7 +543,11 @@ func TestMoveMultiCellEntity(t *testing.T) {
 }
 
 func TestRemoveMultiCellEntity(t *testing.T) {
-	sim := NewSimulation(10, 10)
+	forEachBackend(t, testRemoveMultiCellEntity)
+}
+
+func testRemoveMultiCellEntity(t *testing.T, b backend) {
+	sim := b.newSimulation(10, 10)
 
 	entity, err := NewEntity("multi_cell_entity")
 	if err != nil {
@@ -495,7 +584,11 @@ func TestRemoveMultiCellEntity(t *testing.T) {
 }
 
 func TestAddMultiCellEntityWithInvalidCoords(t *testing.T) {
-	sim := NewSimulation(10, 10)
+	forEachBackend(t, testAddMultiCellEntityWithInvalidCoords)
+}
+
+func testAddMultiCellEntityWithInvalidCoords(t *testing.T, b backend) {
+	sim := b.newSimulation(10, 10)
 
 	entity, err := NewEntity("multi_cell_entity")
 	if err != nil {
@@ -527,7 +620,11 @@ func TestAddMultiCellEntityWithInvalidCoords(t *testing.T) {
 	}
 }
 func TestGetNeighbors(t *testing.T) {
-	sm := NewSpatialMap(10, 10)
+	forEachBackend(t, testGetNeighbors)
+}
+
+func testGetNeighbors(t *testing.T, b backend) {
+	sm := b.newSpatialMap(10, 10)
 
 	tests := []struct {
 		coord    Coord
@@ -608,3 +705,139 @@ func TestDirectionTo(t *testing.T) {
 		}
 	}
 }
+
+func TestStateHash(t *testing.T) {
+	forEachBackend(t, testStateHash)
+}
+
+func testStateHash(t *testing.T, b backend) {
+	type placement struct {
+		entityType string
+		coord      Coord
+		direction  Direction
+	}
+	build := func(placements ...placement) Simulation {
+		sim := b.newSimulation(4, 4)
+		for _, p := range placements {
+			e, err := NewEntity(p.entityType)
+			if err != nil {
+				t.Fatalf("Failed to create entity: %v", err)
+			}
+			if _, err := sim.AddEntity(e, []Coord{p.coord}, p.direction); err != nil {
+				t.Fatalf("Failed to add entity: %v", err)
+			}
+		}
+		return sim
+	}
+	robot := placement{"robot", Coord{X: 1, Y: 1}, East}
+	wall := placement{"wall", Coord{X: 2, Y: 1}, Direction{}}
+
+	tests := []struct {
+		name  string
+		a, b  Simulation
+		equal bool
+	}{
+		{name: "Same entities, new IDs", a: build(robot, wall), b: build(robot, wall), equal: true},
+		{name: "Added in another order", a: build(robot, wall), b: build(wall, robot), equal: true},
+		{name: "Moved", a: build(robot, wall), b: build(placement{"robot", Coord{X: 0, Y: 1}, East}, wall)},
+		{name: "Turned", a: build(robot, wall), b: build(placement{"robot", Coord{X: 1, Y: 1}, West}, wall)},
+		{name: "Other type", a: build(robot, wall), b: build(robot, placement{"box", Coord{X: 2, Y: 1}, Direction{}})},
+		{name: "Missing entity", a: build(robot, wall), b: build(robot)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if equal := test.a.StateHash() == test.b.StateHash(); equal != test.equal {
+				t.Errorf("Expected hashes to be equal: %v, but got %v", test.equal, equal)
+			}
+		})
+	}
+
+	// A clone is the same state
+	sim := build(robot, wall)
+	if sim.Clone().StateHash() != sim.StateHash() {
+		t.Errorf("Expected a clone to hash the same")
+	}
+}
+
+func TestEntityIdGenerations(t *testing.T) {
+	forEachBackend(t, testEntityIdGenerations)
+}
+
+func testEntityIdGenerations(t *testing.T, b backend) {
+	sim := b.newSimulation(5, 5)
+	add := func(entityType string, coord Coord) Entity {
+		e, err := NewEntity(entityType)
+		if err != nil {
+			t.Fatalf("Failed to create entity: %v", err)
+		}
+		if e.GetId() != NilEntityId {
+			t.Fatalf("Expected a new entity to have the nil id, got %s", e.GetId())
+		}
+		if _, err := sim.AddEntity(e, []Coord{coord}, North); err != nil {
+			t.Fatalf("Failed to add entity: %v", err)
+		}
+		return e
+	}
+
+	first := add("first", Coord{X: 0, Y: 0})
+	clone := sim.Clone()
+	if err := sim.RemoveEntity(first.GetId()); err != nil {
+		t.Fatalf("Failed to remove entity: %v", err)
+	}
+
+	// The next entity reuses the slot under a new generation
+	second := add("second", Coord{X: 1, Y: 1})
+	if second.GetId().slot() != first.GetId().slot() {
+		t.Errorf("Expected the freed slot to be reused, got %s after %s", second.GetId(), first.GetId())
+	}
+	if second.GetId() == first.GetId() {
+		t.Errorf("Expected a new id for the entity in the reused slot")
+	}
+	if _, err := sim.GetEntity(first.GetId()); err == nil {
+		t.Errorf("Expected the removed entity's id to find nothing")
+	}
+	if err := sim.MoveEntity(first.GetId(), true); err == nil {
+		t.Errorf("Expected moving a removed entity to fail")
+	}
+
+	// The clone was taken before the removal and still has the first entity
+	e, err := clone.GetEntity(first.GetId())
+	if err != nil {
+		t.Fatalf("Expected the clone to keep the first entity: %v", err)
+	}
+	if e.GetEntityType() != "first" {
+		t.Errorf("Expected the first entity in the clone, got %s", e.GetEntityType())
+	}
+	if _, err := clone.GetEntity(second.GetId()); err == nil {
+		t.Errorf("Expected the clone not to know the second entity")
+	}
+}
+
+func TestEntityUUID(t *testing.T) {
+	sim := NewSimulation(3, 3)
+	plain, err := NewEntity("plain")
+	if err != nil {
+		t.Fatalf("Failed to create entity: %v", err)
+	}
+	tagged, err := NewEntityWithUUID("tagged")
+	if err != nil {
+		t.Fatalf("Failed to create entity: %v", err)
+	}
+	for i, e := range []Entity{plain, tagged} {
+		if _, err := sim.AddEntity(e, []Coord{{X: i, Y: 0}}, North); err != nil {
+			t.Fatalf("Failed to add entity: %v", err)
+		}
+	}
+
+	got, err := sim.Clone().GetEntity(tagged.GetId())
+	if err != nil {
+		t.Fatalf("Failed to get entity: %v", err)
+	}
+	if got.GetUUID() == uuid.Nil || got.GetUUID() != tagged.GetUUID() {
+		t.Errorf("Expected the UUID %s to be kept, got %s", tagged.GetUUID(), got.GetUUID())
+	}
+	if got, _ := sim.GetEntity(plain.GetId()); got.GetUUID() != uuid.Nil {
+		t.Errorf("Expected no UUID on a plain entity, got %s", got.GetUUID())
+	}
+}
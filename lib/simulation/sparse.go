@@ -0,0 +1,195 @@
+package simulation
+
+import (
+	"fmt"
+	"sync"
+)
+
+/////////////////////////////////////////////////////////////////////////////////////
+// SPARSE SPATIAL MAP
+/////////////////////////////////////////////////////////////////////////////////////
+
+// sparseSpatialMap only stores the cells that hold entities, so its size is set
+// by the number of entities rather than by its area. An axis with a size of 0
+// is unbounded and grows in both directions as entities move along it.
+type sparseSpatialMap struct {
+	width    int // 0 when unbounded
+	height   int // 0 when unbounded
+	cells    map[Coord]SpatialMapCell
+	topology Topology // Where steps lead, Bounded when nil
+	terrain  *Terrain // Static ground under the entities, open when nil
+	mu       sync.RWMutex
+}
+
+// NewSparseSpatialMap creates a map that only stores occupied cells. A width or
+// height of 0 leaves that axis unbounded.
+func NewSparseSpatialMap(width, height int) SpatialMap {
+	return &sparseSpatialMap{
+		width:  width,
+		height: height,
+		cells:  make(map[Coord]SpatialMapCell),
+	}
+}
+
+// GetWidth returns the width the map was created with, 0 if it is unbounded.
+// GetBounds gives the area in use.
+func (m *sparseSpatialMap) GetWidth() int {
+	return m.width
+}
+
+// GetHeight returns the height the map was created with, 0 if it is unbounded.
+// GetBounds gives the area in use.
+func (m *sparseSpatialMap) GetHeight() int {
+	return m.height
+}
+
+// GetIndex returns the index the cell would have in a dense map of the same
+// size. Without a width there is no such index and it returns -1.
+func (m *sparseSpatialMap) GetIndex(coord Coord) int {
+	if m.width == 0 {
+		return -1
+	}
+	return coord.Y*m.width + coord.X
+}
+
+// GetBounds returns the top left and bottom right corners of the smallest
+// rectangle holding every entity. For an empty map max is left of and above
+// min.
+func (m *sparseSpatialMap) GetBounds() (min, max Coord) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if len(m.cells) == 0 {
+		return Coord{X: 0, Y: 0}, Coord{X: -1, Y: -1}
+	}
+	first := true
+	for coord := range m.cells {
+		if first {
+			min, max = coord, coord
+			first = false
+			continue
+		}
+		if coord.X < min.X {
+			min.X = coord.X
+		}
+		if coord.Y < min.Y {
+			min.Y = coord.Y
+		}
+		if coord.X > max.X {
+			max.X = coord.X
+		}
+		if coord.Y > max.Y {
+			max.Y = coord.Y
+		}
+	}
+	return min, max
+}
+
+func (m *sparseSpatialMap) ValidateCoord(coord Coord) bool {
+	// Only the bounded axes limit the coordinates
+	if m.width > 0 && (coord.X < 0 || coord.X >= m.width) {
+		return false
+	}
+	if m.height > 0 && (coord.Y < 0 || coord.Y >= m.height) {
+		return false
+	}
+	return true
+}
+
+// GetCell returns the cell at the coordinates. Cells without entities are not
+// stored, for those an empty cell is returned.
+func (m *sparseSpatialMap) GetCell(coord Coord) (SpatialMapCell, error) {
+	if valid := m.ValidateCoord(coord); !valid {
+		return nil, fmt.Errorf("coordinates %s are out of bounds", coord.String())
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if cell, ok := m.cells[coord]; ok {
+		return cell, nil
+	}
+	return NewSpatialMapCell(), nil
+}
+
+// cellForUpdate returns the stored cell at the coordinates, storing a new one
+// if there is none yet. The caller holds the write lock.
+func (m *sparseSpatialMap) cellForUpdate(coord Coord) (SpatialMapCell, error) {
+	if valid := m.ValidateCoord(coord); !valid {
+		return nil, fmt.Errorf("coordinates %s are out of bounds", coord.String())
+	}
+	cell, ok := m.cells[coord]
+	if !ok {
+		cell = NewSpatialMapCell()
+		m.cells[coord] = cell
+	}
+	return cell, nil
+}
+
+// prune drops the cells at coords that no longer hold any entities.
+// The caller holds the write lock.
+func (m *sparseSpatialMap) prune(coords []Coord) {
+	for _, coord := range coords {
+		if cell, ok := m.cells[coord]; ok && cell.IsEmpty() {
+			delete(m.cells, coord)
+		}
+	}
+}
+
+func (m *sparseSpatialMap) addEntity(entityId EntityId, coords ...Coord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	err := addToCells(entityId, coords, m.cellForUpdate)
+	m.prune(coords) // Drops the cells created for an add that was rolled back
+	return err
+}
+
+func (m *sparseSpatialMap) removeEntity(entityId EntityId, coords ...Coord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	err := removeFromCells(entityId, coords, m.cellForUpdate)
+	m.prune(coords)
+	return err
+}
+
+// GetNeighbors returns the cells one step North, East, South and West of the
+// coordinates, following the topology of the map
+func (m *sparseSpatialMap) GetNeighbors(coord Coord) []Coord {
+	return neighbors(m, coord)
+}
+
+// GetTopology returns the topology the map follows, Bounded unless set
+func (m *sparseSpatialMap) GetTopology() Topology {
+	if m.topology == nil {
+		return Bounded{}
+	}
+	return m.topology
+}
+
+func (m *sparseSpatialMap) setTopology(topology Topology) {
+	m.topology = topology
+}
+
+// GetTerrain returns the static ground under the entities, nil unless set
+func (m *sparseSpatialMap) GetTerrain() *Terrain {
+	return m.terrain
+}
+
+func (m *sparseSpatialMap) setTerrain(terrain *Terrain) {
+	m.terrain = terrain
+}
+
+func (m *sparseSpatialMap) Clone() SpatialMap {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	mClone := &sparseSpatialMap{
+		width:    m.width,
+		height:   m.height,
+		cells:    make(map[Coord]SpatialMapCell, len(m.cells)),
+		topology: m.topology,
+		terrain:  m.terrain,
+	}
+	for coord, cell := range m.cells {
+		mClone.cells[coord] = cell.Clone()
+	}
+	return mClone
+}
@@ -0,0 +1,106 @@
+package simulation
+
+import (
+	"testing"
+)
+
+func TestUnboundedMoveEntity(t *testing.T) {
+	sim := NewSimulation(0, 0, WithSparseMap())
+
+	entity, err := NewEntity("walker")
+	if err != nil {
+		t.Fatalf("Failed to create new entity: %v", err)
+	}
+	if _, err := sim.AddEntity(entity, []Coord{{X: 0, Y: 0}}, North); err != nil {
+		t.Fatalf("Failed to add entity: %v", err)
+	}
+
+	// Wrapping has no effect without bounds, the walker keeps going
+	for i := 0; i < 1000; i++ {
+		if err := sim.MoveEntity(entity.GetId(), i%2 == 0); err != nil {
+			t.Fatalf("Failed to move entity: %v", err)
+		}
+	}
+	walker, err := sim.GetEntity(entity.GetId())
+	if err != nil {
+		t.Fatalf("Failed to get entity: %v", err)
+	}
+	if coord := walker.GetPosition()[0]; coord != (Coord{X: 0, Y: -1000}) {
+		t.Fatalf("Expected the walker at (0, -1000), got %s", coord.String())
+	}
+
+	cell, err := sim.GetMap().GetCell(Coord{X: 0, Y: -1000})
+	if err != nil {
+		t.Fatalf("Failed to get cell: %v", err)
+	}
+	if ids := cell.GetEntityIds(); len(ids) != 1 || ids[0] != entity.GetId() {
+		t.Fatalf("Expected the walker in its cell, got %v", ids)
+	}
+
+	// Only the occupied cell is stored
+	if cells := len(sim.GetMap().(*sparseSpatialMap).cells); cells != 1 {
+		t.Fatalf("Expected 1 stored cell, got %d", cells)
+	}
+}
+
+func TestSparseGetBounds(t *testing.T) {
+	sim := NewSimulation(0, 0, WithSparseMap())
+
+	min, max := sim.GetMap().GetBounds()
+	if max.X >= min.X || max.Y >= min.Y {
+		t.Fatalf("Expected empty bounds for an empty map, got %s to %s", min.String(), max.String())
+	}
+
+	var entities []Entity
+	for _, coord := range []Coord{{X: -3, Y: 2}, {X: 4, Y: -1}, {X: 0, Y: 7}} {
+		e, err := NewEntity("cell")
+		if err != nil {
+			t.Fatalf("Failed to create new entity: %v", err)
+		}
+		if _, err := sim.AddEntity(e, []Coord{coord}, East); err != nil {
+			t.Fatalf("Failed to add entity: %v", err)
+		}
+		entities = append(entities, e)
+	}
+
+	min, max = sim.GetMap().GetBounds()
+	if min != (Coord{X: -3, Y: -1}) || max != (Coord{X: 4, Y: 7}) {
+		t.Fatalf("Expected bounds (-3, -1) to (4, 7), got %s to %s", min.String(), max.String())
+	}
+
+	// The bounds follow the entities as they move and leave
+	if err := sim.MoveEntity(entities[1].GetId(), false); err != nil {
+		t.Fatalf("Failed to move entity: %v", err)
+	}
+	if err := sim.RemoveEntity(entities[2].GetId()); err != nil {
+		t.Fatalf("Failed to remove entity: %v", err)
+	}
+	min, max = sim.GetMap().GetBounds()
+	if min != (Coord{X: -3, Y: -1}) || max != (Coord{X: 5, Y: 2}) {
+		t.Fatalf("Expected bounds (-3, -1) to (5, 2), got %s to %s", min.String(), max.String())
+	}
+}
+
+func TestSparseHalfBounded(t *testing.T) {
+	// A strip 3 cells high that runs on forever to the left and right
+	sm := NewSparseSpatialMap(0, 3)
+
+	tests := []struct {
+		coord   Coord
+		isValid bool
+	}{
+		{Coord{-1000, 0}, true},
+		{Coord{1000, 2}, true},
+		{Coord{0, -1}, false},
+		{Coord{0, 3}, false},
+	}
+	for _, test := range tests {
+		if sm.ValidateCoord(test.coord) != test.isValid {
+			t.Fatalf("Validation failed for coordinates %s: expected %v", test.coord.String(), test.isValid)
+		}
+	}
+
+	if neighbors := sm.GetNeighbors(Coord{X: 0, Y: 0}); len(neighbors) != 3 {
+		t.Fatalf("Expected 3 neighbors on the edge of the strip, got %v", neighbors)
+	}
+}
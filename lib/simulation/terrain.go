@@ -0,0 +1,83 @@
+package simulation
+
+import (
+	"fmt"
+	"slices"
+)
+
+/////////////////////////////////////////////////////////////////////////////////////
+// TERRAIN
+/////////////////////////////////////////////////////////////////////////////////////
+
+// Terrain is the static ground under the entities of a map, such as the walls
+// of a maze, stored as a byte per cell rather than an entity per cell. 0 is
+// open ground, the meaning of the other values is up to the caller, who says
+// which of them block movement. Cells off the terrain are open ground.
+//
+// Terrain is set up before it is handed to a map with WithTerrain, after that
+// it never changes, so clones of the map share it rather than copying it.
+type Terrain struct {
+	width, height int
+	cells         []byte
+	blocking      [256]bool // Values that block movement
+	sealed        bool      // Whether a map has it, and it can no longer change
+}
+
+// NewTerrain creates width by height cells of open ground. The blocking values
+// stop entities from moving onto the cells that have them.
+func NewTerrain(width, height int, blocking ...byte) *Terrain {
+	t := &Terrain{width: width, height: height, cells: make([]byte, width*height)}
+	for _, value := range blocking {
+		t.blocking[value] = true
+	}
+	return t
+}
+
+// inside returns whether the coordinates are on the terrain
+func (t *Terrain) inside(coord Coord) bool {
+	return coord.X >= 0 && coord.X < t.width && coord.Y >= 0 && coord.Y < t.height
+}
+
+// Set changes the terrain of a cell. It fails once the terrain is on a map.
+func (t *Terrain) Set(coord Coord, value byte) error {
+	if t.sealed {
+		return fmt.Errorf("terrain is in use by a map and can not change, change a Clone of it instead")
+	}
+	if !t.inside(coord) {
+		return fmt.Errorf("coordinates %s are off the terrain", coord.String())
+	}
+	t.cells[coord.Y*t.width+coord.X] = value
+	return nil
+}
+
+// Get returns the terrain of a cell. A nil Terrain is open ground everywhere.
+func (t *Terrain) Get(coord Coord) byte {
+	if t == nil || !t.inside(coord) {
+		return 0
+	}
+	return t.cells[coord.Y*t.width+coord.X]
+}
+
+// Blocked returns whether the terrain of a cell blocks movement
+func (t *Terrain) Blocked(coord Coord) bool {
+	if t == nil {
+		return false
+	}
+	return t.blocking[t.Get(coord)]
+}
+
+// Clone returns a copy of the terrain that can be changed, even when the
+// terrain itself is on a map
+func (t *Terrain) Clone() *Terrain {
+	return &Terrain{width: t.width, height: t.height, cells: slices.Clone(t.cells), blocking: t.blocking}
+}
+
+// WithTerrain puts static terrain under the entities of the simulation. Entities
+// can not be added to or move onto blocked terrain, which is also left out of
+// GetNeighbors. The terrain can no longer change once the simulation has it.
+func WithTerrain(terrain *Terrain) Option {
+	return func(s *simulation, width, height int) {
+		terrain.sealed = true
+		s.terrain = terrain
+	}
+}
@@ -0,0 +1,157 @@
+package simulation
+
+import (
+	"slices"
+	"testing"
+)
+
+var terrainLegend = MustNewLegend('.', map[rune]string{
+	'r': "robot",
+	'o': "box",
+}, WithTerrainRune('#', 1, true), WithTerrainRune('~', 2, false))
+
+func TestTerrain(t *testing.T) {
+	terrain := NewTerrain(3, 2, 1)
+	if err := terrain.Set(Coord{X: 2, Y: 1}, 1); err != nil {
+		t.Fatalf("Failed to set terrain: %v", err)
+	}
+	if err := terrain.Set(Coord{X: 0, Y: 1}, 2); err != nil {
+		t.Fatalf("Failed to set terrain: %v", err)
+	}
+	if err := terrain.Set(Coord{X: 3, Y: 0}, 1); err == nil {
+		t.Errorf("Expected setting terrain off the edge to fail")
+	}
+
+	var none *Terrain
+	tests := []struct {
+		name    string
+		terrain *Terrain
+		coord   Coord
+		value   byte
+		blocked bool
+	}{
+		{name: "Open ground", terrain: terrain, coord: Coord{X: 0, Y: 0}, value: 0, blocked: false},
+		{name: "Blocking terrain", terrain: terrain, coord: Coord{X: 2, Y: 1}, value: 1, blocked: true},
+		{name: "Passable terrain", terrain: terrain, coord: Coord{X: 0, Y: 1}, value: 2, blocked: false},
+		{name: "Off the terrain", terrain: terrain, coord: Coord{X: -1, Y: 1}, value: 0, blocked: false},
+		{name: "No terrain", terrain: none, coord: Coord{X: 2, Y: 1}, value: 0, blocked: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.terrain.Get(test.coord); got != test.value {
+				t.Errorf("Expected value %d, got %d", test.value, got)
+			}
+			if got := test.terrain.Blocked(test.coord); got != test.blocked {
+				t.Errorf("Expected blocked %t, got %t", test.blocked, got)
+			}
+		})
+	}
+
+	// Terrain on a map can not change, but a clone of it can
+	NewSimulation(3, 2, WithTerrain(terrain))
+	if err := terrain.Set(Coord{X: 0, Y: 0}, 1); err == nil {
+		t.Errorf("Expected terrain on a map not to change")
+	}
+	clone := terrain.Clone()
+	if err := clone.Set(Coord{X: 0, Y: 0}, 1); err != nil {
+		t.Errorf("Failed to change a clone: %v", err)
+	}
+	if terrain.Blocked(Coord{X: 0, Y: 0}) {
+		t.Errorf("Expected changing the clone to leave the terrain alone")
+	}
+}
+
+func TestSimulationTerrain(t *testing.T) {
+	forEachBackend(t, testSimulationTerrain)
+}
+
+func testSimulationTerrain(t *testing.T, b backend) {
+	lines := []string{
+		"ro#.",
+		"~.#.",
+		"....",
+	}
+	sim, err := terrainLegend.Parse(lines, b.options...)
+	if err != nil {
+		t.Fatalf("Failed to parse map: %v", err)
+	}
+
+	if value, ok := terrainLegend.Terrain('#'); !ok || value != 1 {
+		t.Errorf("Expected '#' to stand for terrain 1, got %d %t", value, ok)
+	}
+
+	// Terrain is not made of entities, but renders like the map it came from
+	if got := len(sim.GetEntities()); got != 2 {
+		t.Errorf("Expected 2 entities, got %d", got)
+	}
+	if got := terrainLegend.RenderLines(sim); !slices.Equal(got, lines) {
+		t.Errorf("Expected %v, got %v", lines, got)
+	}
+	if clone := sim.Clone(); clone.GetMap().GetTerrain() != sim.GetMap().GetTerrain() {
+		t.Errorf("Expected the clone to share the terrain")
+	}
+
+	// Entities can not be added inside a wall
+	e, _ := NewEntity("robot")
+	if _, err := sim.AddEntity(e, []Coord{{X: 2, Y: 0}}, North); err == nil {
+		t.Errorf("Expected adding an entity onto blocked terrain to fail")
+	}
+
+	// Blocked terrain is left out of the neighbors, passable terrain is not
+	got := sim.GetMap().GetNeighbors(Coord{X: 1, Y: 1})
+	if want := []Coord{{X: 1, Y: 0}, {X: 1, Y: 2}, {X: 0, Y: 1}}; !slices.Equal(got, want) {
+		t.Errorf("Expected neighbors %v, got %v", want, got)
+	}
+
+	// Pushing the box into the wall moves nothing
+	robot := sim.GetEntitiesOfType("robot")[0]
+	box := sim.GetEntitiesOfType("box")[0]
+	events := recordEvents(sim, Blocked)
+	moved, err := sim.Push(robot.GetId(), East, PushRules{"box": Pushable})
+	if err != nil || moved {
+		t.Errorf("Expected the wall to stop the push, got %t (%v)", moved, err)
+	}
+	if err := sim.MoveEntities([]EntityId{box.GetId()}, East); err == nil {
+		t.Errorf("Expected moving the box into the wall to fail")
+	}
+
+	// Moving onto blocked terrain fails, onto passable terrain it does not
+	if err := sim.SetEntityDirection(box.GetId(), East); err != nil {
+		t.Fatalf("Failed to turn: %v", err)
+	}
+	if err := sim.MoveEntity(box.GetId(), false); err == nil {
+		t.Errorf("Expected the wall to stop the box")
+	}
+	if got := len(*events); got != 2 || (*events)[0].Blocker != NilEntityId {
+		t.Errorf("Expected 2 Blocked events without a blocker, got %v", *events)
+	}
+	if err := sim.SetEntityDirection(robot.GetId(), South); err != nil {
+		t.Fatalf("Failed to turn: %v", err)
+	}
+	if err := sim.MoveEntity(robot.GetId(), false); err != nil {
+		t.Errorf("Failed to move onto passable terrain: %v", err)
+	}
+}
+
+func TestWithTerrainRuneErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []LegendOption
+	}{
+		{name: "Open ground", options: []LegendOption{WithTerrainRune('#', 0, true)}},
+		{name: "Entity rune", options: []LegendOption{WithTerrainRune('r', 1, true)}},
+		{name: "Empty rune", options: []LegendOption{WithTerrainRune('.', 1, true)}},
+		{name: "Shape rune", options: []LegendOption{WithShape("box", "[]"), WithTerrainRune(']', 1, true)}},
+		{name: "Value used twice", options: []LegendOption{WithTerrainRune('#', 1, true), WithTerrainRune('%', 1, true)}},
+		{name: "Shape over terrain", options: []LegendOption{WithTerrainRune('#', 1, true), WithShape("box", "[#")}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := NewLegend('.', map[rune]string{'r': "robot"}, test.options...); err == nil {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,392 @@
+package simulation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+/////////////////////////////////////////////////////////////////////////////////////
+// LEGEND
+/////////////////////////////////////////////////////////////////////////////////////
+
+// Legend translates between the text maps in the puzzle inputs and simulations.
+// Each rune stands for an entity type, and a shape of several runes next to
+// each other can stand for a single entity spanning several cells, like the
+// [] boxes of 2024 day 15. Runes can also stand for terrain instead of an
+// entity, see WithTerrainRune.
+type Legend struct {
+	empty        rune              // Rune for cells without entities
+	unknown      rune              // Rune for cells that can not be rendered
+	types        map[rune]string   // Rune -> entity type
+	runes        map[string]rune   // Entity type -> rune
+	shapes       map[string][]rune // Entity type -> runes, left to right
+	starts       map[rune]string   // First rune of a shape -> entity type
+	priority     map[string]int    // Entity type -> rank when sharing a cell, lowest is drawn
+	terrain      map[rune]byte     // Rune -> terrain value
+	terrainRunes map[byte]rune     // Terrain value -> rune
+	blocking     []byte            // Terrain values that block movement
+}
+
+// LegendOption configures a Legend when it is created
+type LegendOption func(l *Legend) error
+
+// NewLegend creates a legend mapping each rune to an entity type. The empty rune
+// is used for cells without entities and skipped when parsing.
+func NewLegend(empty rune, entities map[rune]string, options ...LegendOption) (*Legend, error) {
+	l := &Legend{
+		empty:        empty,
+		unknown:      '?',
+		types:        make(map[rune]string, len(entities)),
+		runes:        make(map[string]rune, len(entities)),
+		shapes:       make(map[string][]rune),
+		starts:       make(map[rune]string),
+		priority:     make(map[string]int),
+		terrain:      make(map[rune]byte),
+		terrainRunes: make(map[byte]rune),
+	}
+	for r, entityType := range entities {
+		if err := l.claim(r); err != nil {
+			return nil, err
+		}
+		if other, ok := l.runes[entityType]; ok {
+			return nil, fmt.Errorf("entity type %s has two runes, %q and %q", entityType, other, r)
+		}
+		l.types[r] = entityType
+		l.runes[entityType] = r
+	}
+	for _, option := range options {
+		if err := option(l); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// MustNewLegend is NewLegend for legends declared at package level, it panics
+// if the legend is invalid.
+func MustNewLegend(empty rune, entities map[rune]string, options ...LegendOption) *Legend {
+	l, err := NewLegend(empty, entities, options...)
+	if err != nil {
+		panic(fmt.Sprintf("invalid legend: %v", err))
+	}
+	return l
+}
+
+// WithShape maps a row of runes to an entity type spanning one cell per rune
+func WithShape(entityType string, shape string) LegendOption {
+	return func(l *Legend) error {
+		runes := []rune(shape)
+		if len(runes) == 0 {
+			return fmt.Errorf("shape for entity type %s is empty", entityType)
+		}
+		if _, ok := l.runes[entityType]; ok {
+			return fmt.Errorf("entity type %s already has a rune", entityType)
+		}
+		if _, ok := l.shapes[entityType]; ok {
+			return fmt.Errorf("entity type %s already has a shape", entityType)
+		}
+		if _, ok := l.starts[runes[0]]; ok {
+			return fmt.Errorf("rune %q already starts a shape", runes[0])
+		}
+		for _, r := range runes {
+			if r == l.empty {
+				return fmt.Errorf("shape %q uses the empty rune", shape)
+			}
+			if _, ok := l.types[r]; ok {
+				return fmt.Errorf("rune %q in shape %q is already used", r, shape)
+			}
+			if _, ok := l.terrain[r]; ok {
+				return fmt.Errorf("rune %q in shape %q is already used for terrain", r, shape)
+			}
+		}
+		l.shapes[entityType] = runes
+		l.starts[runes[0]] = entityType
+		return nil
+	}
+}
+
+// WithPriority decides which entity is drawn when several share a cell. Types
+// listed first win over types listed later, which win over unlisted types.
+// Between entities of the same rank the first one in the cell is drawn.
+func WithPriority(entityTypes ...string) LegendOption {
+	return func(l *Legend) error {
+		for i, entityType := range entityTypes {
+			l.priority[entityType] = i
+		}
+		return nil
+	}
+}
+
+// WithUnknownRune sets the rune drawn for entities whose type is not in the
+// legend, '?' by default
+func WithUnknownRune(r rune) LegendOption {
+	return func(l *Legend) error {
+		l.unknown = r
+		return nil
+	}
+}
+
+// WithTerrainRune maps a rune to a terrain value rather than an entity type,
+// for parts of the map that never move like the walls of a maze. Parse puts the
+// terrain under the simulation with WithTerrain. Value 0 is open ground, which
+// is what the empty rune stands for.
+func WithTerrainRune(r rune, value byte, blocking bool) LegendOption {
+	return func(l *Legend) error {
+		if value == 0 {
+			return fmt.Errorf("terrain value 0 is open ground, use the empty rune for it")
+		}
+		if err := l.claim(r); err != nil {
+			return err
+		}
+		for _, shape := range l.shapes {
+			if slices.Contains(shape, r) {
+				return fmt.Errorf("rune %q is already used in shape %q", r, string(shape))
+			}
+		}
+		if other, ok := l.terrainRunes[value]; ok {
+			return fmt.Errorf("terrain value %d has two runes, %q and %q", value, other, r)
+		}
+		l.terrain[r] = value
+		l.terrainRunes[value] = r
+		if blocking {
+			l.blocking = append(l.blocking, value)
+		}
+		return nil
+	}
+}
+
+// claim checks a rune is free to stand for an entity type or terrain
+func (l *Legend) claim(r rune) error {
+	if r == l.empty {
+		return fmt.Errorf("rune %q is already the empty rune", r)
+	}
+	if _, ok := l.types[r]; ok {
+		return fmt.Errorf("rune %q is already used", r)
+	}
+	if _, ok := l.terrain[r]; ok {
+		return fmt.Errorf("rune %q is already used for terrain", r)
+	}
+	return nil
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// PARSING
+/////////////////////////////////////////////////////////////////////////////////////
+
+// Parse builds a simulation the size of the map, with an entity facing North
+// for every rune or shape in it, on top of terrain for every terrain rune. Every
+// line must be as wide as the first, and every rune must be in the legend. The
+// options are passed on to NewSimulation.
+func (l *Legend) Parse(lines []string, options ...Option) (Simulation, error) {
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("map is empty")
+	}
+	rows := make([][]rune, len(lines))
+	for y, line := range lines {
+		rows[y] = []rune(line)
+		if len(rows[y]) != len(rows[0]) {
+			return nil, fmt.Errorf("line %d is %d runes wide, expected %d", y, len(rows[y]), len(rows[0]))
+		}
+	}
+
+	// Terrain has to be ready before the simulation is created, as it can not
+	// change once the simulation has it
+	if len(l.terrain) > 0 {
+		terrain := NewTerrain(len(rows[0]), len(rows), l.blocking...)
+		for y, row := range rows {
+			for x, r := range row {
+				if value, ok := l.terrain[r]; ok {
+					if err := terrain.Set(Coord{X: x, Y: y}, value); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+		options = append(slices.Clip(options), WithTerrain(terrain))
+	}
+
+	sim := NewSimulation(len(rows[0]), len(rows), options...)
+	add := func(entityType string, coords []Coord) error {
+		entity, err := NewEntity(entityType)
+		if err != nil {
+			return fmt.Errorf("error creating entity of type %s: %v", entityType, err)
+		}
+		if _, err := sim.AddEntity(entity, coords, North); err != nil {
+			return fmt.Errorf("error adding entity of type %s at %v: %v", entityType, coords, err)
+		}
+		return nil
+	}
+
+	for y, row := range rows {
+		for x := 0; x < len(row); {
+			coord := Coord{X: x, Y: y}
+			r := row[x]
+			if _, ok := l.terrain[r]; ok || r == l.empty {
+				x++
+				continue
+			}
+			if entityType, ok := l.starts[r]; ok {
+				shape := l.shapes[entityType]
+				if x+len(shape) > len(row) || !slices.Equal(row[x:x+len(shape)], shape) {
+					return nil, fmt.Errorf("incomplete %q at %s", string(shape), coord.String())
+				}
+				coords := make([]Coord, len(shape))
+				for i := range shape {
+					coords[i] = Coord{X: x + i, Y: y}
+				}
+				if err := add(entityType, coords); err != nil {
+					return nil, err
+				}
+				x += len(shape)
+				continue
+			}
+			entityType, ok := l.types[r]
+			if !ok {
+				return nil, fmt.Errorf("rune %q at %s is not in the legend", r, coord.String())
+			}
+			if err := add(entityType, []Coord{coord}); err != nil {
+				return nil, err
+			}
+			x++
+		}
+	}
+	return sim, nil
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// RENDERING
+/////////////////////////////////////////////////////////////////////////////////////
+
+// Mask overlays runes on a rendered map, for example to draw a path
+type Mask map[Coord]rune
+
+// PathMask draws the same rune on every coordinate of a path
+func PathMask(path []Coord, r rune) Mask {
+	mask := make(Mask, len(path))
+	for _, coord := range path {
+		mask[coord] = r
+	}
+	return mask
+}
+
+// Render draws the simulation as text, one line per row each ending in a
+// newline, so it can be compared with a map copied from the puzzle. Masks are
+// drawn over the entities in order, so later masks win.
+func (l *Legend) Render(sim Simulation, masks ...Mask) string {
+	var sb strings.Builder
+	for _, line := range l.RenderLines(sim, masks...) {
+		sb.WriteString(line)
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// RenderLines draws the simulation as text, one string per row. The unbounded
+// axes of a sparse map are drawn as far as its entities reach.
+func (l *Legend) RenderLines(sim Simulation, masks ...Mask) []string {
+	m := sim.GetMap()
+	min, max := m.GetBounds()
+	if m.GetWidth() > 0 {
+		min.X, max.X = 0, m.GetWidth()-1
+	}
+	if m.GetHeight() > 0 {
+		min.Y, max.Y = 0, m.GetHeight()-1
+	}
+	entities := make(map[EntityId]Entity) // Looked up once, even when spanning many cells
+	lines := make([]string, 0, max.Y-min.Y+1)
+	for y := min.Y; y <= max.Y; y++ {
+		row := make([]rune, 0, max.X-min.X+1)
+		for x := min.X; x <= max.X; x++ {
+			coord := Coord{X: x, Y: y}
+			r := l.cellRune(sim, coord, entities)
+			for _, mask := range masks {
+				if masked, ok := mask[coord]; ok {
+					r = masked
+				}
+			}
+			row = append(row, r)
+		}
+		lines = append(lines, string(row))
+	}
+	return lines
+}
+
+// cellRune returns the rune of the entity drawn at the coordinates, or of the
+// terrain under them when there is none
+func (l *Legend) cellRune(sim Simulation, coord Coord, entities map[EntityId]Entity) rune {
+	cell, err := sim.GetMap().GetCell(coord)
+	if err != nil {
+		return l.unknown
+	}
+	var drawn Entity
+	for _, entityId := range cell.GetEntityIds() {
+		entity, ok := entities[entityId]
+		if !ok {
+			if entity, err = sim.GetEntity(entityId); err != nil {
+				return l.unknown
+			}
+			entities[entityId] = entity
+		}
+		if drawn == nil || l.rank(entity.GetEntityType()) < l.rank(drawn.GetEntityType()) {
+			drawn = entity
+		}
+	}
+	if drawn == nil {
+		value := sim.GetMap().GetTerrain().Get(coord)
+		if value == 0 {
+			return l.empty
+		}
+		if r, ok := l.terrainRunes[value]; ok {
+			return r
+		}
+		return l.unknown
+	}
+	entityType := drawn.GetEntityType()
+	if shape, ok := l.shapes[entityType]; ok {
+		// Shapes are drawn left to right, whatever order the cells were added in
+		left := coord.X
+		for _, c := range drawn.GetPosition() {
+			if c.Y == coord.Y && c.X < left {
+				left = c.X
+			}
+		}
+		if i := coord.X - left; i < len(shape) {
+			return shape[i]
+		}
+		return l.unknown
+	}
+	if r, ok := l.runes[entityType]; ok {
+		return r
+	}
+	return l.unknown
+}
+
+// EntityType returns the entity type a rune stands for, including the runes of
+// shapes, so rendered lines can be colored by type
+func (l *Legend) EntityType(r rune) (string, bool) {
+	if entityType, ok := l.types[r]; ok {
+		return entityType, true
+	}
+	for entityType, shape := range l.shapes {
+		if slices.Contains(shape, r) {
+			return entityType, true
+		}
+	}
+	return "", false
+}
+
+// Terrain returns the terrain value a rune stands for, so rendered lines can be
+// colored by terrain too
+func (l *Legend) Terrain(r rune) (byte, bool) {
+	value, ok := l.terrain[r]
+	return value, ok
+}
+
+// rank orders entity types by WithPriority, unlisted types come last
+func (l *Legend) rank(entityType string) int {
+	if rank, ok := l.priority[entityType]; ok {
+		return rank
+	}
+	return len(l.priority)
+}
@@ -0,0 +1,160 @@
+package simulation
+
+import (
+	"strings"
+	"testing"
+)
+
+var testLegend = MustNewLegend('.', map[rune]string{
+	'#': "wall",
+	'@': "robot",
+	'S': "start",
+}, WithShape("box", "[]"), WithPriority("robot", "start"))
+
+func TestLegendRoundTrip(t *testing.T) {
+	forEachBackend(t, testLegendRoundTrip)
+}
+
+func testLegendRoundTrip(t *testing.T, b backend) {
+	layout := []string{
+		"########",
+		"#S.[]..#",
+		"#.[][]@#",
+		"########",
+	}
+	sim, err := testLegend.Parse(layout, b.options...)
+	if err != nil {
+		t.Fatalf("Failed to parse map: %v", err)
+	}
+
+	counts := map[string]int{}
+	for _, e := range sim.GetEntities() {
+		counts[e.GetEntityType()]++
+	}
+	if counts["wall"] != 20 || counts["box"] != 3 || counts["robot"] != 1 || counts["start"] != 1 {
+		t.Errorf("Unexpected entity counts: %v", counts)
+	}
+
+	expected := strings.Join(layout, "\n") + "\n"
+	if got := testLegend.Render(sim); got != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, got)
+	}
+}
+
+func TestLegendPriorityAndMasks(t *testing.T) {
+	sim, err := testLegend.Parse([]string{"S...", "...."})
+	if err != nil {
+		t.Fatalf("Failed to parse map: %v", err)
+	}
+	// A robot and a wall both on the start tile, the robot wins
+	for _, entityType := range []string{"wall", "robot"} {
+		e, err := NewEntity(entityType)
+		if err != nil {
+			t.Fatalf("Failed to create entity: %v", err)
+		}
+		if _, err := sim.AddEntity(e, []Coord{{X: 0, Y: 0}}, North); err != nil {
+			t.Fatalf("Failed to add entity: %v", err)
+		}
+	}
+	// Unknown types are drawn with the unknown rune
+	e, err := NewEntity("ghost")
+	if err != nil {
+		t.Fatalf("Failed to create entity: %v", err)
+	}
+	if _, err := sim.AddEntity(e, []Coord{{X: 3, Y: 1}}, North); err != nil {
+		t.Fatalf("Failed to add entity: %v", err)
+	}
+
+	if got := testLegend.Render(sim); got != "@...\n...?\n" {
+		t.Errorf("Expected the robot on top and the ghost unknown, got\n%s", got)
+	}
+
+	path := PathMask([]Coord{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}}, 'O')
+	end := Mask{{X: 1, Y: 1}: 'E'}
+	if got := testLegend.RenderLines(sim, path, end); strings.Join(got, "|") != "OO..|.E.?" {
+		t.Errorf("Expected masks drawn over entities with the last one on top, got %v", got)
+	}
+}
+
+func TestLegendUnboundedSparseMap(t *testing.T) {
+	sim := NewSimulation(0, 0, WithSparseMap())
+	for _, coord := range []Coord{{X: -2, Y: 5}, {X: 1, Y: 6}} {
+		e, err := NewEntity("wall")
+		if err != nil {
+			t.Fatalf("Failed to create entity: %v", err)
+		}
+		if _, err := sim.AddEntity(e, []Coord{coord}, North); err != nil {
+			t.Fatalf("Failed to add entity: %v", err)
+		}
+	}
+	// Only the area the entities reach is drawn
+	if got := testLegend.Render(sim); got != "#...\n...#\n" {
+		t.Errorf("Expected the occupied area, got\n%s", got)
+	}
+}
+
+func TestLegendEntityType(t *testing.T) {
+	tests := []struct {
+		r      rune
+		want   string
+		wantOk bool
+	}{
+		{r: '#', want: "wall", wantOk: true},
+		{r: '[', want: "box", wantOk: true},
+		{r: ']', want: "box", wantOk: true},
+		{r: '.', wantOk: false},
+		{r: 'x', wantOk: false},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.r), func(t *testing.T) {
+			got, ok := testLegend.EntityType(test.r)
+			if got != test.want || ok != test.wantOk {
+				t.Errorf("Expected %q %v, got %q %v", test.want, test.wantOk, got, ok)
+			}
+		})
+	}
+}
+
+func TestLegendParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+	}{
+		{name: "Empty", lines: []string{}},
+		{name: "Ragged", lines: []string{"...", ".."}},
+		{name: "Unknown rune", lines: []string{".x."}},
+		{name: "Half a box", lines: []string{".[."}},
+		{name: "Box off the edge", lines: []string{"..["}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := testLegend.Parse(test.lines); err == nil {
+				t.Errorf("Expected an error parsing %v", test.lines)
+			}
+		})
+	}
+}
+
+func TestNewLegendErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		entities map[rune]string
+		options  []LegendOption
+	}{
+		{name: "Empty rune used", entities: map[rune]string{'.': "floor"}},
+		{name: "Type with two runes", entities: map[rune]string{'#': "wall", 'X': "wall"}},
+		{name: "Shape reuses a rune", entities: map[rune]string{'#': "wall"}, options: []LegendOption{WithShape("box", "#]")}},
+		{name: "Shape for a type with a rune", entities: map[rune]string{'#': "wall"}, options: []LegendOption{WithShape("wall", "[]")}},
+		{name: "Empty shape", options: []LegendOption{WithShape("box", "")}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := NewLegend('.', test.entities, test.options...); err == nil {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package simulation
+
+import "fmt"
+
+/////////////////////////////////////////////////////////////////////////////////////
+// TOPOLOGY
+/////////////////////////////////////////////////////////////////////////////////////
+
+// Topology decides where a step from a cell in a direction leads, and which way
+// the entity taking it faces afterwards. Steps between cells on the map usually
+// lead to the next cell, it is the steps off the edge that tell topologies
+// apart. Step returns false when the step can not be taken. A width or height
+// of 0 is an unbounded axis of a sparse map, which has no edge to step off.
+type Topology interface {
+	Step(from Coord, direction Direction, width, height int) (to Coord, facing Direction, ok bool)
+}
+
+// inside returns whether the coordinates are on a width by height map
+func inside(c Coord, width, height int) bool {
+	return (width == 0 || (c.X >= 0 && c.X < width)) && (height == 0 || (c.Y >= 0 && c.Y < height))
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// wrap brings a value back onto an axis of a size, unless it is unbounded
+func wrap(v, size int) int {
+	if size == 0 {
+		return v
+	}
+	return ((v % size) + size) % size
+}
+
+// Bounded stops every step off the edge of the map. It is the topology maps
+// start with.
+type Bounded struct{}
+
+func (Bounded) Step(from Coord, direction Direction, width, height int) (Coord, Direction, bool) {
+	to := from.Move(direction)
+	return to, direction, inside(to, width, height)
+}
+
+// Torus wraps steps off any edge around to the other side
+type Torus struct{}
+
+func (Torus) Step(from Coord, direction Direction, width, height int) (Coord, Direction, bool) {
+	to := from.Move(direction)
+	return Coord{X: wrap(to.X, width), Y: wrap(to.Y, height)}, direction, true
+}
+
+// WrapHorizontal wraps steps off the left and right edges around to the other
+// side, and stops steps off the top and bottom
+type WrapHorizontal struct{}
+
+func (WrapHorizontal) Step(from Coord, direction Direction, width, height int) (Coord, Direction, bool) {
+	to := from.Move(direction)
+	to.X = wrap(to.X, width)
+	return to, direction, inside(to, width, height)
+}
+
+// WrapVertical wraps steps off the top and bottom edges around to the other
+// side, and stops steps off the left and right
+type WrapVertical struct{}
+
+func (WrapVertical) Step(from Coord, direction Direction, width, height int) (Coord, Direction, bool) {
+	to := from.Move(direction)
+	to.Y = wrap(to.Y, height)
+	return to, direction, inside(to, width, height)
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// PORTALS
+/////////////////////////////////////////////////////////////////////////////////////
+
+// PortalEnd is one way into a portal, stepping from a cell in a direction
+type PortalEnd struct {
+	At    Coord
+	Enter Direction
+}
+
+// PortalPair links two ends of a portal. Entering one end leads to the cell of
+// the other, facing away from it.
+type PortalPair struct {
+	Name string
+	A, B PortalEnd
+}
+
+// Portals is a topology with portals on top of another one. Steps that enter a
+// portal lead to its other end, every other step is left to the base topology.
+// Portal ends can be anywhere on the map, not only at the edges.
+type Portals struct {
+	base  Topology
+	pairs map[string]PortalPair
+	exits map[PortalEnd]PortalEnd // Entrance -> the end it leads to
+}
+
+// NewPortals adds portals to a base topology. Each end can only be used by one
+// portal, and the names must be unique.
+func NewPortals(base Topology, pairs ...PortalPair) (*Portals, error) {
+	p := &Portals{
+		base:  base,
+		pairs: make(map[string]PortalPair, len(pairs)),
+		exits: make(map[PortalEnd]PortalEnd, 2*len(pairs)),
+	}
+	for _, pair := range pairs {
+		if _, ok := p.pairs[pair.Name]; ok {
+			return nil, fmt.Errorf("portal %s is defined twice", pair.Name)
+		}
+		for _, end := range []PortalEnd{pair.A, pair.B} {
+			if _, ok := p.exits[end]; ok {
+				return nil, fmt.Errorf("portal %s enters from %s going %s, which another portal already does", pair.Name, end.At.String(), end.Enter)
+			}
+		}
+		if pair.A == pair.B {
+			return nil, fmt.Errorf("portal %s leads to itself", pair.Name)
+		}
+		p.pairs[pair.Name] = pair
+		p.exits[pair.A] = pair.B
+		p.exits[pair.B] = pair.A
+	}
+	return p, nil
+}
+
+// Pair returns the portal with a name
+func (p *Portals) Pair(name string) (PortalPair, bool) {
+	pair, ok := p.pairs[name]
+	return pair, ok
+}
+
+func (p *Portals) Step(from Coord, direction Direction, width, height int) (Coord, Direction, bool) {
+	if exit, ok := p.exits[PortalEnd{At: from, Enter: direction}]; ok {
+		return exit.At, exit.Enter.Rotate(2), true
+	}
+	return p.base.Step(from, direction, width, height)
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// CUBE
+/////////////////////////////////////////////////////////////////////////////////////
+
+// vec3 is a direction or point in the space the cube is folded in
+type vec3 [3]int
+
+func (v vec3) add(o vec3) vec3  { return vec3{v[0] + o[0], v[1] + o[1], v[2] + o[2]} }
+func (v vec3) scale(k int) vec3 { return vec3{v[0] * k, v[1] * k, v[2] * k} }
+func (v vec3) neg() vec3        { return v.scale(-1) }
+func (v vec3) dot(o vec3) int   { return v[0]*o[0] + v[1]*o[1] + v[2]*o[2] }
+
+// cubeFace is a face of the cube and the way it sits in space: its outward
+// normal and the directions East and South on the net point in
+type cubeFace struct {
+	at                  Coord // Top left cell of the face on the net
+	normal, right, down vec3
+}
+
+// Cube folds a net of six square faces into a cube, the way 2022 day 22 part 2
+// walks it. Stepping off the edge of a face leads onto the face that edge
+// folds onto, which may be anywhere on the net and turn the entity around.
+// Cells that are not on a face can not be stepped to or from.
+type Cube struct {
+	size     int
+	faces    []cubeFace
+	byNet    map[Coord]int // Face position on the net, in faces -> face
+	byNormal map[vec3]int  // Outward normal -> face
+}
+
+// NewCube folds a net with faces size cells wide. Faces are given by their
+// position on the net counted in faces, so {X: 1, Y: 0} is the face whose top
+// left cell is (size, 0). There must be six of them, connected edge to edge
+// in a shape that folds into a cube.
+func NewCube(size int, faces []Coord) (*Cube, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("faces of a cube must be at least 1 cell wide, got %d", size)
+	}
+	if len(faces) != 6 {
+		return nil, fmt.Errorf("a cube has 6 faces, got %d", len(faces))
+	}
+	c := &Cube{size: size, byNet: make(map[Coord]int), byNormal: make(map[vec3]int)}
+	for i, face := range faces {
+		if _, ok := c.byNet[face]; ok {
+			return nil, fmt.Errorf("face %s is given twice", face.String())
+		}
+		c.byNet[face] = i
+	}
+
+	// Fold outwards from the first face. Folding over an edge turns the face
+	// about that edge, so the normal becomes the direction of the edge and the
+	// direction across the edge points back along the old normal.
+	c.faces = make([]cubeFace, len(faces))
+	placed := make([]bool, len(faces))
+	c.faces[0] = cubeFace{normal: vec3{0, 0, 1}, right: vec3{1, 0, 0}, down: vec3{0, 1, 0}}
+	placed[0] = true
+	queue := []int{0}
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		f := c.faces[i]
+		for _, d := range []Direction{North, East, South, West} {
+			j, ok := c.byNet[faces[i].Move(d)]
+			if !ok || placed[j] {
+				continue
+			}
+			next := f
+			switch d {
+			case East:
+				next.normal, next.right = f.right, f.normal.neg()
+			case West:
+				next.normal, next.right = f.right.neg(), f.normal
+			case South:
+				next.normal, next.down = f.down, f.normal.neg()
+			case North:
+				next.normal, next.down = f.down.neg(), f.normal
+			}
+			c.faces[j] = next
+			placed[j] = true
+			queue = append(queue, j)
+		}
+	}
+	for i, face := range faces {
+		if !placed[i] {
+			return nil, fmt.Errorf("face %s is not connected to the others", face.String())
+		}
+		c.faces[i].at = Coord{X: face.X * size, Y: face.Y * size}
+		if other, ok := c.byNormal[c.faces[i].normal]; ok {
+			return nil, fmt.Errorf("faces %s and %s fold onto the same side of the cube", faces[other].String(), face.String())
+		}
+		c.byNormal[c.faces[i].normal] = i
+	}
+	return c, nil
+}
+
+// faceOf returns the face holding a cell of the net
+func (c *Cube) faceOf(coord Coord) (int, bool) {
+	if coord.X < 0 || coord.Y < 0 {
+		return 0, false
+	}
+	i, ok := c.byNet[Coord{X: coord.X / c.size, Y: coord.Y / c.size}]
+	return i, ok
+}
+
+// axis returns the direction on a face's net that points along v in space
+func (f cubeFace) axis(v vec3) Direction {
+	switch v {
+	case f.right:
+		return East
+	case f.right.neg():
+		return West
+	case f.down:
+		return South
+	case f.down.neg():
+		return North
+	}
+	return Direction{}
+}
+
+// inSpace returns the vector in space a direction on the face's net points along
+func (f cubeFace) inSpace(d Direction) vec3 {
+	return f.right.scale(d.VX).add(f.down.scale(d.VY))
+}
+
+func (c *Cube) Step(from Coord, direction Direction, width, height int) (Coord, Direction, bool) {
+	i, ok := c.faceOf(from)
+	if !ok {
+		return from, direction, false
+	}
+	to := from.Move(direction)
+	if j, ok := c.faceOf(to); ok && j == i {
+		return to, direction, true
+	}
+	f := c.faces[i]
+	if abs(direction.VX)+abs(direction.VY) != 1 {
+		return from, direction, false // Only single steps in the four straight directions cross edges
+	}
+	across := f.inSpace(direction)
+
+	// Cell centres in space sit on odd coordinates of a cube reaching from -size
+	// to size. Stepping over an edge moves the point onto the next face and
+	// one step in from its edge, and turns it to head away from the old face.
+	n := c.size
+	p := f.normal.scale(n).add(f.right.scale(2*(from.X-f.at.X) - n + 1)).add(f.down.scale(2*(from.Y-f.at.Y) - n + 1))
+	p = p.add(across).add(f.normal.neg())
+	next := c.faces[c.byNormal[across]]
+	to = Coord{
+		X: next.at.X + (next.right.dot(p)+n-1)/2,
+		Y: next.at.Y + (next.down.dot(p)+n-1)/2,
+	}
+	return to, next.axis(f.normal.neg()), true
+}
@@ -0,0 +1,322 @@
+package simulation
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTopologyStep(t *testing.T) {
+	tests := []struct {
+		name          string
+		topology      Topology
+		from          Coord
+		direction     Direction
+		width, height int
+		want          Coord
+		wantOk        bool
+	}{
+		{name: "Bounded inside", topology: Bounded{}, from: Coord{X: 1, Y: 1}, direction: East, width: 4, height: 3, want: Coord{X: 2, Y: 1}, wantOk: true},
+		{name: "Bounded edge", topology: Bounded{}, from: Coord{X: 3, Y: 1}, direction: East, width: 4, height: 3, wantOk: false},
+		{name: "Bounded unbounded axis", topology: Bounded{}, from: Coord{X: 3, Y: 1}, direction: East, width: 0, height: 3, want: Coord{X: 4, Y: 1}, wantOk: true},
+		{name: "Torus right edge", topology: Torus{}, from: Coord{X: 3, Y: 1}, direction: East, width: 4, height: 3, want: Coord{X: 0, Y: 1}, wantOk: true},
+		{name: "Torus top edge", topology: Torus{}, from: Coord{X: 1, Y: 0}, direction: North, width: 4, height: 3, want: Coord{X: 1, Y: 2}, wantOk: true},
+		{name: "Horizontal wraps left", topology: WrapHorizontal{}, from: Coord{X: 0, Y: 1}, direction: West, width: 4, height: 3, want: Coord{X: 3, Y: 1}, wantOk: true},
+		{name: "Horizontal stops at the bottom", topology: WrapHorizontal{}, from: Coord{X: 0, Y: 2}, direction: South, width: 4, height: 3, wantOk: false},
+		{name: "Vertical wraps down", topology: WrapVertical{}, from: Coord{X: 2, Y: 2}, direction: South, width: 4, height: 3, want: Coord{X: 2, Y: 0}, wantOk: true},
+		{name: "Vertical stops at the right", topology: WrapVertical{}, from: Coord{X: 3, Y: 2}, direction: East, width: 4, height: 3, wantOk: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, facing, ok := test.topology.Step(test.from, test.direction, test.width, test.height)
+			if ok != test.wantOk {
+				t.Fatalf("Expected ok %v, got %v", test.wantOk, ok)
+			}
+			if ok && (got != test.want || facing != test.direction) {
+				t.Errorf("Expected %s facing %s, got %s facing %s", test.want.String(), test.direction, got.String(), facing)
+			}
+		})
+	}
+}
+
+func TestPortals(t *testing.T) {
+	portals, err := NewPortals(Bounded{}, PortalPair{
+		Name: "AB",
+		A:    PortalEnd{At: Coord{X: 1, Y: 1}, Enter: North},
+		B:    PortalEnd{At: Coord{X: 3, Y: 2}, Enter: East},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create portals: %v", err)
+	}
+
+	// Both ways through, coming out facing away from the other end
+	if to, facing, ok := portals.Step(Coord{X: 1, Y: 1}, North, 4, 3); !ok || to != (Coord{X: 3, Y: 2}) || facing != West {
+		t.Errorf("Expected (3, 2) facing West, got %s facing %s (%v)", to.String(), facing, ok)
+	}
+	if to, facing, ok := portals.Step(Coord{X: 3, Y: 2}, East, 4, 3); !ok || to != (Coord{X: 1, Y: 1}) || facing != South {
+		t.Errorf("Expected (1, 1) facing South, got %s facing %s (%v)", to.String(), facing, ok)
+	}
+	// Other steps from the same cell are left to the base topology
+	if to, _, ok := portals.Step(Coord{X: 1, Y: 1}, East, 4, 3); !ok || to != (Coord{X: 2, Y: 1}) {
+		t.Errorf("Expected a plain step to (2, 1), got %s (%v)", to.String(), ok)
+	}
+	if pair, ok := portals.Pair("AB"); !ok || pair.B.Enter != East {
+		t.Errorf("Expected to look up the portal by name")
+	}
+
+	end := PortalEnd{At: Coord{X: 0, Y: 0}, Enter: West}
+	if _, err := NewPortals(Bounded{}, PortalPair{Name: "A", A: end, B: end}); err == nil {
+		t.Errorf("Expected a portal leading to itself to fail")
+	}
+	other := PortalEnd{At: Coord{X: 2, Y: 0}, Enter: North}
+	if _, err := NewPortals(Bounded{}, PortalPair{Name: "A", A: end, B: other}, PortalPair{Name: "B", A: other, B: PortalEnd{}}); err == nil {
+		t.Errorf("Expected two portals sharing an end to fail")
+	}
+}
+
+// The net of the 2022 day 22 example, and the net most inputs used
+//
+//	  #        ##
+//	###        #
+//	  ##      ##
+//	          #
+var (
+	exampleNet = []Coord{{X: 2, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}, {X: 2, Y: 2}, {X: 3, Y: 2}}
+	inputNet   = []Coord{{X: 1, Y: 0}, {X: 2, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 2}, {X: 1, Y: 2}, {X: 0, Y: 3}}
+)
+
+func TestCube(t *testing.T) {
+	cube, err := NewCube(4, exampleNet)
+	if err != nil {
+		t.Fatalf("Failed to fold cube: %v", err)
+	}
+
+	// The two edge crossings walked through in the puzzle
+	tests := []struct {
+		name      string
+		from      Coord
+		direction Direction
+		want      Coord
+		facing    Direction
+	}{
+		{name: "A to B", from: Coord{X: 11, Y: 5}, direction: East, want: Coord{X: 14, Y: 8}, facing: South},
+		{name: "C to D", from: Coord{X: 10, Y: 11}, direction: South, want: Coord{X: 1, Y: 7}, facing: North},
+		{name: "Across a net edge", from: Coord{X: 3, Y: 5}, direction: East, want: Coord{X: 4, Y: 5}, facing: East},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, facing, ok := cube.Step(test.from, test.direction, 0, 0)
+			if !ok || got != test.want || facing != test.facing {
+				t.Errorf("Expected %s facing %s, got %s facing %s (%v)", test.want.String(), test.facing, got.String(), facing, ok)
+			}
+		})
+	}
+
+	if _, _, ok := cube.Step(Coord{X: 0, Y: 0}, East, 0, 0); ok {
+		t.Errorf("Expected cells off the net to go nowhere")
+	}
+}
+
+func TestCubeWalks(t *testing.T) {
+	for name, net := range map[string][]Coord{"example": exampleNet, "input": inputNet} {
+		t.Run(name, func(t *testing.T) {
+			const size = 3
+			cube, err := NewCube(size, net)
+			if err != nil {
+				t.Fatalf("Failed to fold cube: %v", err)
+			}
+			for _, face := range net {
+				for y := face.Y * size; y < (face.Y+1)*size; y++ {
+					for x := face.X * size; x < (face.X+1)*size; x++ {
+						from := Coord{X: x, Y: y}
+						for _, direction := range []Direction{North, East, South, West} {
+							// Turning around after a step leads straight back
+							to, facing, ok := cube.Step(from, direction, 0, 0)
+							if !ok {
+								t.Fatalf("Expected a step from %s going %s", from.String(), direction)
+							}
+							back, _, _ := cube.Step(to, facing.Rotate(2), 0, 0)
+							if back != from {
+								t.Errorf("Expected to step back from %s to %s, got %s", to.String(), from.String(), back.String())
+							}
+
+							// Going straight ahead goes around the cube and back
+							at, heading := from, direction
+							for i := 0; i < 4*size; i++ {
+								at, heading, _ = cube.Step(at, heading, 0, 0)
+							}
+							if at != from || heading != direction {
+								t.Errorf("Expected to go around the cube from %s going %s, ended at %s going %s", from.String(), direction, at.String(), heading)
+							}
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestNewCubeErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		size  int
+		faces []Coord
+	}{
+		{name: "No size", size: 0, faces: exampleNet},
+		{name: "Five faces", size: 4, faces: exampleNet[:5]},
+		{name: "Face given twice", size: 4, faces: append(slices.Clone(exampleNet[:5]), exampleNet[0])},
+		{name: "Not connected", size: 4, faces: []Coord{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 0, Y: 5}, {X: 1, Y: 5}, {X: 2, Y: 5}}},
+		{name: "Strip overlaps itself", size: 4, faces: []Coord{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 3, Y: 0}, {X: 4, Y: 0}, {X: 5, Y: 0}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := NewCube(test.size, test.faces); err == nil {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+}
+
+func TestSimulationTopology(t *testing.T) {
+	forEachBackend(t, testSimulationTopology)
+}
+
+func testSimulationTopology(t *testing.T, b backend) {
+	sim, err := queryLegend.Parse([]string{"r...", "....", "...#"}, append(b.options, WithTopology(WrapHorizontal{}))...)
+	if err != nil {
+		t.Fatalf("Failed to parse map: %v", err)
+	}
+
+	// Neighbors wrap left and right but not up and down
+	got := sim.GetMap().GetNeighbors(Coord{X: 0, Y: 0})
+	if want := []Coord{{X: 1, Y: 0}, {X: 0, Y: 1}, {X: 3, Y: 0}}; !slices.Equal(got, want) {
+		t.Errorf("Expected neighbors %v, got %v", want, got)
+	}
+	if _, ok := sim.Clone().GetMap().GetTopology().(WrapHorizontal); !ok {
+		t.Errorf("Expected the clone to keep the topology")
+	}
+
+	// Moving without wrapping follows the topology
+	robot := sim.GetEntitiesOfType("robot")[0]
+	if err := sim.SetEntityDirection(robot.GetId(), West); err != nil {
+		t.Fatalf("Failed to turn: %v", err)
+	}
+	if err := sim.MoveEntity(robot.GetId(), false); err != nil {
+		t.Fatalf("Failed to move: %v", err)
+	}
+	if e, _ := sim.GetEntity(robot.GetId()); e.GetPosition()[0] != (Coord{X: 3, Y: 0}) {
+		t.Errorf("Expected the robot to wrap to (3, 0), got %v", e.GetPosition())
+	}
+	if err := sim.SetEntityDirection(robot.GetId(), North); err != nil {
+		t.Fatalf("Failed to turn: %v", err)
+	}
+	if err := sim.MoveEntity(robot.GetId(), false); err == nil {
+		t.Errorf("Expected the top edge to stop the robot")
+	}
+}
+
+func TestPushWithTopology(t *testing.T) {
+	forEachBackend(t, testPushWithTopology)
+}
+
+func testPushWithTopology(t *testing.T, b backend) {
+	portals, err := NewPortals(WrapHorizontal{}, PortalPair{
+		Name: "AB",
+		A:    PortalEnd{At: Coord{X: 0, Y: 2}, Enter: South},
+		B:    PortalEnd{At: Coord{X: 2, Y: 2}, Enter: South},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create portals: %v", err)
+	}
+	sim, err := queryLegend.Parse([]string{"g..r", "....", "r.g."}, append(b.options, WithTopology(portals))...)
+	if err != nil {
+		t.Fatalf("Failed to parse map: %v", err)
+	}
+	rules := PushRules{"guard": Pushable}
+	robots := sim.GetEntitiesOfType("robot")
+	guards := sim.GetEntitiesOfType("guard")
+	events := recordEvents(sim, Moved, Wrapped)
+
+	// Pushing off the right edge wraps around onto the guard on the left
+	if moved, err := sim.Push(robots[0].GetId(), East, rules); err != nil || !moved {
+		t.Fatalf("Expected the push to wrap, got %t (%v)", moved, err)
+	}
+	if got := positions(sim.GetEntities()); !slices.Equal(got, []Coord{{X: 1, Y: 0}, {X: 0, Y: 0}, {X: 0, Y: 2}, {X: 2, Y: 2}}) {
+		t.Errorf("Expected the robot to wrap and push the guard, got %v", got)
+	}
+	if got := kinds(*events); !slices.Equal(got, []EventKind{Moved, Wrapped, Moved}) {
+		t.Errorf("Expected the robot to wrap, got %v", got)
+	}
+
+	// Pushing into the portal pushes the guard at the other end out of it, North
+	if err := sim.SetEntityDirection(robots[1].GetId(), East); err != nil {
+		t.Fatalf("Failed to turn: %v", err)
+	}
+	if moved, err := sim.Push(robots[1].GetId(), South, rules); err != nil || !moved {
+		t.Fatalf("Expected the push to go through the portal, got %t (%v)", moved, err)
+	}
+	if got := positions(sim.GetEntities()); !slices.Equal(got, []Coord{{X: 1, Y: 0}, {X: 0, Y: 0}, {X: 2, Y: 2}, {X: 2, Y: 1}}) {
+		t.Errorf("Expected the guard pushed North out of the portal, got %v", got)
+	}
+	if e, _ := sim.GetEntity(robots[1].GetId()); e.GetDirection() != East {
+		t.Errorf("Expected pushing to leave the robot facing East, got %s", e.GetDirection())
+	}
+
+	// MoveEntities follows the topology too, and stops at the top edge
+	if err := sim.MoveEntities([]EntityId{guards[0].GetId()}, West); err != nil {
+		t.Fatalf("Failed to move: %v", err)
+	}
+	if e, _ := sim.GetEntity(guards[0].GetId()); e.GetPosition()[0] != (Coord{X: 0, Y: 0}) {
+		t.Errorf("Expected the guard back at (0, 0), got %v", e.GetPosition())
+	}
+	if err := sim.MoveEntities([]EntityId{guards[0].GetId()}, North); err == nil {
+		t.Errorf("Expected the top edge to stop the guard")
+	}
+}
+
+func TestMoveEntityTurnsWithTopology(t *testing.T) {
+	portals, err := NewPortals(Bounded{}, PortalPair{
+		Name: "AB",
+		A:    PortalEnd{At: Coord{X: 0, Y: 0}, Enter: West},
+		B:    PortalEnd{At: Coord{X: 2, Y: 2}, Enter: South},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create portals: %v", err)
+	}
+	sim := NewSimulation(3, 3, WithTopology(portals))
+	history, err := NewHistory(sim)
+	if err != nil {
+		t.Fatalf("Failed to create history: %v", err)
+	}
+	events := recordEvents(sim)
+	e, _ := NewEntity("robot")
+	robot, err := sim.AddEntity(e, []Coord{{X: 0, Y: 0}}, West)
+	if err != nil {
+		t.Fatalf("Failed to add robot: %v", err)
+	}
+
+	if err := sim.MoveEntity(robot.GetId(), false); err != nil {
+		t.Fatalf("Failed to move: %v", err)
+	}
+	moved, _ := sim.GetEntity(robot.GetId())
+	if moved.GetPosition()[0] != (Coord{X: 2, Y: 2}) || moved.GetDirection() != North {
+		t.Errorf("Expected the robot at (2, 2) facing North, got %v facing %s", moved.GetPosition(), moved.GetDirection())
+	}
+	if got := kinds(*events); !slices.Equal(got, []EventKind{Spawned, Moved, Wrapped, Turned}) {
+		t.Errorf("Expected the portal to wrap and turn the robot, got %v", got)
+	}
+
+	// Undoing the move turns the robot back too
+	if _, err := history.Undo(1); err != nil {
+		t.Fatalf("Failed to undo: %v", err)
+	}
+	if _, err := sim.GetEntity(robot.GetId()); err == nil {
+		t.Errorf("Expected undoing tick 0 to remove the robot")
+	}
+	if _, err := history.Redo(1); err != nil {
+		t.Fatalf("Failed to redo: %v", err)
+	}
+	if redone, _ := sim.GetEntity(robot.GetId()); redone.GetDirection() != North {
+		t.Errorf("Expected the robot facing North again, got %s", redone.GetDirection())
+	}
+}
@@ -0,0 +1,230 @@
+// Package xy keeps the original x/y flavoured simulation API alive on top of
+// the shared simulation package. Days written before the simulation moved to
+// Coord and Direction (day8, day10, day14) import it under the name
+// "simulation" so their call sites did not have to change.
+package xy
+
+import (
+	"fmt"
+
+	"2ajoyce/adventofcode/lib/simulation"
+)
+
+/////////////////////////////////////////////////////////////////////////////////////
+// ERRORS
+/////////////////////////////////////////////////////////////////////////////////////
+
+type CellEmptyError = simulation.CellEmptyError
+
+/////////////////////////////////////////////////////////////////////////////////////
+// SPATIAL MAP
+/////////////////////////////////////////////////////////////////////////////////////
+
+type SpatialMap interface {
+	GetCell(x, y int) (simulation.SpatialMapCell, error)
+	GetHeight() int
+	GetWidth() int
+	GetIndex(x, y int) int
+	ValidateCoord(x, y int) bool
+}
+
+type spatialMap struct {
+	inner simulation.SpatialMap
+}
+
+func (m *spatialMap) GetCell(x, y int) (simulation.SpatialMapCell, error) {
+	return m.inner.GetCell(simulation.Coord{X: x, Y: y})
+}
+
+func (m *spatialMap) GetHeight() int {
+	return m.inner.GetHeight()
+}
+
+func (m *spatialMap) GetWidth() int {
+	return m.inner.GetWidth()
+}
+
+func (m *spatialMap) GetIndex(x, y int) int {
+	return m.inner.GetIndex(simulation.Coord{X: x, Y: y})
+}
+
+func (m *spatialMap) ValidateCoord(x, y int) bool {
+	return m.inner.ValidateCoord(simulation.Coord{X: x, Y: y})
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// ENTITY
+/////////////////////////////////////////////////////////////////////////////////////
+
+// Entity is a single cell entity. The velocity of the old API is stored as the
+// entity's Direction in the shared simulation. Once added, an entity reads its
+// position and velocity from the simulation, so it follows the entity around
+// the way the original implementation did.
+type Entity interface {
+	GetId() simulation.EntityId
+	GetPosition() (x int, y int)
+	GetVelocity() (xv int, yv int)
+	unwrap() simulation.Entity
+	attach(sim simulation.Simulation)
+}
+
+type entity struct {
+	inner simulation.Entity
+	sim   simulation.Simulation // Set once the entity is added
+}
+
+// EntityType is the type of every entity in the shared simulation, the x/y API
+// has no entity types
+const EntityType = ""
+
+func NewEntity() (Entity, error) {
+	e, err := simulation.NewEntity(EntityType)
+	if err != nil {
+		return nil, err
+	}
+	return &entity{inner: e}, nil
+}
+
+func (e *entity) GetId() simulation.EntityId {
+	return e.inner.GetId()
+}
+
+// current returns the entity as the simulation has it now, or as it was last
+// seen if it is not in a simulation
+func (e *entity) current() simulation.Entity {
+	if e.sim != nil {
+		if live, err := e.sim.GetEntity(e.inner.GetId()); err == nil {
+			return live
+		}
+	}
+	return e.inner
+}
+
+func (e *entity) GetPosition() (x int, y int) {
+	coords := e.current().GetPosition()
+	if len(coords) == 0 {
+		return 0, 0
+	}
+	return coords[0].X, coords[0].Y
+}
+
+func (e *entity) GetVelocity() (xv int, yv int) {
+	direction := e.current().GetDirection()
+	return direction.VX, direction.VY
+}
+
+func (e *entity) unwrap() simulation.Entity {
+	return e.inner
+}
+
+func (e *entity) attach(sim simulation.Simulation) {
+	e.sim = sim
+}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// SIMULATION
+/////////////////////////////////////////////////////////////////////////////////////
+
+type Simulation interface {
+	AddEntity(e Entity, x, y int, xv, yv int) (Entity, error)
+	GetEntity(entityId simulation.EntityId) (Entity, error)
+	GetEntities() []Entity
+	MoveEntity(entityId simulation.EntityId, newX, newY int) (bool, error)
+	RemoveEntity(entityId simulation.EntityId) (bool, error)
+	CountPerRegion(regions []simulation.Rect) []int
+	GetMap() SpatialMap
+	Unwrap() simulation.Simulation
+}
+
+type xySimulation struct {
+	inner     simulation.Simulation
+	entities  []Entity
+	entityMap map[simulation.EntityId]int // EntityID -> index in Entities slice
+}
+
+func NewSimulation(width, height int) Simulation {
+	s := new(xySimulation)
+	s.inner = simulation.NewSimulation(width, height)
+	s.entities = make([]Entity, 0)
+	s.entityMap = make(map[simulation.EntityId]int)
+	return s
+}
+
+// Unwrap returns the shared simulation underneath, for the code that only
+// works with it, like Legend and playback
+func (s *xySimulation) Unwrap() simulation.Simulation {
+	return s.inner
+}
+
+func (s *xySimulation) GetMap() SpatialMap {
+	return &spatialMap{inner: s.inner.GetMap()}
+}
+
+// AddEntity keeps hold of e itself so that days embedding Entity in their own
+// types can type switch on the values returned by GetEntity and GetEntities.
+func (s *xySimulation) AddEntity(e Entity, x, y int, xv, yv int) (Entity, error) {
+	_, err := s.inner.AddEntity(e.unwrap(), []simulation.Coord{{X: x, Y: y}}, simulation.Direction{VX: xv, VY: yv})
+	if err != nil {
+		return nil, err
+	}
+	e.attach(s.inner)
+	s.entities = append(s.entities, e)
+	s.entityMap[e.GetId()] = len(s.entities) - 1
+	return e, nil
+}
+
+func (s *xySimulation) GetEntity(entityId simulation.EntityId) (Entity, error) {
+	index, ok := s.entityMap[entityId]
+	if !ok {
+		return nil, fmt.Errorf("entity with ID %s not found", entityId)
+	}
+	return s.entities[index], nil
+}
+
+// GetEntities returns a copy of the current list of entities.
+func (s *xySimulation) GetEntities() []Entity {
+	entitiesCopy := make([]Entity, len(s.entities))
+	copy(entitiesCopy, s.entities)
+	return entitiesCopy
+}
+
+// MoveEntity places the entity at (newX, newY), wrapping the coordinates around
+// the edges of the map the same way the original implementation did. The
+// velocity of the entity is left as it is.
+func (s *xySimulation) MoveEntity(entityId simulation.EntityId, newX, newY int) (bool, error) {
+	width := s.inner.GetMap().GetWidth()
+	height := s.inner.GetMap().GetHeight()
+	wrappedX := ((newX % width) + width) % width
+	wrappedY := ((newY % height) + height) % height
+
+	if err := s.inner.PlaceEntity(entityId, []simulation.Coord{{X: wrappedX, Y: wrappedY}}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CountPerRegion counts the entities inside each region, see
+// simulation.Quadrants for the usual regions
+func (s *xySimulation) CountPerRegion(regions []simulation.Rect) []int {
+	return s.inner.CountPerRegion(regions)
+}
+
+func (s *xySimulation) RemoveEntity(entityId simulation.EntityId) (bool, error) {
+	index, ok := s.entityMap[entityId]
+	if !ok {
+		return false, fmt.Errorf("entity with ID %v not found", entityId)
+	}
+	if err := s.inner.RemoveEntity(entityId); err != nil {
+		return false, err
+	}
+
+	// Remove the entity from the slice and update the map
+	lastIndex := len(s.entities) - 1
+	if index != lastIndex {
+		s.entities[index] = s.entities[lastIndex]
+		s.entityMap[s.entities[lastIndex].GetId()] = index
+	}
+	delete(s.entityMap, entityId)
+	s.entities = s.entities[:lastIndex]
+	return true, nil
+}
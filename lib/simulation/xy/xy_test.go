@@ -0,0 +1,113 @@
+package xy
+
+import (
+	"slices"
+	"testing"
+
+	"2ajoyce/adventofcode/lib/simulation"
+)
+
+func TestAddAndGetEntity(t *testing.T) {
+	sim := NewSimulation(5, 5)
+	entity, err := NewEntity()
+	if err != nil {
+		t.Fatalf("Failed to create entity: %v", err)
+	}
+
+	if _, err := sim.AddEntity(entity, 2, 3, 1, -1); err != nil {
+		t.Fatalf("Failed to add entity: %v", err)
+	}
+
+	retrieved, err := sim.GetEntity(entity.GetId())
+	if err != nil {
+		t.Fatalf("Failed to get entity: %v", err)
+	}
+	if x, y := retrieved.GetPosition(); x != 2 || y != 3 {
+		t.Errorf("Expected position (2, 3), got (%d, %d)", x, y)
+	}
+	if xv, yv := retrieved.GetVelocity(); xv != 1 || yv != -1 {
+		t.Errorf("Expected velocity (1, -1), got (%d, %d)", xv, yv)
+	}
+
+	cell, err := sim.GetMap().GetCell(2, 3)
+	if err != nil {
+		t.Fatalf("Failed to get cell: %v", err)
+	}
+	if ids := cell.GetEntityIds(); len(ids) != 1 || ids[0] != entity.GetId() {
+		t.Errorf("Expected cell to contain entity %s, got %v", entity.GetId(), ids)
+	}
+}
+
+func TestMoveEntityWraps(t *testing.T) {
+	sim := NewSimulation(5, 5)
+	entity, err := NewEntity()
+	if err != nil {
+		t.Fatalf("Failed to create entity: %v", err)
+	}
+	if _, err := sim.AddEntity(entity, 4, 0, 2, 3); err != nil {
+		t.Fatalf("Failed to add entity: %v", err)
+	}
+	var events []simulation.Event
+	sim.Unwrap().Subscribe(func(e simulation.Event) { events = append(events, e) })
+
+	moved, err := sim.MoveEntity(entity.GetId(), 6, -1)
+	if err != nil || !moved {
+		t.Fatalf("Failed to move entity: %v", err)
+	}
+
+	retrieved, err := sim.GetEntity(entity.GetId())
+	if err != nil {
+		t.Fatalf("Failed to get entity: %v", err)
+	}
+	if x, y := retrieved.GetPosition(); x != 1 || y != 4 {
+		t.Errorf("Expected wrapped position (1, 4), got (%d, %d)", x, y)
+	}
+	if xv, yv := retrieved.GetVelocity(); xv != 2 || yv != 3 {
+		t.Errorf("Expected velocity to be preserved as (2, 3), got (%d, %d)", xv, yv)
+	}
+
+	oldCell, _ := sim.GetMap().GetCell(4, 0)
+	if !oldCell.IsEmpty() {
+		t.Errorf("Expected old cell to be empty after move")
+	}
+	if len(events) != 1 || events[0].Kind != simulation.Moved {
+		t.Errorf("Expected a single Moved event, got %v", events)
+	}
+}
+
+func TestRemoveEntity(t *testing.T) {
+	sim := NewSimulation(3, 3)
+	entity, err := NewEntity()
+	if err != nil {
+		t.Fatalf("Failed to create entity: %v", err)
+	}
+	if _, err := sim.AddEntity(entity, 1, 1, 0, 0); err != nil {
+		t.Fatalf("Failed to add entity: %v", err)
+	}
+
+	removed, err := sim.RemoveEntity(entity.GetId())
+	if err != nil || !removed {
+		t.Fatalf("Failed to remove entity: %v", err)
+	}
+	if len(sim.GetEntities()) != 0 {
+		t.Errorf("Expected no entities after removal, got %d", len(sim.GetEntities()))
+	}
+}
+
+func TestCountPerRegion(t *testing.T) {
+	sim := NewSimulation(5, 5)
+	for _, position := range [][2]int{{0, 0}, {1, 1}, {2, 2}, {4, 0}, {3, 4}} {
+		entity, err := NewEntity()
+		if err != nil {
+			t.Fatalf("Failed to create entity: %v", err)
+		}
+		if _, err := sim.AddEntity(entity, position[0], position[1], 0, 0); err != nil {
+			t.Fatalf("Failed to add entity: %v", err)
+		}
+	}
+
+	counts := sim.CountPerRegion(simulation.Quadrants(5, 5))
+	if want := []int{2, 1, 0, 1}; !slices.Equal(counts, want) {
+		t.Errorf("Expected %v, the entity in the middle in no quadrant, got %v", want, counts)
+	}
+}
@@ -0,0 +1,162 @@
+// Package vec holds the integer vectors every day ends up needing: positions
+// and directions on a 2D grid, and points in 3D space.
+//
+// Like the lines of an input, Y grows downwards, so North is (0, -1) and
+// turning right takes North to East.
+package vec
+
+import (
+	"fmt"
+	"math"
+)
+
+/////////////////////////////////////////////////////////////////////////////////////
+// VEC2
+/////////////////////////////////////////////////////////////////////////////////////
+
+type Vec2 struct {
+	X, Y int
+}
+
+func (v Vec2) String() string {
+	return fmt.Sprintf("(%d, %d)", v.X, v.Y)
+}
+
+func (v Vec2) Add(o Vec2) Vec2 {
+	return Vec2{X: v.X + o.X, Y: v.Y + o.Y}
+}
+
+func (v Vec2) Sub(o Vec2) Vec2 {
+	return Vec2{X: v.X - o.X, Y: v.Y - o.Y}
+}
+
+func (v Vec2) Scale(k int) Vec2 {
+	return Vec2{X: v.X * k, Y: v.Y * k}
+}
+
+// Manhattan is the number of orthogonal steps between v and o.
+func (v Vec2) Manhattan(o Vec2) int {
+	return abs(v.X-o.X) + abs(v.Y-o.Y)
+}
+
+// Chebyshev is the number of steps between v and o when diagonal steps are
+// allowed, like a king on a chessboard.
+func (v Vec2) Chebyshev(o Vec2) int {
+	return max(abs(v.X-o.X), abs(v.Y-o.Y))
+}
+
+// Euclidean is the straight line distance between v and o.
+func (v Vec2) Euclidean(o Vec2) float64 {
+	return math.Hypot(float64(v.X-o.X), float64(v.Y-o.Y))
+}
+
+// RotateRight rotates v a quarter turn clockwise around the origin.
+func (v Vec2) RotateRight() Vec2 {
+	return Vec2{X: -v.Y, Y: v.X}
+}
+
+// RotateLeft rotates v a quarter turn counter-clockwise around the origin.
+func (v Vec2) RotateLeft() Vec2 {
+	return Vec2{X: v.Y, Y: -v.X}
+}
+
+// Rotate rotates v by the given number of quarter turns around the origin.
+// Positive turns are clockwise, negative turns counter-clockwise.
+func (v Vec2) Rotate(quarterTurns int) Vec2 {
+	switch ((quarterTurns % 4) + 4) % 4 {
+	case 1:
+		return v.RotateRight()
+	case 2:
+		return Vec2{X: -v.X, Y: -v.Y}
+	case 3:
+		return v.RotateLeft()
+	default:
+		return v
+	}
+}
+
+// Turn rotates one of Directions8 by the given number of eighth turns, so a
+// single step turns North into NorthEast. Positive steps are clockwise.
+// It returns false if v is not one of Directions8.
+func (v Vec2) Turn(eighths int) (Vec2, bool) {
+	for i, d := range Directions8 {
+		if d == v {
+			return Directions8[(((i+eighths)%8)+8)%8], true
+		}
+	}
+	return v, false
+}
+
+// The unit steps on a grid
+var (
+	North     = Vec2{X: 0, Y: -1}
+	NorthEast = Vec2{X: 1, Y: -1}
+	East      = Vec2{X: 1, Y: 0}
+	SouthEast = Vec2{X: 1, Y: 1}
+	South     = Vec2{X: 0, Y: 1}
+	SouthWest = Vec2{X: -1, Y: 1}
+	West      = Vec2{X: -1, Y: 0}
+	NorthWest = Vec2{X: -1, Y: -1}
+)
+
+// Directions4 are the orthogonal steps, clockwise from North.
+var Directions4 = []Vec2{North, East, South, West}
+
+// Directions8 are the orthogonal and diagonal steps, clockwise from North.
+var Directions8 = []Vec2{North, NorthEast, East, SouthEast, South, SouthWest, West, NorthWest}
+
+/////////////////////////////////////////////////////////////////////////////////////
+// VEC3
+/////////////////////////////////////////////////////////////////////////////////////
+
+type Vec3 struct {
+	X, Y, Z int
+}
+
+func (v Vec3) String() string {
+	return fmt.Sprintf("(%d, %d, %d)", v.X, v.Y, v.Z)
+}
+
+func (v Vec3) Add(o Vec3) Vec3 {
+	return Vec3{X: v.X + o.X, Y: v.Y + o.Y, Z: v.Z + o.Z}
+}
+
+func (v Vec3) Sub(o Vec3) Vec3 {
+	return Vec3{X: v.X - o.X, Y: v.Y - o.Y, Z: v.Z - o.Z}
+}
+
+func (v Vec3) Scale(k int) Vec3 {
+	return Vec3{X: v.X * k, Y: v.Y * k, Z: v.Z * k}
+}
+
+// Manhattan is the number of axis aligned steps between v and o.
+func (v Vec3) Manhattan(o Vec3) int {
+	return abs(v.X-o.X) + abs(v.Y-o.Y) + abs(v.Z-o.Z)
+}
+
+// Chebyshev is the largest difference along any one axis.
+func (v Vec3) Chebyshev(o Vec3) int {
+	return max(abs(v.X-o.X), abs(v.Y-o.Y), abs(v.Z-o.Z))
+}
+
+// Euclidean is the straight line distance between v and o.
+func (v Vec3) Euclidean(o Vec3) float64 {
+	dx := float64(v.X - o.X)
+	dy := float64(v.Y - o.Y)
+	dz := float64(v.Z - o.Z)
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
+
+// Directions6 are the steps to the six faces of a cube: along X, then Y, then Z.
+var Directions6 = []Vec3{
+	{X: 1}, {X: -1},
+	{Y: 1}, {Y: -1},
+	{Z: 1}, {Z: -1},
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,141 @@
+package vec
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVec2Arithmetic(t *testing.T) {
+	a := Vec2{X: 3, Y: -2}
+	b := Vec2{X: -1, Y: 5}
+
+	if got := a.Add(b); got != (Vec2{X: 2, Y: 3}) {
+		t.Errorf("Add: expected (2, 3), got %s", got)
+	}
+	if got := a.Sub(b); got != (Vec2{X: 4, Y: -7}) {
+		t.Errorf("Sub: expected (4, -7), got %s", got)
+	}
+	if got := a.Scale(-2); got != (Vec2{X: -6, Y: 4}) {
+		t.Errorf("Scale: expected (-6, 4), got %s", got)
+	}
+}
+
+func TestDistances(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      Vec2
+		manhattan int
+		chebyshev int
+		euclidean float64
+	}{
+		{name: "Same point", a: Vec2{X: 1, Y: 1}, b: Vec2{X: 1, Y: 1}},
+		{name: "Straight line", a: Vec2{X: 0, Y: 0}, b: Vec2{X: 0, Y: -4}, manhattan: 4, chebyshev: 4, euclidean: 4},
+		{name: "Diagonal", a: Vec2{X: -1, Y: -1}, b: Vec2{X: 2, Y: 3}, manhattan: 7, chebyshev: 4, euclidean: 5},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.a.Manhattan(test.b); got != test.manhattan {
+				t.Errorf("Manhattan: expected %d, got %d", test.manhattan, got)
+			}
+			if got := test.a.Chebyshev(test.b); got != test.chebyshev {
+				t.Errorf("Chebyshev: expected %d, got %d", test.chebyshev, got)
+			}
+			if got := test.a.Euclidean(test.b); math.Abs(got-test.euclidean) > 1e-9 {
+				t.Errorf("Euclidean: expected %f, got %f", test.euclidean, got)
+			}
+		})
+	}
+
+	a := Vec3{X: 1, Y: 2, Z: 3}
+	b := Vec3{X: 4, Y: -2, Z: 3}
+	if got := a.Manhattan(b); got != 7 {
+		t.Errorf("Vec3 Manhattan: expected 7, got %d", got)
+	}
+	if got := a.Chebyshev(b); got != 4 {
+		t.Errorf("Vec3 Chebyshev: expected 4, got %d", got)
+	}
+	if got := a.Euclidean(b); got != 5 {
+		t.Errorf("Vec3 Euclidean: expected 5, got %f", got)
+	}
+}
+
+func TestRotate(t *testing.T) {
+	tests := []struct {
+		v            Vec2
+		quarterTurns int
+		expected     Vec2
+	}{
+		{North, 1, East},
+		{East, 1, South},
+		{West, 1, North},
+		{North, -1, West},
+		{North, 2, South},
+		{North, 3, West},
+		{North, -7, East},
+		{NorthEast, 1, SouthEast},
+		{Vec2{X: 2, Y: -1}, 1, Vec2{X: 1, Y: 2}},
+		{Vec2{X: 2, Y: -1}, 4, Vec2{X: 2, Y: -1}},
+	}
+
+	for _, test := range tests {
+		if got := test.v.Rotate(test.quarterTurns); got != test.expected {
+			t.Errorf("%s rotated %d: expected %s, got %s", test.v, test.quarterTurns, test.expected, got)
+		}
+	}
+
+	// Turning right and then left gives every direction back
+	for _, d := range Directions8 {
+		if got := d.RotateRight().RotateLeft(); got != d {
+			t.Errorf("Expected turning %s right and left to give it back, got %s", d, got)
+		}
+	}
+}
+
+func TestTurn(t *testing.T) {
+	tests := []struct {
+		v        Vec2
+		eighths  int
+		expected Vec2
+		ok       bool
+	}{
+		{North, 1, NorthEast, true},
+		{North, -1, NorthWest, true},
+		{SouthWest, 3, North, true},
+		{East, 2, South, true},
+		{East, -10, North, true},
+		{Vec2{X: 2, Y: 0}, 1, Vec2{X: 2, Y: 0}, false},
+	}
+
+	for _, test := range tests {
+		got, ok := test.v.Turn(test.eighths)
+		if got != test.expected || ok != test.ok {
+			t.Errorf("%s turned %d: expected %s, %v, got %s, %v", test.v, test.eighths, test.expected, test.ok, got, ok)
+		}
+	}
+}
+
+func TestDirectionSets(t *testing.T) {
+	// Every direction set adds up to nothing, each step has its opposite
+	var sum2 Vec2
+	for _, d := range Directions8 {
+		sum2 = sum2.Add(d)
+		if got := d.Chebyshev(Vec2{}); got != 1 {
+			t.Errorf("Expected %s to be one step from the origin, got %d", d, got)
+		}
+	}
+	if sum2 != (Vec2{}) {
+		t.Errorf("Expected Directions8 to add up to the origin, got %s", sum2)
+	}
+
+	var sum3 Vec3
+	for _, d := range Directions6 {
+		sum3 = sum3.Add(d)
+		if got := d.Manhattan(Vec3{}); got != 1 {
+			t.Errorf("Expected %s to be one step from the origin, got %d", d, got)
+		}
+	}
+	if sum3 != (Vec3{}) {
+		t.Errorf("Expected Directions6 to add up to the origin, got %s", sum3)
+	}
+}